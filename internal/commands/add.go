@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// addFlagValues holds the flags describing the entity added by `add`.
+type addFlagValues struct {
+	description string
+	params      []string
+	updates     []string
+	resType     string
+	uriTemplate string
+	args        []string
+	enabled     bool
+}
+
+// NewAddCmd creates the `add` cobra command, which adds a tool, resource,
+// prompt or capability to a project created by generate.
+func NewAddCmd() *cobra.Command {
+	opts := &handlers.AddOptions{}
+	flags := &addFlagValues{}
+
+	cmd := &cobra.Command{
+		Use:   "add tool|resource|prompt|capability [name]",
+		Short: "Add a tool, resource, prompt or capability to a generated project.",
+		Long: `Add extends a project created by generate, whose language is read from configs/mcp-config.json.
+It creates the source file of the new tool, resource or capability, regenerates the registry of its
+kind and appends it to the config. Files are never overwritten: a registry edited since it was
+generated is left as is and the regenerated one is written next to it with a .new suffix.
+Without a name, the definition is asked for interactively.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("specify what to add: %v", handlers.AddKinds)
+			}
+			opts.Kind = args[0]
+			name := ""
+			if len(args) > 1 {
+				name = args[1]
+			}
+			if err := buildAddEntity(opts, name, flags); err != nil {
+				return err
+			}
+			return handlers.RunAdd(opts, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Dir, "dir", "", ".", "Project directory")
+	cmd.Flags().StringVarP(&flags.description, "description", "", "", "Description of the tool or prompt")
+	cmd.Flags().StringArrayVar(&flags.params, "param", nil, "Tool parameter as name:type or name:type:required (repeatable)")
+	cmd.Flags().StringArrayVar(&flags.updates, "updates", nil, "Resource the tool changes, whose subscribers are notified after each call (repeatable)")
+	cmd.Flags().StringVarP(&flags.resType, "type", "", string(core.ResourceTypeFilesystem), "Resource type (database, filesystem, time)")
+	cmd.Flags().StringVarP(&flags.uriTemplate, "uri-template", "", "", "URI template making the resource a resource template")
+	cmd.Flags().StringArrayVar(&flags.args, "arg", nil, "Prompt argument as name or name:required (repeatable)")
+	cmd.Flags().BoolVar(&flags.enabled, "enabled", true, "Enable the capability")
+
+	return cmd
+}
+
+// buildAddEntity fills the entity of opts from the flags, or asks for it
+// interactively when no name is given.
+func buildAddEntity(opts *handlers.AddOptions, name string, flags *addFlagValues) error {
+	switch opts.Kind {
+	case "tool":
+		if name == "" {
+			opts.Tool = askTool()
+			return nil
+		}
+		opts.Tool = core.Tool{Name: name, Description: flags.description, Updates: flags.updates}
+		for _, spec := range flags.params {
+			p, err := parseToolParameter(spec)
+			if err != nil {
+				return err
+			}
+			opts.Tool.Parameters = append(opts.Tool.Parameters, p)
+		}
+	case "resource":
+		if name == "" {
+			opts.Resource = askResource()
+			return nil
+		}
+		opts.Resource = core.Resource{Name: name, Type: flags.resType, URITemplate: flags.uriTemplate}
+	case "prompt":
+		if name == "" {
+			opts.Prompt = askPrompt()
+			return nil
+		}
+		opts.Prompt = core.Prompt{Name: name, Description: flags.description}
+		for _, spec := range flags.args {
+			argName, required, err := splitRequired(spec, 1)
+			if err != nil {
+				return fmt.Errorf("invalid --arg %q: %w", spec, err)
+			}
+			opts.Prompt.Arguments = append(opts.Prompt.Arguments, core.PromptArgument{Name: argName[0], Required: required})
+		}
+	case "capability":
+		if name == "" {
+			opts.Capability = askCapability()
+			return nil
+		}
+		opts.Capability = core.Capability{Name: name, Enabled: flags.enabled}
+	default:
+		return fmt.Errorf("invalid kind: %s, valid options are: %v", opts.Kind, handlers.AddKinds)
+	}
+	return nil
+}
+
+// parseToolParameter parses a --param flag given as name:type or
+// name:type:required.
+func parseToolParameter(spec string) (core.ToolParameter, error) {
+	parts, required, err := splitRequired(spec, 2)
+	if err != nil {
+		return core.ToolParameter{}, fmt.Errorf("invalid --param %q: %w", spec, err)
+	}
+	return core.ToolParameter{Name: parts[0], Type: parts[1], Required: required}, nil
+}
+
+// splitRequired splits a colon separated flag value into n fields, optionally
+// followed by "required".
+func splitRequired(spec string, n int) ([]string, bool, error) {
+	parts := strings.Split(spec, ":")
+	required := false
+	if len(parts) == n+1 && parts[n] == "required" {
+		parts, required = parts[:n], true
+	}
+	if len(parts) != n {
+		return nil, false, fmt.Errorf("expected %d field(s) separated by ':', optionally followed by :required", n)
+	}
+	for _, p := range parts {
+		if p == "" {
+			return nil, false, fmt.Errorf("empty field")
+		}
+	}
+	return parts, required, nil
+}
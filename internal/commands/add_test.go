@@ -0,0 +1,75 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/handlers"
+)
+
+func TestAddCmd(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := handlers.GenerateProject(&handlers.GenerateOptions{Name: "proj", Language: "javascript", Transport: "stdio", Output: dir}); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewAddCmd()
+	cmd.SetArgs([]string{"--dir", dir, "--description", "Searches", "--param", "query:string:required", "--param", "limit:integer"})
+	if err := cmd.RunE(cmd, []string{"tool", "search"}); err != nil {
+		t.Fatalf("add tool failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "src", "tools", "search.js")); err != nil {
+		t.Errorf("tool file not created: %v", err)
+	}
+	config, err := handlers.LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Tools) != 1 || len(config.Tools[0].Parameters) != 2 || !config.Tools[0].Parameters[0].Required {
+		t.Errorf("unexpected tools in config: %+v", config.Tools)
+	}
+
+	cmd = NewAddCmd()
+	cmd.SetArgs([]string{"--dir", dir, "--param", "query"})
+	if err := cmd.RunE(cmd, []string{"tool", "broken"}); err == nil || !strings.Contains(err.Error(), "invalid --param") {
+		t.Errorf("expected an invalid --param error, got %v", err)
+	}
+	cmd = NewAddCmd()
+	if err := cmd.RunE(cmd, nil); err == nil {
+		t.Error("expected an error without a kind")
+	}
+}
+
+func TestAddCmd_Interactive(t *testing.T) {
+	origAskOne, origAsk := survey.AskOne, survey.Ask
+	defer func() { survey.AskOne = origAskOne; survey.Ask = origAsk }()
+	survey.AskOne = func(p interface{}, r interface{}, _ ...interface{}) error { return nil }
+	survey.Ask = func(qs interface{}, resp interface{}, _ ...interface{}) error {
+		if p, ok := resp.(*core.Prompt); ok {
+			p.Name = "greet"
+		}
+		return nil
+	}
+	opts := &handlers.AddOptions{Kind: "prompt"}
+	if err := buildAddEntity(opts, "", &addFlagValues{}); err != nil {
+		t.Fatal(err)
+	}
+	if opts.Prompt.Name != "greet" {
+		t.Errorf("prompt not asked for interactively: %+v", opts.Prompt)
+	}
+}
+
+func TestParseToolParameter(t *testing.T) {
+	p, err := parseToolParameter("city:string:required")
+	if err != nil || p.Name != "city" || p.Type != "string" || !p.Required {
+		t.Errorf("parseToolParameter = %+v, %v", p, err)
+	}
+	for _, bad := range []string{"city", "city:string:optional", ":string"} {
+		if _, err := parseToolParameter(bad); err == nil {
+			t.Errorf("expected an error for %q", bad)
+		}
+	}
+}
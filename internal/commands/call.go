@@ -0,0 +1,35 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewCallCmd creates the `call` cobra command.
+func NewCallCmd() *cobra.Command {
+	opts := &handlers.CallOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "call <tool>",
+		Short: "Call a tool of an MCP server and print the result.",
+		Long: `Call starts or connects to an MCP server, calls one tool and prints the result. Arguments are given
+as --arg key=value, where values are read as JSON when valid and as strings otherwise, or as a JSON
+object with --json-args. The command fails when the server returns an error or the tool reports one.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("a tool name is required")
+			}
+			return handlers.RunCall(opts, args[0], os.Stdout)
+		},
+	}
+
+	addClientFlags(cmd, &opts.ClientOptions)
+	cmd.Flags().StringArrayVar(&opts.Args, "arg", nil, "Tool argument as key=value (repeatable)")
+	cmd.Flags().StringVarP(&opts.JSONArgs, "json-args", "", "", "File holding the tool arguments as a JSON object (- for stdin)")
+
+	return cmd
+}
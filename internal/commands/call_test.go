@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestOneShotCmds_RequireServer(t *testing.T) {
+	for _, args := range [][]string{{"call", "echo"}, {"read", "file:///greeting.txt"}, {"list", "tools"}} {
+		root := MakeRootCommand(version)
+		root.SetArgs(args)
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--config or --command") {
+			t.Errorf("%v: expected missing server error, got %v", args, err)
+		}
+	}
+}
+
+func TestCallCmd_ServerError(t *testing.T) {
+	root := MakeRootCommand(version)
+	root.SetArgs([]string{"call", "echo", "--command", mcptest.Command(t), "--arg", "message=1"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "(code -32602)") {
+		t.Errorf("expected the server error, got %v", err)
+	}
+}
+
+func TestListCmd_InvalidKind(t *testing.T) {
+	root := MakeRootCommand(version)
+	root.SetArgs([]string{"list", "widgets", "--command", "true"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), `cannot list "widgets"`) {
+		t.Errorf("expected an invalid kind error, got %v", err)
+	}
+}
@@ -0,0 +1,16 @@
+package commands
+
+import (
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// addClientFlags defines the flags shared by the one-shot commands.
+func addClientFlags(cmd *cobra.Command, opts *handlers.ClientOptions) {
+	cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "Path to MCP configuration file")
+	cmd.Flags().StringVarP(&opts.Command, "command", "", "", "Command starting a stdio MCP server, instead of --config")
+	cmd.Flags().StringVarP(&opts.Output, "output", "o", handlers.OutputJSON, "Output format (json, yaml, table)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", handlers.DefaultTestTimeout, "Timeout for each request sent to the server (0 disables it)")
+	cmd.Flags().StringVarP(&opts.ProtocolVersion, "protocol-version", "", core.LatestProtocolVersion, "MCP protocol version requested during initialization")
+}
@@ -13,18 +13,31 @@ import (
 // server projects. It sets up flags, validation, and interactive prompts.
 func NewGenerateCmd() *cobra.Command {
 	opts := &handlers.GenerateOptions{}
+	var from string
 
 	cmd := &cobra.Command{
 		Use:     "generate [name]",
 		Aliases: []string{"gen", "g"},
 		Short:   "Generate a new MCP server project",
 		Long: `Generate scaffolds a new MCP server project with the specified configuration.
-Supports multiple languages, transport methods, and includes optional Docker support.`,
+Supports multiple languages, transport methods, and includes optional Docker support.
+With --from, the project is described by a YAML or JSON spec file; flags and
+the name argument override the values it sets. With --dry-run or --diff, the
+project is only previewed.`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) > 0 {
 				opts.Name = args[0]
 			}
+			if from != "" {
+				spec, err := core.LoadProjectSpec(from)
+				if err != nil {
+					return fmt.Errorf("invalid spec: %w", err)
+				}
+				opts.DockerSet = cmd.Flags().Changed("docker")
+				opts.ExamplesSet = cmd.Flags().Changed("examples")
+				handlers.ApplyProjectSpec(opts, spec)
+			}
 			opts.Interactive = needsInteractiveMode(opts)
 			if opts.Interactive {
 				if err := promptForOptions(opts); err != nil {
@@ -39,6 +52,7 @@ Supports multiple languages, transport methods, and includes optional Docker sup
 	}
 
 	addFlags(cmd, opts)
+	cmd.Flags().StringVarP(&from, "from", "", "", "Generate from a YAML or JSON project spec file")
 	return cmd
 }
 
@@ -46,11 +60,13 @@ Supports multiple languages, transport methods, and includes optional Docker sup
 func addFlags(cmd *cobra.Command, opts *handlers.GenerateOptions) {
 	cmd.Flags().StringVarP(&opts.Name, "name", "n", "", "MCP project name")
 	cmd.Flags().StringVarP(&opts.Language, "language", "l", "", "Programming language (e.g., golang, python, java)")
-	cmd.Flags().StringVarP(&opts.Transport, "transport", "t", "", "Transport method (e.g., stdio, rest, websocket)")
+	cmd.Flags().StringVarP(&opts.Transport, "transport", "t", "", "Transport method (e.g., stdio, rest, streamable-http, websocket)")
 	cmd.Flags().BoolVarP(&opts.Docker, "docker", "d", false, "Include Docker support")
 	cmd.Flags().BoolVarP(&opts.Examples, "examples", "e", false, "Include example resources and tools")
 	cmd.Flags().StringVarP(&opts.Output, "output", "o", "", "Output directory (default to project name)")
 	cmd.Flags().BoolVarP(&opts.Force, "force", "f", false, "Overwrite existing directory")
+	cmd.Flags().BoolVar(&opts.DryRun, "dry-run", false, "List the files that would be generated without writing them")
+	cmd.Flags().BoolVar(&opts.Diff, "diff", false, "Show a unified diff against the output directory without writing it")
 }
 
 // needsInteractiveMode checks if the options are incomplete and requires user input.
@@ -69,6 +85,9 @@ func promptForOptions(opts *handlers.GenerateOptions) error {
 	if err := promptForResources(opts); err != nil {
 		return err
 	}
+	if err := promptForPrompts(opts); err != nil {
+		return err
+	}
 	return promptForCapabilities(opts)
 }
 
@@ -104,7 +123,7 @@ func buildBasicQuestions(opts *handlers.GenerateOptions) []*survey.Question {
 		qs = append(qs, &survey.Question{Name: "language", Prompt: &survey.Select{Message: "Select programming language:", Options: []string{"golang", "python", "java", "javascript"}, Default: "golang"}, Validate: survey.Required})
 	}
 	if opts.Transport == "" {
-		qs = append(qs, &survey.Question{Name: "transport", Prompt: &survey.Select{Message: "Choose transport method:", Options: []string{"stdio", "rest", "websocket"}, Default: "stdio"}})
+		qs = append(qs, &survey.Question{Name: "transport", Prompt: &survey.Select{Message: "Choose transport method:", Options: []string{"stdio", "rest", "streamable-http", "websocket"}, Default: "stdio"}})
 	}
 	if !opts.Docker {
 		qs = append(qs, &survey.Question{Name: "docker", Prompt: &survey.Confirm{Message: "Include Docker support?", Default: true}})
@@ -140,67 +159,146 @@ func applyBasicAnswers(opts *handlers.GenerateOptions, ans basicAnswers) {
 	}
 }
 
-// promptForTools interactively adds tool definitions to the options.
+// promptForTools interactively adds tool definitions, with their parameters,
+// to the options.
 func promptForTools(opts *handlers.GenerateOptions) error {
 	var add bool
 	survey.AskOne(&survey.Confirm{Message: "Would you like to add tools?", Default: false}, &add)
 	for add {
-		var tool core.Tool
-		survey.Ask([]*survey.Question{
-			{Name: "Name", Prompt: &survey.Input{Message: "Tool name:"}, Validate: survey.Required},
-			{Name: "Description", Prompt: &survey.Input{Message: "Tool description:"}},
-		}, &tool)
-		opts.Tools = append(opts.Tools, tool)
+		opts.Tools = append(opts.Tools, askTool())
 		survey.AskOne(&survey.Confirm{Message: "Add another tool?", Default: false}, &add)
 	}
 	return nil
 }
 
+// askTool asks for a tool definition and its parameters.
+func askTool() core.Tool {
+	var tool core.Tool
+	survey.Ask([]*survey.Question{
+		{Name: "Name", Prompt: &survey.Input{Message: "Tool name:"}, Validate: survey.Required},
+		{Name: "Description", Prompt: &survey.Input{Message: "Tool description:"}},
+	}, &tool)
+	var addParam bool
+	survey.AskOne(&survey.Confirm{Message: "Add a parameter to this tool?", Default: false}, &addParam)
+	for addParam {
+		var param core.ToolParameter
+		survey.Ask([]*survey.Question{
+			{Name: "Name", Prompt: &survey.Input{Message: "Parameter name:"}, Validate: survey.Required},
+			{Name: "Type", Prompt: &survey.Select{Message: "Parameter type:", Options: core.ToolParameterTypes, Default: "string"}},
+			{Name: "Description", Prompt: &survey.Input{Message: "Parameter description:"}},
+			{Name: "Required", Prompt: &survey.Confirm{Message: "Is this parameter required?", Default: true}},
+		}, &param)
+		tool.Parameters = append(tool.Parameters, param)
+		survey.AskOne(&survey.Confirm{Message: "Add another parameter?", Default: false}, &addParam)
+	}
+	return tool
+}
+
 // promptForResources interactively adds resource definitions to the options.
 func promptForResources(opts *handlers.GenerateOptions) error {
 	var add bool
 	survey.AskOne(&survey.Confirm{Message: "Would you like to add resources?", Default: false}, &add)
 	for add {
-		var res core.Resource
-		survey.Ask([]*survey.Question{
-			{
-				Name:     "Name",
-				Prompt:   &survey.Input{Message: "Resource name:"},
-				Validate: survey.Required,
-			},
-			{
-				Name: "Type",
-				Prompt: &survey.Select{
-					Message: "Resource type:",
-					Options: []string{string(core.ResourceTypeDatabase), string(core.ResourceTypeFilesystem), string(core.ResourceTypeTime)},
-				},
-			},
-		}, &res)
-		opts.Resources = append(opts.Resources, res)
+		opts.Resources = append(opts.Resources, askResource())
 		survey.AskOne(&survey.Confirm{Message: "Add another resource?", Default: false}, &add)
 	}
 	return nil
 }
 
+// askResource asks for a resource definition.
+func askResource() core.Resource {
+	var res core.Resource
+	survey.Ask([]*survey.Question{
+		{
+			Name:     "Name",
+			Prompt:   &survey.Input{Message: "Resource name:"},
+			Validate: survey.Required,
+		},
+		{
+			Name: "Type",
+			Prompt: &survey.Select{
+				Message: "Resource type:",
+				Options: []string{string(core.ResourceTypeDatabase), string(core.ResourceTypeFilesystem), string(core.ResourceTypeTime)},
+			},
+		},
+		{
+			Name:     "URITemplate",
+			Prompt:   &survey.Input{Message: "URI template (optional, e.g. file:///{path}):"},
+			Validate: validateURITemplate,
+		},
+	}, &res)
+	return res
+}
+
+// validateURITemplate accepts an empty answer or a valid RFC 6570 URI
+// template.
+func validateURITemplate(ans interface{}) error {
+	s, _ := ans.(string)
+	if s == "" {
+		return nil
+	}
+	_, err := core.ParseURITemplate(s)
+	return err
+}
+
+// promptForPrompts interactively adds prompt definitions, with their
+// arguments, to the options.
+func promptForPrompts(opts *handlers.GenerateOptions) error {
+	var add bool
+	survey.AskOne(&survey.Confirm{Message: "Would you like to add prompts?", Default: false}, &add)
+	for add {
+		opts.Prompts = append(opts.Prompts, askPrompt())
+		survey.AskOne(&survey.Confirm{Message: "Add another prompt?", Default: false}, &add)
+	}
+	return nil
+}
+
+// askPrompt asks for a prompt definition and its arguments.
+func askPrompt() core.Prompt {
+	var prompt core.Prompt
+	survey.Ask([]*survey.Question{
+		{Name: "Name", Prompt: &survey.Input{Message: "Prompt name:"}, Validate: survey.Required},
+		{Name: "Description", Prompt: &survey.Input{Message: "Prompt description:"}},
+	}, &prompt)
+	var addArg bool
+	survey.AskOne(&survey.Confirm{Message: "Add an argument to this prompt?", Default: false}, &addArg)
+	for addArg {
+		var arg core.PromptArgument
+		survey.Ask([]*survey.Question{
+			{Name: "Name", Prompt: &survey.Input{Message: "Argument name:"}, Validate: survey.Required},
+			{Name: "Description", Prompt: &survey.Input{Message: "Argument description:"}},
+			{Name: "Required", Prompt: &survey.Confirm{Message: "Is this argument required?", Default: true}},
+		}, &arg)
+		prompt.Arguments = append(prompt.Arguments, arg)
+		survey.AskOne(&survey.Confirm{Message: "Add another argument?", Default: false}, &addArg)
+	}
+	return prompt
+}
+
 // promptForCapabilities interactively adds capability definitions to the options.
 func promptForCapabilities(opts *handlers.GenerateOptions) error {
 	var add bool
 	survey.AskOne(&survey.Confirm{Message: "Would you like to add capabilities?", Default: false}, &add)
 	for add {
-		var cap core.Capability
-		survey.Ask([]*survey.Question{
-			{
-				Name:     "Name",
-				Prompt:   &survey.Input{Message: "Capability name:"},
-				Validate: survey.Required,
-			},
-			{
-				Name:   "Enabled",
-				Prompt: &survey.Confirm{Message: "Enable this capability?"},
-			},
-		}, &cap)
-		opts.Capabilities = append(opts.Capabilities, cap)
+		opts.Capabilities = append(opts.Capabilities, askCapability())
 		survey.AskOne(&survey.Confirm{Message: "Add another capability?", Default: false}, &add)
 	}
 	return nil
 }
+
+// askCapability asks for a capability definition.
+func askCapability() core.Capability {
+	var cap core.Capability
+	survey.Ask([]*survey.Question{
+		{
+			Name:     "Name",
+			Prompt:   &survey.Input{Message: "Capability name:"},
+			Validate: survey.Required,
+		},
+		{
+			Name:   "Enabled",
+			Prompt: &survey.Confirm{Message: "Enable this capability?"},
+		},
+	}, &cap)
+	return cap
+}
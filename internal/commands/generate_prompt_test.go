@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 
 	survey "github.com/AlecAivazis/survey/v2"
@@ -60,6 +62,93 @@ func TestPromptForTools(t *testing.T) {
 	}
 }
 
+func TestPromptForToolParameters(t *testing.T) {
+	origAskOne := survey.AskOne
+	origAsk := survey.Ask
+	defer func() { survey.AskOne = origAskOne; survey.Ask = origAsk }()
+	answers := map[string][]bool{
+		"Would you like to add tools?":  {true},
+		"Add a parameter to this tool?": {true},
+		"Add another parameter?":        {true, false},
+		"Add another tool?":             {false},
+	}
+	survey.AskOne = func(p interface{}, r interface{}, _ ...interface{}) error {
+		c := p.(*survey.Confirm)
+		*r.(*bool), answers[c.Message] = answers[c.Message][0], answers[c.Message][1:]
+		return nil
+	}
+	params := 0
+	survey.Ask = func(qs interface{}, resp interface{}, _ ...interface{}) error {
+		switch v := resp.(type) {
+		case *core.Tool:
+			v.Name = "search"
+		case *core.ToolParameter:
+			params++
+			v.Name = fmt.Sprintf("param%d", params)
+			v.Type = "string"
+			v.Required = params == 1
+		}
+		return nil
+	}
+	opts := &handlers.GenerateOptions{}
+	if err := promptForTools(opts); err != nil {
+		t.Fatalf("promptForTools error: %v", err)
+	}
+	want := []core.Tool{{Name: "search", Parameters: []core.ToolParameter{{Name: "param1", Type: "string", Required: true}, {Name: "param2", Type: "string"}}}}
+	if !reflect.DeepEqual(opts.Tools, want) {
+		t.Fatalf("tool parameters not added: %+v", opts.Tools)
+	}
+}
+
+func TestPromptForPrompts(t *testing.T) {
+	origAskOne := survey.AskOne
+	origAsk := survey.Ask
+	defer func() { survey.AskOne = origAskOne; survey.Ask = origAsk }()
+	answers := map[string][]bool{
+		"Would you like to add prompts?":  {true},
+		"Add an argument to this prompt?": {true},
+		"Add another argument?":           {true, false},
+		"Add another prompt?":             {false},
+	}
+	survey.AskOne = func(p interface{}, r interface{}, _ ...interface{}) error {
+		c := p.(*survey.Confirm)
+		*r.(*bool), answers[c.Message] = answers[c.Message][0], answers[c.Message][1:]
+		return nil
+	}
+	args := 0
+	survey.Ask = func(qs interface{}, resp interface{}, _ ...interface{}) error {
+		switch v := resp.(type) {
+		case *core.Prompt:
+			v.Name = "review"
+			v.Description = "Reviews code"
+		case *core.PromptArgument:
+			args++
+			v.Name = fmt.Sprintf("arg%d", args)
+			v.Required = args == 1
+		}
+		return nil
+	}
+	opts := &handlers.GenerateOptions{}
+	if err := promptForPrompts(opts); err != nil {
+		t.Fatalf("promptForPrompts error: %v", err)
+	}
+	want := []core.Prompt{{Name: "review", Description: "Reviews code", Arguments: []core.PromptArgument{{Name: "arg1", Required: true}, {Name: "arg2"}}}}
+	if !reflect.DeepEqual(opts.Prompts, want) {
+		t.Fatalf("prompts not added: %+v", opts.Prompts)
+	}
+}
+
+func TestValidateURITemplate(t *testing.T) {
+	for _, ans := range []string{"", "file:///{path}", "db://{table}/{id}{?fields*}"} {
+		if err := validateURITemplate(ans); err != nil {
+			t.Errorf("%q: unexpected error %v", ans, err)
+		}
+	}
+	if err := validateURITemplate("file:///{path"); err == nil {
+		t.Error("expected an error for an unclosed expression")
+	}
+}
+
 func TestPromptForResourcesAndCapabilities(t *testing.T) {
 	origOne := survey.AskOne
 	origAsk := survey.Ask
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/aawadall/mcpcli/internal/handlers"
@@ -37,6 +39,18 @@ func TestPrepareDirectory(t *testing.T) {
 	}
 }
 
+func TestGenerateCmd_DryRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "out")
+	cmd := NewGenerateCmd()
+	cmd.SetArgs([]string{"--language", "golang", "--transport", "stdio", "--output", out, "--dry-run"})
+	if err := cmd.RunE(cmd, []string{"proj"}); err != nil {
+		t.Fatalf("generate --dry-run failed: %v", err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s", out)
+	}
+}
+
 func TestValidateGenerateOptions_Language(t *testing.T) {
 	langs := []string{"golang", "javascript", "java", "python"}
 	for _, l := range langs {
@@ -77,6 +91,12 @@ func TestAddFlags(t *testing.T) {
 	if cmd.Flags().Lookup("force") == nil {
 		t.Fatal("expected 'force' flag to be added")
 	}
+	if cmd.Flags().Lookup("dry-run") == nil {
+		t.Fatal("expected 'dry-run' flag to be added")
+	}
+	if cmd.Flags().Lookup("diff") == nil {
+		t.Fatal("expected 'diff' flag to be added")
+	}
 
 }
 
@@ -92,3 +112,52 @@ func TestNewGenerateCmd(t *testing.T) {
 		t.Fatalf("expected short description to match, got %s", cmd.Short)
 	}
 }
+
+func TestGenerateCmd_FromSpec(t *testing.T) {
+	dir := t.TempDir()
+	spec := filepath.Join(dir, "spec.yaml")
+	data := "name: weather\nlanguage: golang\ntransport: stdio\ndescription: Forecasts\ntools:\n  - name: forecast\n    parameters:\n      - {name: city, type: string, required: true}\n"
+	if err := os.WriteFile(spec, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(dir, "out")
+	cmd := NewGenerateCmd()
+	cmd.SetArgs([]string{"--from", spec, "--output", out})
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("generate --from failed: %v", err)
+	}
+	config, err := os.ReadFile(filepath.Join(out, "configs", "mcp-config.json"))
+	if err != nil {
+		t.Fatalf("config not generated: %v", err)
+	}
+	if !strings.Contains(string(config), `"city"`) {
+		t.Errorf("tool parameters missing from config:\n%s", config)
+	}
+
+	if err := os.WriteFile(spec, []byte(data+"docker: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	noDocker := filepath.Join(dir, "no-docker")
+	cmd = NewGenerateCmd()
+	cmd.SetArgs([]string{"--from", spec, "--output", noDocker, "--docker", "false"})
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("generate --from --docker false failed: %v", err)
+	}
+	config, err = os.ReadFile(filepath.Join(noDocker, "configs", "mcp-config.json"))
+	if err != nil {
+		t.Fatalf("config not generated: %v", err)
+	}
+	if !strings.Contains(string(config), `"docker": false`) {
+		t.Errorf("--docker false did not override the spec:\n%s", config)
+	}
+
+	if err := os.WriteFile(spec, []byte("name: weather\nlanguage: cobol\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd = NewGenerateCmd()
+	cmd.SetArgs([]string{"--from", spec, "--output", filepath.Join(dir, "bad")})
+	err = cmd.RunE(cmd, nil)
+	if err == nil || !strings.Contains(err.Error(), "line 2, column 11") {
+		t.Fatalf("expected a located spec error, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/aawadall/mcpcli/internal/core"
 	"github.com/aawadall/mcpcli/internal/handlers"
 	"testing"
 )
@@ -31,4 +32,10 @@ func TestValidateOptions(t *testing.T) {
 	if err := handlers.ValidateGenerateOptions(opts); err == nil {
 		t.Fatal("expected error for invalid transport")
 	}
+
+	opts.Transport = "stdio"
+	opts.Tools = []core.Tool{{Name: "search", Parameters: []core.ToolParameter{{Name: "query", Type: "text"}}}}
+	if err := handlers.ValidateGenerateOptions(opts); err == nil {
+		t.Fatal("expected error for invalid tool parameter type")
+	}
 }
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewListCmd creates the `list` cobra command.
+func NewListCmd() *cobra.Command {
+	opts := &handlers.ClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "list tools|resources|templates|prompts",
+		Short: "List the tools, resources, resource templates or prompts of an MCP server.",
+		Long:  `List starts or connects to an MCP server and prints every tool, resource, resource template or prompt it lists, following pagination. The command fails when the server returns an error.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("specify what to list: %v", handlers.ListKinds)
+			}
+			return handlers.RunList(opts, args[0], os.Stdout)
+		},
+	}
+
+	addClientFlags(cmd, opts)
+
+	return cmd
+}
@@ -0,0 +1,13 @@
+package commands
+
+import (
+	"os"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestMain(m *testing.M) {
+	mcptest.RunIfRequested()
+	os.Exit(m.Run())
+}
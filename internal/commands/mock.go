@@ -0,0 +1,37 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewMockCmd creates the `mock` cobra command.
+func NewMockCmd() *cobra.Command {
+	opts := &handlers.MockOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "mock",
+		Short: "Serve a fake MCP server from a config or a recorded session.",
+		Long: `Mock serves canned initialize, tools/list, tools/call, resources/list and resources/read responses
+built from the tools and resources of an MCP configuration, or replayed from a session recorded with
+"mcpcli test --record". A rules file templates responses and injects latency and errors.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handlers.RunMock(opts, os.Stderr)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "Path to MCP configuration file")
+	cmd.Flags().StringVarP(&opts.Recording, "recording", "", "", "Session recorded with \"mcpcli test --record\" to replay")
+	cmd.Flags().StringVarP(&opts.Rules, "rules", "", "", "JSON file of response rules (templates, latency, errors)")
+	cmd.Flags().StringVarP(&opts.Transport, "transport", "t", "", "Transport to serve: stdio, http or websocket (defaults to the config transport)")
+	cmd.Flags().StringVarP(&opts.Host, "host", "", "", "Host to listen on for http and websocket (default localhost)")
+	cmd.Flags().IntVar(&opts.Port, "port", 0, "Port to listen on (default 8080 for http, 8081 for websocket)")
+	cmd.Flags().StringVarP(&opts.Path, "path", "", "", "Endpoint path (default /mcp for http, /ws for websocket)")
+	cmd.Flags().DurationVar(&opts.Latency, "latency", 0, "Delay added to every response")
+	cmd.Flags().Float64Var(&opts.ErrorRate, "error-rate", 0, "Probability, between 0 and 1, of answering a request with an injected error")
+	cmd.Flags().Int64Var(&opts.Seed, "seed", 0, "Seed making injected errors reproducible")
+
+	return cmd
+}
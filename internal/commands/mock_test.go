@@ -0,0 +1,23 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMockCmd_RequiresSource(t *testing.T) {
+	root := MakeRootCommand(version)
+	root.SetArgs([]string{"mock", "--transport", "http"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "config or a recording") {
+		t.Errorf("expected missing source error, got %v", err)
+	}
+}
+
+func TestMockCmd_Flags(t *testing.T) {
+	cmd := NewMockCmd()
+	for _, name := range []string{"config", "recording", "rules", "transport", "host", "port", "path", "latency", "error-rate", "seed"} {
+		if cmd.Flags().Lookup(name) == nil {
+			t.Errorf("missing flag --%s", name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewReadCmd creates the `read` cobra command.
+func NewReadCmd() *cobra.Command {
+	opts := &handlers.ClientOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "read <uri>",
+		Short: "Read a resource of an MCP server and print its contents.",
+		Long:  `Read starts or connects to an MCP server, reads one resource and prints its contents. The command fails when the server returns an error.`,
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("a resource uri is required")
+			}
+			return handlers.RunRead(opts, args[0], os.Stdout)
+		},
+	}
+
+	addClientFlags(cmd, opts)
+
+	return cmd
+}
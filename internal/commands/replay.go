@@ -0,0 +1,44 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewReplayCmd creates the `replay` cobra command.
+func NewReplayCmd() *cobra.Command {
+	opts := &handlers.ReplayOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "replay <recording>",
+		Short: "Replay a recorded MCP session and compare the responses.",
+		Long: `Replay re-sends the requests of a session recorded with "mcpcli test --record" to an MCP server
+and compares each response with the recorded one. Volatile values can be left out of the comparison
+with --ignore.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("a recording file is required")
+			}
+			opts.Recording = args[0]
+			if opts.Config == "" {
+				return fmt.Errorf("--config is required")
+			}
+			config, err := handlers.LoadMCPConfig(opts.Config)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			return handlers.RunReplay(opts, config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "Path to MCP configuration file")
+	cmd.Flags().StringSliceVar(&opts.Ignore, "ignore", nil, "JSONPath of a response value to leave out of the comparison (repeatable)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", handlers.DefaultTestTimeout, "Timeout for each request sent to the server (0 disables it)")
+	cmd.Flags().StringVarP(&opts.Report, "report", "", "", "Machine-readable report format (junit, tap, json)")
+	cmd.Flags().StringVarP(&opts.ReportFile, "report-file", "", "", "Write the report to this file instead of stdout (defaults to junit format)")
+
+	return cmd
+}
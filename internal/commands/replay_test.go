@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestReplayCmd_RecordAndReplay(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := filepath.Join(tmpDir, "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}}})
+	if err := os.WriteFile(cfg, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	session := filepath.Join(tmpDir, "session.jsonl")
+
+	test := NewTestCmd()
+	test.SetArgs([]string{"--config", cfg, "--tools", "true", "--record", session})
+	if err := test.Execute(); err != nil {
+		t.Fatalf("test failed: %v", err)
+	}
+
+	report := filepath.Join(tmpDir, "replay.tap")
+	root := MakeRootCommand(version)
+	root.SetArgs([]string{"replay", session, "--config", cfg, "--ignore", "$.result.serverInfo.version", "--report", "tap", "--report-file", report})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("replay failed: %v", err)
+	}
+	out, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"TAP version 13\n1..3\n", "ok 1 - replay: initialize (line 1)\n", "ok 3 - replay: tools/call (line 6)\n"} {
+		if !strings.Contains(string(out), want) {
+			t.Errorf("report missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestReplayCmd_RequiresConfig(t *testing.T) {
+	cmd := NewReplayCmd()
+	cmd.SetArgs([]string{"session.jsonl"})
+	if err := cmd.Execute(); err == nil || !strings.Contains(err.Error(), "--config") {
+		t.Errorf("expected missing config error, got %v", err)
+	}
+}
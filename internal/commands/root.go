@@ -15,7 +15,15 @@ func MakeRootCommand(version string) *cobra.Command {
 
 	// Add subcommands
 	rootCmd.AddCommand(NewGenerateCmd())
+	rootCmd.AddCommand(NewAddCmd())
+	rootCmd.AddCommand(NewUpgradeCmd())
 	rootCmd.AddCommand(NewTestCmd())
+	rootCmd.AddCommand(NewReplayCmd())
+	rootCmd.AddCommand(NewMockCmd())
+	rootCmd.AddCommand(NewShellCmd())
+	rootCmd.AddCommand(NewCallCmd())
+	rootCmd.AddCommand(NewReadCmd())
+	rootCmd.AddCommand(NewListCmd())
 	// TODO: Add future commands
 
 	// Global flags
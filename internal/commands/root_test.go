@@ -33,7 +33,8 @@ func TestAddedCommands(t *testing.T) {
 	}
 
 	for _, cmd := range rootCmd.Commands() {
-		if cmd.Name() == "generate" || cmd.Name() == "test" {
+		if cmd.Name() == "generate" || cmd.Name() == "add" || cmd.Name() == "upgrade" || cmd.Name() == "test" || cmd.Name() == "replay" || cmd.Name() == "mock" || cmd.Name() == "shell" ||
+			cmd.Name() == "call" || cmd.Name() == "read" || cmd.Name() == "list" {
 			if cmd.Use == "" {
 				t.Errorf("expected command '%s' to have a valid use description", cmd.Name())
 			}
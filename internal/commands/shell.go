@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewShellCmd creates the `shell` cobra command.
+func NewShellCmd() *cobra.Command {
+	opts := &handlers.ShellOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Open an interactive shell connected to an MCP server.",
+		Long: `Shell connects to an MCP server through the configured transport and reads commands such as
+"tools", "call <tool> {json}", "resources", "read <uri>", "prompts" and "raw <method> {json}".
+Tool names and resource URIs are completed with Tab and commands are kept in a history.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if opts.Config == "" {
+				return fmt.Errorf("--config is required")
+			}
+			config, err := handlers.LoadMCPConfig(opts.Config)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			return handlers.RunShell(opts, config)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "Path to MCP configuration file")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", handlers.DefaultTestTimeout, "Timeout for each request sent to the server (0 disables it)")
+	cmd.Flags().StringVarP(&opts.ProtocolVersion, "protocol-version", "", core.LatestProtocolVersion, "MCP protocol version requested during initialization")
+	cmd.Flags().StringVarP(&opts.HistoryFile, "history-file", "", handlers.DefaultHistoryFile(), "File keeping the command history (empty disables it)")
+
+	return cmd
+}
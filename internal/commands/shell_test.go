@@ -0,0 +1,14 @@
+package commands
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestShellCmd_RequiresConfig(t *testing.T) {
+	root := MakeRootCommand(version)
+	root.SetArgs([]string{"shell"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "--config") {
+		t.Errorf("expected missing config error, got %v", err)
+	}
+}
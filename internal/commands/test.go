@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/aawadall/mcpcli/internal/core"
 	"github.com/aawadall/mcpcli/internal/handlers"
 	"github.com/spf13/cobra"
 )
@@ -15,12 +16,12 @@ type TestOptions = handlers.TestOptions
 // needsTestInteractiveMode returns true if no test flags are set and
 // the command should prompt the user interactively.
 func needsTestInteractiveMode(opts *TestOptions) bool {
-	return !opts.TestAll && !opts.TestResources && !opts.TestTools && !opts.TestCapabilities && !opts.TestInit && opts.ScriptFile == "" && opts.Config == ""
+	return !opts.TestAll && !opts.TestResources && !opts.TestTools && !opts.TestPrompts && !opts.TestSubscriptions && !opts.TestCapabilities && !opts.TestInit && !opts.Fuzz && !opts.Conformance && opts.ScriptFile == "" && opts.Config == ""
 }
 
 // promptForTestOptions displays an interactive survey to choose which tests to run.
 func promptForTestOptions(opts *TestOptions) error {
-	choices := []string{"Resources", "Tools", "Capabilities", "Initialization", "All"}
+	choices := []string{"Resources", "Tools", "Prompts", "Subscriptions", "Capabilities", "Initialization", "All"}
 	selected := []string{}
 	prompt := &survey.MultiSelect{
 		Message: "Which tests would you like to run?",
@@ -37,6 +38,10 @@ func promptForTestOptions(opts *TestOptions) error {
 			opts.TestResources = true
 		case "Tools":
 			opts.TestTools = true
+		case "Prompts":
+			opts.TestPrompts = true
+		case "Subscriptions":
+			opts.TestSubscriptions = true
 		case "Capabilities":
 			opts.TestCapabilities = true
 		case "Initialization":
@@ -67,8 +72,8 @@ func NewTestCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:   "test",
-		Short: "Test MCP server resources, tools, capabilities, and initialization.",
-		Long:  `Test command connects to an MCP server and runs tests on resources, tools, capabilities, and initialization. Optionally, test instructions can be read from a script file.`,
+		Short: "Test MCP server resources, tools, prompts, capabilities, and initialization.",
+		Long:  `Test command connects to an MCP server and runs tests on resources, tools, prompts, capabilities, and initialization. Optionally, test instructions can be read from a script file.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if needsTestInteractiveMode(opts) {
 				if err := promptForTestOptions(opts); err != nil {
@@ -86,12 +91,25 @@ func NewTestCmd() *cobra.Command {
 	}
 
 	cmd.Flags().StringVarP(&opts.Config, "config", "c", "", "Path to MCP configuration file")
-	cmd.Flags().BoolVar(&opts.TestAll, "all", false, "Test all components (resources, tools, capabilities, init)")
+	cmd.Flags().BoolVar(&opts.TestAll, "all", false, "Test all components (resources, tools, prompts and subscriptions when advertised, capabilities, init)")
 	cmd.Flags().BoolVar(&opts.TestResources, "resources", false, "Test resources")
 	cmd.Flags().BoolVar(&opts.TestTools, "tools", false, "Test tools")
+	cmd.Flags().BoolVar(&opts.TestPrompts, "prompts", false, "Test prompts")
+	cmd.Flags().BoolVar(&opts.TestSubscriptions, "subscriptions", false, "Test that subscribed resources produce update notifications")
+	cmd.Flags().StringVarP(&opts.TriggerTool, "trigger-tool", "", "", "Tool called to update a subscribed resource (default: try every tool)")
 	cmd.Flags().BoolVar(&opts.TestCapabilities, "capabilities", false, "Test capabilities")
 	cmd.Flags().BoolVar(&opts.TestInit, "init", false, "Test initialization")
 	cmd.Flags().StringVarP(&opts.ScriptFile, "script", "f", "", "Path to test script file")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", handlers.DefaultTestTimeout, "Timeout for each request sent to the server (0 disables it)")
+	cmd.Flags().StringVarP(&opts.ProtocolVersion, "protocol-version", "", core.LatestProtocolVersion, "MCP protocol version requested during initialization")
+	cmd.Flags().StringVarP(&opts.Record, "record", "", "", "Record every frame exchanged with the server to this JSON Lines file")
+	cmd.Flags().BoolVar(&opts.Conformance, "conformance", false, "Run the built-in protocol conformance checks")
+	cmd.Flags().BoolVar(&opts.Fuzz, "fuzz", false, "Call every tool with random and edge-case arguments generated from its inputSchema")
+	cmd.Flags().IntVar(&opts.FuzzIterations, "fuzz-iterations", handlers.DefaultFuzzIterations, "Number of random inputs per tool, in addition to the edge cases")
+	cmd.Flags().Int64Var(&opts.FuzzSeed, "fuzz-seed", handlers.DefaultFuzzSeed, "Seed of the fuzzer, reuse it to reproduce a run")
+	cmd.Flags().StringVarP(&opts.FuzzOutput, "fuzz-output", "", handlers.DefaultFuzzOutput, "Directory receiving the scripts reproducing fuzzing failures")
+	cmd.Flags().StringVarP(&opts.Report, "report", "", "", "Machine-readable report format (junit, tap, json)")
+	cmd.Flags().StringVarP(&opts.ReportFile, "report-file", "", "", "Write the report to this file instead of stdout (defaults to junit format)")
 
 	return cmd
 }
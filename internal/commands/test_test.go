@@ -10,6 +10,7 @@ import (
 	survey "github.com/AlecAivazis/survey/v2"
 	"github.com/aawadall/mcpcli/internal/core"
 	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/aawadall/mcpcli/internal/mcptest"
 )
 
 func writeTempConfig(t *testing.T, dir string) string {
@@ -32,7 +33,14 @@ func writeTempConfig(t *testing.T, dir string) string {
 func TestTestCmd_FlagParsingAndExecution(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := writeTempConfig(t, tmpDir)
-	script := filepath.Join(tmpDir, "script.txt")
+	data, _ := json.Marshal(&core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}}})
+	if err := os.WriteFile(cfg, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	script := filepath.Join(tmpDir, "script.json")
+	if err := os.WriteFile(script, []byte(`{"steps": [{"method": "ping"}]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
 
 	cmd := NewTestCmd()
 	cmd.SetArgs([]string{"--config", cfg, "--script", script})
@@ -73,6 +81,8 @@ func TestNeedsTestInteractiveMode(t *testing.T) {
 	}{
 		{"none", handlers.TestOptions{}, true},
 		{"all", handlers.TestOptions{TestAll: true}, false},
+		{"prompts", handlers.TestOptions{TestPrompts: true}, false},
+		{"subscriptions", handlers.TestOptions{TestSubscriptions: true}, false},
 		{"script", handlers.TestOptions{ScriptFile: "file"}, false},
 		{"config", handlers.TestOptions{Config: "cfg"}, false},
 	}
@@ -132,7 +142,7 @@ func TestLoadMCPConfig_FileNotFound(t *testing.T) {
 
 func TestNewTestCmd_HasFlags(t *testing.T) {
 	cmd := NewTestCmd()
-	flags := []string{"config", "all", "resources", "tools", "capabilities", "init", "script"}
+	flags := []string{"config", "all", "resources", "tools", "capabilities", "init", "script", "timeout", "protocol-version"}
 	for _, f := range flags {
 		if cmd.Flags().Lookup(f) == nil {
 			t.Errorf("flag %s not defined", f)
@@ -168,3 +178,73 @@ func TestPromptForTestOptions_SelectAll(t *testing.T) {
 		t.Errorf("expected config 'mycfg.json', got %s", opts.Config)
 	}
 }
+
+func TestTestCmd_ReportFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := filepath.Join(tmpDir, "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}}})
+	if err := os.WriteFile(cfg, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(tmpDir, "results.tap")
+
+	cmd := NewTestCmd()
+	cmd.SetArgs([]string{"--config", cfg, "--tools", "--report", "tap", "--report-file", report})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	out, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "TAP version 13\n1..2\nok 1 - tools: tools\nok 2 - tools: tools/call echo\n") {
+		t.Errorf("unexpected report: %s", out)
+	}
+}
+
+func TestTestCmd_FuzzFlags(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := filepath.Join(tmpDir, "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}}})
+	if err := os.WriteFile(cfg, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(tmpDir, "results.tap")
+
+	cmd := NewTestCmd()
+	cmd.SetArgs([]string{"--config", cfg, "--fuzz", "true", "--fuzz-iterations", "5", "--fuzz-seed", "9",
+		"--fuzz-output", filepath.Join(tmpDir, "repro"), "--report", "tap", "--report-file", report})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	out, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(out), "TAP version 13\n1..2\nok 1 - fuzz: tools\nok 2 - fuzz: fuzz echo\n") {
+		t.Errorf("unexpected report: %s", out)
+	}
+}
+
+func TestTestCmd_ConformanceFlag(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := filepath.Join(tmpDir, "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}}})
+	if err := os.WriteFile(cfg, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	report := filepath.Join(tmpDir, "results.json")
+
+	cmd := NewTestCmd()
+	cmd.SetArgs([]string{"--config", cfg, "--conformance", "true", "--report", "json", "--report-file", report})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("command failed: %v", err)
+	}
+	out, err := os.ReadFile(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"name": "mcp-conformance@1.0.0"`) || !strings.Contains(string(out), `"name": "jsonrpc/parse-error"`) {
+		t.Errorf("unexpected report: %s", out)
+	}
+}
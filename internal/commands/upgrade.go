@@ -0,0 +1,32 @@
+package commands
+
+import (
+	"os"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+	"github.com/spf13/cobra"
+)
+
+// NewUpgradeCmd creates the `upgrade` cobra command, which brings the
+// templates of this mcpcli into a project created by generate.
+func NewUpgradeCmd() *cobra.Command {
+	opts := &handlers.UpgradeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "Upgrade a generated project to the templates of this mcpcli.",
+		Long: `Upgrade renders a project created by generate again with the templates of this mcpcli.
+Files left as they were generated are replaced. Files edited since are three-way merged, using the
+content recorded in .mcpcli when they were generated as the common ancestor, and get conflict markers
+where both sides changed the same lines. With --reject, or when no generated content was recorded,
+such files are left as is and the template changes are written to a .rej file next to them.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handlers.RunUpgrade(opts, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVarP(&opts.Dir, "dir", "", ".", "Project directory")
+	cmd.Flags().BoolVar(&opts.Reject, "reject", false, "Write conflicting changes to .rej files instead of conflict markers")
+
+	return cmd
+}
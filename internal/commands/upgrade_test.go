@@ -0,0 +1,34 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/handlers"
+)
+
+func TestUpgradeCmd(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := handlers.GenerateProject(&handlers.GenerateOptions{Name: "proj", Language: "python", Transport: "stdio", Output: dir}); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cmd := NewUpgradeCmd()
+	cmd.SetArgs([]string{"--dir", dir, "--reject"})
+	if err := cmd.RunE(cmd, nil); err != nil {
+		t.Fatalf("upgrade failed: %v", err)
+	}
+	if data, _ := os.ReadFile(readme); string(data) != "mine\n" {
+		t.Errorf("README.md was changed:\n%s", data)
+	}
+
+	cmd = NewUpgradeCmd()
+	cmd.SetArgs([]string{"--dir", t.TempDir()})
+	if err := cmd.RunE(cmd, nil); err == nil {
+		t.Error("expected an error outside a project")
+	}
+}
@@ -0,0 +1,230 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ClientTransport carries encoded JSON-RPC messages between the client and an
+// MCP server. Send may be called from several goroutines at once while
+// Receive is only called from the client's reader goroutine.
+type ClientTransport interface {
+	// Send delivers one message to the server.
+	Send(ctx context.Context, message []byte) error
+	// Receive blocks until the next message from the server is available and
+	// returns io.EOF once the connection is closed.
+	Receive() ([]byte, error)
+	// Close releases the connection.
+	Close() error
+}
+
+// NewClientTransport creates the transport described by an MCP configuration.
+// Supported types are stdio (a subprocess started from the "command" option,
+// or the process' own stdin/stdout when no command is set), rest/http,
+// streamable-http/sse and websocket. Network transports use the "url" option
+// or build the address from the "host", "port" and "path" options.
+func NewClientTransport(cfg Transport) (ClientTransport, error) {
+	switch cfg.Type {
+	case "", "stdio":
+		command, _ := cfg.Options["command"].(string)
+		if strings.TrimSpace(command) == "" {
+			return NewStreamTransport(os.Stdin, os.Stdout), nil
+		}
+		return StartCommandTransport(command)
+	case "rest", "http":
+		endpoint, err := transportURL(cfg.Options, "http", 8080, "/mcp")
+		if err != nil {
+			return nil, err
+		}
+		return NewHTTPTransport(endpoint, nil), nil
+	case "streamable-http", "sse":
+		endpoint, err := transportURL(cfg.Options, "http", 8080, "/mcp")
+		if err != nil {
+			return nil, err
+		}
+		return NewStreamableHTTPTransport(endpoint, nil), nil
+	case "websocket":
+		endpoint, err := transportURL(cfg.Options, "ws", 8081, "/ws")
+		if err != nil {
+			return nil, err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), dialTimeout)
+		defer cancel()
+		return DialWebSocket(ctx, endpoint, nil)
+	default:
+		return nil, fmt.Errorf("unsupported transport type: %s", cfg.Type)
+	}
+}
+
+// dialTimeout bounds establishing network connections to a server.
+const dialTimeout = 10 * time.Second
+
+// transportURL returns the "url" option or builds one from host, port and path.
+func transportURL(options map[string]interface{}, scheme string, defaultPort int, defaultPath string) (string, error) {
+	if raw, ok := options["url"].(string); ok && raw != "" {
+		if _, err := url.Parse(raw); err != nil {
+			return "", fmt.Errorf("invalid transport url: %w", err)
+		}
+		return raw, nil
+	}
+	host, _ := options["host"].(string)
+	if host == "" {
+		host = "localhost"
+	}
+	port := defaultPort
+	switch p := options["port"].(type) {
+	case int:
+		port = p
+	case float64:
+		port = int(p)
+	case string:
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid transport port %q", p)
+		}
+		port = n
+	}
+	path, _ := options["path"].(string)
+	if path == "" {
+		path = defaultPath
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return fmt.Sprintf("%s://%s:%d%s", scheme, host, port, path), nil
+}
+
+// StreamTransport exchanges newline-delimited messages over a reader and a
+// writer, as used by the stdio transport.
+type StreamTransport struct {
+	reader  *bufio.Reader
+	writer  io.Writer
+	writeMu sync.Mutex
+}
+
+// NewStreamTransport creates a transport reading messages from r and writing
+// them to w.
+func NewStreamTransport(r io.Reader, w io.Writer) *StreamTransport {
+	return &StreamTransport{reader: bufio.NewReader(r), writer: w}
+}
+
+// Send writes the message followed by a newline.
+func (t *StreamTransport) Send(ctx context.Context, message []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	if _, err := t.writer.Write(append(bytes.TrimSpace(message), '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Receive returns the next non-empty line.
+func (t *StreamTransport) Receive() ([]byte, error) {
+	for {
+		line, err := t.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) > 0 {
+			return line, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Close closes the writer when it supports closing.
+func (t *StreamTransport) Close() error {
+	if c, ok := t.writer.(io.Closer); ok && t.writer != os.Stdout {
+		return c.Close()
+	}
+	return nil
+}
+
+// serverShutdownGrace is how long a server subprocess may take to exit after
+// its stdin is closed before it is killed.
+const serverShutdownGrace = 2 * time.Second
+
+// CommandTransport runs an MCP server as a subprocess and talks to it over
+// its stdin and stdout.
+type CommandTransport struct {
+	*StreamTransport
+	cmd       *exec.Cmd
+	stdin     io.WriteCloser
+	exited    chan struct{}
+	closeOnce sync.Once
+	waitErr   error
+}
+
+// StartCommandTransport starts the server command line, split on whitespace,
+// with its stderr forwarded to ours.
+func StartCommandTransport(command string) (*CommandTransport, error) {
+	parts := strings.Fields(command)
+	if len(parts) == 0 {
+		return nil, fmt.Errorf("invalid server command: %s", command)
+	}
+	cmd := exec.Command(parts[0], parts[1:]...)
+	cmd.Stderr = os.Stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdin pipe: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start server: %w", err)
+	}
+	t := &CommandTransport{
+		StreamTransport: NewStreamTransport(stdout, stdin),
+		cmd:             cmd,
+		stdin:           stdin,
+		exited:          make(chan struct{}),
+	}
+	go func() {
+		t.waitErr = cmd.Wait()
+		close(t.exited)
+	}()
+	return t, nil
+}
+
+// Exited is closed once the server process has terminated.
+func (t *CommandTransport) Exited() <-chan struct{} {
+	return t.exited
+}
+
+// ExitError returns the error reported by the terminated server process. It
+// must only be called after Exited is closed.
+func (t *CommandTransport) ExitError() error {
+	return t.waitErr
+}
+
+// Close closes the server's stdin and kills it if it does not exit in time,
+// so a hung server cannot block the caller.
+func (t *CommandTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.stdin.Close()
+		select {
+		case <-t.exited:
+		case <-time.After(serverShutdownGrace):
+			t.cmd.Process.Kill()
+			<-t.exited
+		}
+	})
+	var exitErr *exec.ExitError
+	if t.waitErr != nil && !errors.As(t.waitErr, &exitErr) {
+		return t.waitErr
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestTransportURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		options map[string]interface{}
+		want    string
+	}{
+		{"defaults", nil, "http://localhost:8080/mcp"},
+		{"explicit url", map[string]interface{}{"url": "https://example.com/rpc"}, "https://example.com/rpc"},
+		{"json port", map[string]interface{}{"host": "127.0.0.1", "port": float64(9000), "path": "rpc"}, "http://127.0.0.1:9000/rpc"},
+		{"string port", map[string]interface{}{"port": "9001"}, "http://localhost:9001/mcp"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transportURL(tt.options, "http", 8080, "/mcp")
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+	if _, err := transportURL(map[string]interface{}{"port": "abc"}, "http", 8080, "/mcp"); err == nil {
+		t.Error("expected error for invalid port")
+	}
+}
+
+func TestNewClientTransport_Unsupported(t *testing.T) {
+	if _, err := NewClientTransport(Transport{Type: "carrier-pigeon"}); err == nil {
+		t.Error("expected error for unsupported transport")
+	}
+}
+
+func TestNewClientTransport_BadCommand(t *testing.T) {
+	cfg := Transport{Type: "stdio", Options: map[string]interface{}{"command": "/nonexistent/server"}}
+	if _, err := NewClientTransport(cfg); err == nil {
+		t.Error("expected error for missing server binary")
+	}
+}
+
+func TestCommandTransport(t *testing.T) {
+	if _, err := exec.LookPath("cat"); err != nil {
+		t.Skip("cat not available")
+	}
+	tr, err := StartCommandTransport("cat")
+	if err != nil {
+		t.Fatalf("start failed: %v", err)
+	}
+	if err := tr.Send(context.Background(), []byte(`{"jsonrpc":"2.0","method":"ping","id":1}`)); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	msg, err := tr.Receive()
+	if err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if !strings.Contains(string(msg), `"method":"ping"`) {
+		t.Errorf("unexpected echo %s", msg)
+	}
+	if err := tr.Close(); err != nil {
+		t.Errorf("close failed: %v", err)
+	}
+	select {
+	case <-tr.Exited():
+	default:
+		t.Error("expected server process to have exited")
+	}
+}
+
+// newHTTPServer answers every JSON-RPC request with its method name as the
+// result and accepts notifications with 202.
+func newHTTPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		if req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if req.Method == "fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(NewErrorResponse(req.ID, InvalidRequest, "rejected", nil))
+			return
+		}
+		json.NewEncoder(w).Encode(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: req.Method})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestHTTPTransport_Call(t *testing.T) {
+	srv := newHTTPServer(t)
+	c := NewMCPClientWithTransport(NewHTTPTransport(srv.URL, nil), io.Discard)
+	defer c.Close()
+
+	resp, err := c.ListTools(nil)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if resp.Result != "tools/list" {
+		t.Errorf("unexpected result %v", resp.Result)
+	}
+	if err := c.SendNotification("notifications/initialized", nil); err != nil {
+		t.Errorf("notification failed: %v", err)
+	}
+	resp, err = c.Call("fail", nil, nil)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != InvalidRequest {
+		t.Errorf("expected JSON-RPC error, got %+v", resp)
+	}
+}
+
+func TestHTTPTransport_StatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	c := NewMCPClientWithTransport(NewHTTPTransport(srv.URL, nil), io.Discard)
+	defer c.Close()
+	_, err := c.Call("ping", nil, nil)
+	if err == nil || !strings.Contains(err.Error(), "500") {
+		t.Errorf("expected status error, got %v", err)
+	}
+}
+
+func TestNewClientTransport_HTTP(t *testing.T) {
+	srv := newHTTPServer(t)
+	tr, err := NewClientTransport(Transport{Type: "rest", Options: map[string]interface{}{"url": srv.URL}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := NewMCPClientWithTransport(tr, io.Discard)
+	defer c.Close()
+	if _, err := c.Call("ping", nil, nil); err != nil {
+		t.Errorf("call failed: %v", err)
+	}
+}
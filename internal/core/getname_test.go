@@ -16,6 +16,13 @@ func TestResourceGetName(t *testing.T) {
 	}
 }
 
+func TestPromptGetName(t *testing.T) {
+	p := Prompt{Name: "summarize"}
+	if p.GetName() != "summarize" {
+		t.Errorf("expected 'summarize', got %s", p.GetName())
+	}
+}
+
 func TestCapabilityGetName(t *testing.T) {
 	c := Capability{Name: "cap", Enabled: true}
 	if c.GetName() != "cap" {
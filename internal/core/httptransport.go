@@ -0,0 +1,94 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// HTTPTransport sends every message as an HTTP POST and queues the JSON
+// bodies of the replies for Receive, matching the servers scaffolded with the
+// rest transport.
+type HTTPTransport struct {
+	endpoint string
+	client   *http.Client
+
+	incoming  chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+// NewHTTPTransport creates a transport posting to endpoint. A nil client
+// defaults to http.DefaultClient.
+func NewHTTPTransport(endpoint string, client *http.Client) *HTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &HTTPTransport{
+		endpoint: endpoint,
+		client:   client,
+		incoming: make(chan []byte, 16),
+		closed:   make(chan struct{}),
+	}
+}
+
+// Send posts the message and queues the response body, if any.
+func (t *HTTPTransport) Send(ctx context.Context, message []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read http response: %w", err)
+	}
+	body = bytes.TrimSpace(body)
+	if resp.StatusCode >= 300 && !looksLikeJSON(body) {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return t.enqueue(body)
+}
+
+// enqueue hands a received message to Receive unless the transport is closed.
+func (t *HTTPTransport) enqueue(message []byte) error {
+	select {
+	case t.incoming <- message:
+		return nil
+	case <-t.closed:
+		return io.ErrClosedPipe
+	}
+}
+
+// Receive returns the next queued response body.
+func (t *HTTPTransport) Receive() ([]byte, error) {
+	select {
+	case msg := <-t.incoming:
+		return msg, nil
+	case <-t.closed:
+		return nil, io.EOF
+	}
+}
+
+// Close stops Receive.
+func (t *HTTPTransport) Close() error {
+	t.closeOnce.Do(func() { close(t.closed) })
+	return nil
+}
+
+// looksLikeJSON reports whether a body holds a JSON object or array.
+func looksLikeJSON(body []byte) bool {
+	return len(body) > 0 && (body[0] == '{' || body[0] == '[')
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// diffStep is one step from the root of a document to the value compared.
+type diffStep struct {
+	key     string
+	index   int
+	isIndex bool
+	// length is the length of the array indexed, for negative indexes.
+	length int
+}
+
+// DiffJSON compares two decoded JSON documents and describes each difference
+// as "<path>: <problem>", in document order. Values matching one of the
+// ignore JSONPath expressions (see EvalJSONPath) are not compared.
+func DiffJSON(want, got interface{}, ignore []string) ([]string, error) {
+	var patterns [][]pathSegment
+	for _, p := range ignore {
+		segments, err := parseJSONPath(p)
+		if err != nil {
+			return nil, err
+		}
+		patterns = append(patterns, segments)
+	}
+	d := &jsonDiff{ignore: patterns}
+	d.compare(normalizeJSON(want), normalizeJSON(got), nil)
+	return d.diffs, nil
+}
+
+type jsonDiff struct {
+	ignore [][]pathSegment
+	diffs  []string
+}
+
+func (d *jsonDiff) compare(want, got interface{}, path []diffStep) {
+	if d.ignored(path) {
+		return
+	}
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			child := append(path[:len(path):len(path)], diffStep{key: k})
+			wv, inWant := w[k]
+			gv, inGot := g[k]
+			switch {
+			case d.ignored(child):
+			case !inGot:
+				d.add(child, "missing, expected %s", compactJSON(wv))
+			case !inWant:
+				d.add(child, "unexpected %s", compactJSON(gv))
+			default:
+				d.compare(wv, gv, child)
+			}
+		}
+		return
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		for i := 0; i < len(w) || i < len(g); i++ {
+			length := max(len(w), len(g))
+			child := append(path[:len(path):len(path)], diffStep{index: i, isIndex: true, length: length})
+			switch {
+			case d.ignored(child):
+			case i >= len(g):
+				d.add(child, "missing, expected %s", compactJSON(w[i]))
+			case i >= len(w):
+				d.add(child, "unexpected %s", compactJSON(g[i]))
+			default:
+				d.compare(w[i], g[i], child)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(want, got) {
+		d.add(path, "expected %s, got %s", compactJSON(want), compactJSON(got))
+	}
+}
+
+func (d *jsonDiff) add(path []diffStep, format string, args ...interface{}) {
+	d.diffs = append(d.diffs, formatDiffPath(path)+": "+fmt.Sprintf(format, args...))
+}
+
+// ignored reports whether path matches one of the ignore patterns.
+func (d *jsonDiff) ignored(path []diffStep) bool {
+	for _, pattern := range d.ignore {
+		if matchDiffPath(pattern, path) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchDiffPath(pattern []pathSegment, path []diffStep) bool {
+	if len(pattern) != len(path) {
+		return false
+	}
+	for i, seg := range pattern {
+		step := path[i]
+		switch {
+		case seg.wildcard:
+		case seg.isIndex:
+			index := seg.index
+			if index < 0 {
+				index += step.length
+			}
+			if !step.isIndex || index != step.index {
+				return false
+			}
+		default:
+			if step.isIndex || seg.key != step.key {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func formatDiffPath(path []diffStep) string {
+	s := "$"
+	for _, step := range path {
+		if step.isIndex {
+			s += "[" + strconv.Itoa(step.index) + "]"
+		} else {
+			s = memberPath(s, step.key)
+		}
+	}
+	return s
+}
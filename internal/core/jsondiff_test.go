@@ -0,0 +1,54 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+func TestDiffJSON(t *testing.T) {
+	want := decodeJSON(t, `{"id":1,"result":{"serverInfo":{"name":"a","version":"1"},"items":[{"t":1},{"t":2},{"t":3}],"my key":true}}`)
+	got := decodeJSON(t, `{"id":1,"result":{"serverInfo":{"name":"b","version":"2"},"items":[{"t":1},{"t":5}],"extra":null,"my key":true}}`)
+
+	diffs, err := DiffJSON(want, got, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"$.result.extra: unexpected null",
+		"$.result.items[1].t: expected 2, got 5",
+		`$.result.items[2]: missing, expected {"t":3}`,
+		`$.result.serverInfo.name: expected "a", got "b"`,
+		`$.result.serverInfo.version: expected "1", got "2"`,
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("unexpected diffs:\n%q\nwant\n%q", diffs, expected)
+	}
+
+	diffs, err = DiffJSON(want, got, []string{"$.result.serverInfo.*", "$.result.items[-1]", "$.result.items[*].t", "$.result['extra']"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 0 {
+		t.Errorf("expected ignored paths to be skipped, got %q", diffs)
+	}
+
+	if diffs, _ := DiffJSON(want, want, nil); len(diffs) != 0 {
+		t.Errorf("expected no differences, got %q", diffs)
+	}
+	if diffs, _ := DiffJSON(map[string]interface{}{"a": 1}, []interface{}{}, nil); len(diffs) != 1 || diffs[0] != `$: expected {"a":1}, got []` {
+		t.Errorf("unexpected type mismatch diff %q", diffs)
+	}
+	if _, err := DiffJSON(nil, nil, []string{"result"}); err == nil {
+		t.Error("expected error for invalid ignore path")
+	}
+}
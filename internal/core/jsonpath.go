@@ -0,0 +1,167 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathSegment is one step of a compiled JSONPath expression.
+type pathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// EvalJSONPath evaluates a JSONPath expression against a decoded JSON
+// document. The supported subset covers the root ($), member access (.name
+// and ['name']), array indexes ([0], negative indexes count from the end) and
+// wildcards (.* and [*]). Without wildcards the single matched value is
+// returned; with wildcards the list of all matches is returned. found is
+// false when the path does not exist in the document.
+func EvalJSONPath(doc interface{}, path string) (value interface{}, found bool, err error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, false, err
+	}
+	current := []interface{}{doc}
+	wildcard := false
+	for _, seg := range segments {
+		var next []interface{}
+		for _, v := range current {
+			switch {
+			case seg.wildcard:
+				next = append(next, children(v)...)
+			case seg.isIndex:
+				arr, ok := v.([]interface{})
+				if !ok {
+					continue
+				}
+				i := seg.index
+				if i < 0 {
+					i += len(arr)
+				}
+				if i >= 0 && i < len(arr) {
+					next = append(next, arr[i])
+				}
+			default:
+				obj, ok := v.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if child, ok := obj[seg.key]; ok {
+					next = append(next, child)
+				}
+			}
+		}
+		if seg.wildcard {
+			wildcard = true
+		}
+		current = next
+	}
+	if wildcard {
+		if current == nil {
+			current = []interface{}{}
+		}
+		return current, true, nil
+	}
+	if len(current) != 1 {
+		return nil, false, nil
+	}
+	return current[0], true, nil
+}
+
+// children returns the members of an object, ordered by key, or the elements
+// of an array.
+func children(v interface{}) []interface{} {
+	switch t := v.(type) {
+	case []interface{}:
+		return t
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := make([]interface{}, 0, len(keys))
+		for _, k := range keys {
+			out = append(out, t[k])
+		}
+		return out
+	default:
+		return nil
+	}
+}
+
+// parseJSONPath compiles a JSONPath expression into segments.
+func parseJSONPath(path string) ([]pathSegment, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+	var segments []pathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty member name", path)
+			}
+			if name == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{key: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unterminated [", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			switch {
+			case inner == "*":
+				segments = append(segments, pathSegment{wildcard: true})
+			case len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0]:
+				segments = append(segments, pathSegment{key: inner[1 : len(inner)-1]})
+			default:
+				i, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid JSONPath %q: bad index %q", path, inner)
+				}
+				segments = append(segments, pathSegment{index: i, isIndex: true})
+			}
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// closingBracket returns the index of the ] closing the bracket expression at
+// the start of s, skipping over quoted member names.
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEvalJSONPath(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"result":{"tools":[{"name":"a"},{"name":"b"}],"odd key":1,"meta":{"y":2,"x":1}}}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		path  string
+		want  interface{}
+		found bool
+	}{
+		{"$", doc, true},
+		{"$.result.tools[0].name", "a", true},
+		{"$.result.tools[-1].name", "b", true},
+		{"$['result']['odd key']", float64(1), true},
+		{"$.result.tools[*].name", []interface{}{"a", "b"}, true},
+		{"$.result.meta.*", []interface{}{float64(1), float64(2)}, true},
+		{"$.result.missing[*]", []interface{}{}, true},
+		{"$.result.tools[5]", nil, false},
+		{"$.result.missing", nil, false},
+	}
+	for _, c := range cases {
+		got, found, err := EvalJSONPath(doc, c.path)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", c.path, err)
+			continue
+		}
+		if found != c.found || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s => %v (%v), want %v (%v)", c.path, got, found, c.want, c.found)
+		}
+	}
+}
+
+func TestEvalJSONPath_Invalid(t *testing.T) {
+	for _, path := range []string{"", "result", "$.", "$[0", "$[x]", "$x"} {
+		if _, _, err := EvalJSONPath(nil, path); err == nil {
+			t.Errorf("expected error for %q", path)
+		}
+	}
+}
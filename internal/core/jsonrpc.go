@@ -0,0 +1,178 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// JSONRPCVersion is the protocol version emitted in every message.
+const JSONRPCVersion = "2.0"
+
+// Standard JSON-RPC 2.0 error codes.
+const (
+	ParseError     = -32700
+	InvalidRequest = -32600
+	MethodNotFound = -32601
+	InvalidParams  = -32602
+	InternalError  = -32603
+)
+
+// ResourceNotFound is the MCP error code for reads of unknown resources.
+const ResourceNotFound = -32002
+
+// IsNotification reports whether the request carries no ID and therefore
+// expects no response.
+func (r *Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+// MarshalJSON always emits the jsonrpc member and only emits the id member
+// for requests, so that an ID of zero is never confused with a notification.
+func (r Request) MarshalJSON() ([]byte, error) {
+	if err := validateID(r.ID); err != nil {
+		return nil, err
+	}
+	type request struct {
+		JSONRPC string                 `json:"jsonrpc"`
+		Method  string                 `json:"method"`
+		Params  map[string]interface{} `json:"params,omitempty"`
+		ID      interface{}            `json:"id,omitempty"`
+	}
+	return json.Marshal(request{JSONRPC: JSONRPCVersion, Method: r.Method, Params: r.Params, ID: r.ID})
+}
+
+// MarshalJSON always emits the jsonrpc and id members. The result member is
+// emitted, even when null, unless the response carries an error.
+func (r Response) MarshalJSON() ([]byte, error) {
+	if err := validateID(r.ID); err != nil {
+		return nil, err
+	}
+	if r.Error != nil {
+		type errorResponse struct {
+			JSONRPC string      `json:"jsonrpc"`
+			ID      interface{} `json:"id"`
+			Error   *Error      `json:"error"`
+		}
+		return json.Marshal(errorResponse{JSONRPC: JSONRPCVersion, ID: r.ID, Error: r.Error})
+	}
+	type resultResponse struct {
+		JSONRPC string      `json:"jsonrpc"`
+		ID      interface{} `json:"id"`
+		Result  interface{} `json:"result"`
+	}
+	return json.Marshal(resultResponse{JSONRPC: JSONRPCVersion, ID: r.ID, Result: r.Result})
+}
+
+// Error implements the error interface so MCP errors can be returned directly.
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+}
+
+// CheckProtocol reports JSON-RPC 2.0 violations in a decoded response: a
+// wrong jsonrpc member, a response carrying both or neither of result and
+// error, and error codes from the reserved range that the specification does
+// not define.
+func (r *Response) CheckProtocol() error {
+	if r.JSONRPC != JSONRPCVersion {
+		return fmt.Errorf("jsonrpc must be %q, got %q", JSONRPCVersion, r.JSONRPC)
+	}
+	if r.Error != nil && r.Result != nil {
+		return fmt.Errorf("response carries both result and error")
+	}
+	if r.Error == nil && r.Result == nil {
+		return fmt.Errorf("response carries neither result nor error")
+	}
+	if r.Error != nil {
+		code := r.Error.Code
+		switch {
+		case code == ParseError, code >= InternalError && code <= InvalidRequest:
+		case code >= -32768 && code <= -32100:
+			return fmt.Errorf("error code %d is reserved by JSON-RPC", code)
+		}
+	}
+	return nil
+}
+
+// NewNotification creates a request without an ID.
+func NewNotification(method string, params map[string]interface{}) *Request {
+	return &Request{JSONRPC: JSONRPCVersion, Method: method, Params: params}
+}
+
+// NewErrorResponse creates a response carrying a JSON-RPC error.
+func NewErrorResponse(id interface{}, code int, message string, data interface{}) *Response {
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Error: &Error{Code: code, Message: message, Data: data}}
+}
+
+// validateID checks that an ID is a string, a number or absent.
+func validateID(id interface{}) error {
+	if id == nil {
+		return nil
+	}
+	if _, ok := idKey(id); !ok {
+		return fmt.Errorf("invalid JSON-RPC id %v: must be a string or number", id)
+	}
+	return nil
+}
+
+// IDKey returns a canonical representation of a JSON-RPC ID so that numeric
+// IDs compare equal regardless of their Go type (int, float64, json.Number)
+// while string IDs stay distinct from numbers. It returns an empty string for
+// nil or invalid IDs.
+func IDKey(id interface{}) string {
+	key, _ := idKey(id)
+	return key
+}
+
+func idKey(id interface{}) (string, bool) {
+	switch v := id.(type) {
+	case string:
+		return strconv.Quote(v), true
+	case int:
+		return strconv.FormatInt(int64(v), 10), true
+	case int8:
+		return strconv.FormatInt(int64(v), 10), true
+	case int16:
+		return strconv.FormatInt(int64(v), 10), true
+	case int32:
+		return strconv.FormatInt(int64(v), 10), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint8:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint16:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint32:
+		return strconv.FormatUint(uint64(v), 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float32:
+		return formatFloatID(float64(v))
+	case float64:
+		return formatFloatID(v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return strconv.FormatInt(i, 10), true
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return "", false
+		}
+		return formatFloatID(f)
+	default:
+		return "", false
+	}
+}
+
+func formatFloatID(f float64) (string, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", false
+	}
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return strconv.FormatInt(int64(f), 10), true
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64), true
+}
@@ -0,0 +1,133 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestRequestMarshalEnvelope(t *testing.T) {
+	cases := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"zero id", Request{Method: "ping", ID: 0}, `{"jsonrpc":"2.0","method":"ping","id":0}`},
+		{"string id", Request{Method: "ping", ID: "abc"}, `{"jsonrpc":"2.0","method":"ping","id":"abc"}`},
+		{"notification", Request{Method: "notifications/initialized"}, `{"jsonrpc":"2.0","method":"notifications/initialized"}`},
+	}
+	for _, c := range cases {
+		data, err := json.Marshal(c.req)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", c.name, err)
+		}
+		if string(data) != c.want {
+			t.Errorf("%s: got %s, want %s", c.name, data, c.want)
+		}
+	}
+}
+
+func TestRequestMarshalInvalidID(t *testing.T) {
+	if _, err := json.Marshal(Request{Method: "x", ID: true}); err == nil {
+		t.Fatal("expected error for boolean id")
+	}
+}
+
+func TestRequestUnmarshalNotification(t *testing.T) {
+	var req Request
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","method":"notifications/cancelled"}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if !req.IsNotification() || req.JSONRPC != "2.0" {
+		t.Errorf("expected notification, got %+v", req)
+	}
+	if err := json.Unmarshal([]byte(`{"jsonrpc":"2.0","method":"ping","id":0}`), &req); err != nil {
+		t.Fatal(err)
+	}
+	if req.IsNotification() {
+		t.Error("request with id 0 must not be a notification")
+	}
+}
+
+func TestResponseMarshalEnvelope(t *testing.T) {
+	data, err := json.Marshal(Response{ID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","id":1,"result":null}` {
+		t.Errorf("unexpected result response %s", data)
+	}
+	data, err = json.Marshal(*NewErrorResponse(nil, ParseError, "Parse error", map[string]interface{}{"offset": 3}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error","data":{"offset":3}}}` {
+		t.Errorf("unexpected error response %s", data)
+	}
+}
+
+func TestErrorDataRoundTrip(t *testing.T) {
+	var resp Response
+	raw := `{"jsonrpc":"2.0","id":"r1","error":{"code":-32602,"message":"bad","data":{"field":"uri"}}}`
+	if err := json.Unmarshal([]byte(raw), &resp); err != nil {
+		t.Fatal(err)
+	}
+	data, ok := resp.Error.Data.(map[string]interface{})
+	if !ok || data["field"] != "uri" {
+		t.Fatalf("error data not decoded: %#v", resp.Error.Data)
+	}
+	out, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != raw {
+		t.Errorf("round trip mismatch:\n got %s\nwant %s", out, raw)
+	}
+	if !strings.Contains(resp.Error.Error(), "bad (code -32602)") {
+		t.Errorf("unexpected error string %q", resp.Error.Error())
+	}
+}
+
+func TestIDKey(t *testing.T) {
+	numeric := []interface{}{1, int64(1), uint8(1), float64(1), json.Number("1")}
+	for _, id := range numeric {
+		if IDKey(id) != "1" {
+			t.Errorf("IDKey(%#v) = %q, want 1", id, IDKey(id))
+		}
+	}
+	if IDKey("1") == IDKey(1) {
+		t.Error("string and numeric ids must not collide")
+	}
+	if IDKey(1.5) != "1.5" {
+		t.Errorf("unexpected key for fractional id: %s", IDKey(1.5))
+	}
+	if IDKey(nil) != "" || IDKey([]int{1}) != "" {
+		t.Error("expected empty key for nil or invalid ids")
+	}
+}
+
+func TestResponseCheckProtocol(t *testing.T) {
+	tests := []struct {
+		name string
+		resp Response
+		want string
+	}{
+		{"result", Response{JSONRPC: JSONRPCVersion, ID: 1, Result: map[string]interface{}{}}, ""},
+		{"predefined error", Response{JSONRPC: JSONRPCVersion, ID: 1, Error: &Error{Code: InvalidParams}}, ""},
+		{"server error", Response{JSONRPC: JSONRPCVersion, ID: 1, Error: &Error{Code: -32000}}, ""},
+		{"application error", Response{JSONRPC: JSONRPCVersion, ID: 1, Error: &Error{Code: -32002}}, ""},
+		{"version", Response{JSONRPC: "1.0", ID: 1, Result: 1}, "jsonrpc must be"},
+		{"both", Response{JSONRPC: JSONRPCVersion, ID: 1, Result: 1, Error: &Error{Code: InternalError}}, "both"},
+		{"neither", Response{JSONRPC: JSONRPCVersion, ID: 1}, "neither"},
+		{"reserved code", Response{JSONRPC: JSONRPCVersion, ID: 1, Error: &Error{Code: -32500}}, "reserved"},
+	}
+	for _, tt := range tests {
+		err := tt.resp.CheckProtocol()
+		switch {
+		case tt.want == "" && err != nil:
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
@@ -0,0 +1,671 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxSchemaDepth bounds the nesting followed through $ref while validating
+// or sampling, so that recursive schemas terminate.
+const maxSchemaDepth = 32
+
+// Schema is a compiled JSON Schema. It supports the keywords found in MCP tool
+// schemas: type, enum, const, the numeric, string, array and object
+// constraints, the allOf, anyOf, oneOf and not combinators and local $ref
+// references into $defs or definitions. format is treated as an annotation
+// and unknown keywords are ignored, as the specification requires. Patterns
+// are compiled as RE2 regular expressions: those using ECMA-262 features RE2
+// lacks, such as lookahead or backreferences, are skipped and listed in
+// Unsupported instead of failing compilation.
+type Schema struct {
+	// always is set for the boolean schemas true and false.
+	always *bool
+
+	Types    []string
+	Enum     []interface{}
+	Const    interface{}
+	HasConst bool
+	Default  interface{}
+	Examples []interface{}
+	Format   string
+
+	Minimum          *float64
+	Maximum          *float64
+	ExclusiveMinimum *float64
+	ExclusiveMaximum *float64
+	MultipleOf       *float64
+
+	MinLength *int
+	MaxLength *int
+	Pattern   *regexp.Regexp
+
+	Items       *Schema
+	PrefixItems []*Schema
+	MinItems    *int
+	MaxItems    *int
+	UniqueItems bool
+
+	Properties           map[string]*Schema
+	PatternProperties    map[*regexp.Regexp]*Schema
+	AdditionalProperties *Schema
+	Required             []string
+	MinProperties        *int
+	MaxProperties        *int
+
+	AllOf []*Schema
+	AnyOf []*Schema
+	OneOf []*Schema
+	Not   *Schema
+
+	// Ref is the $ref of the schema and ref the schema it resolves to.
+	Ref string
+	ref *Schema
+
+	// Unsupported lists, on the schema returned by CompileSchema, the
+	// keywords skipped by location and why. Values are not checked against
+	// them.
+	Unsupported []string
+}
+
+// SchemaError reports an invalid schema, with the JSON pointer of the
+// offending keyword.
+type SchemaError struct {
+	Location string
+	Message  string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("invalid schema at %s: %s", e.Location, e.Message)
+}
+
+// ParseSchema decodes and compiles a JSON Schema document.
+func ParseSchema(data []byte) (*Schema, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, FormatJSONError(data, err, "failed to parse schema")
+	}
+	return CompileSchema(doc)
+}
+
+// CompileSchema compiles a decoded JSON Schema document, checking that the
+// schema itself is valid.
+func CompileSchema(doc interface{}) (*Schema, error) {
+	c := &schemaCompiler{root: normalizeJSON(doc), resolved: map[string]*Schema{}}
+	s, err := c.compile(c.root, "#")
+	if err != nil {
+		return nil, err
+	}
+	for len(c.pending) > 0 {
+		p := c.pending[0]
+		c.pending = c.pending[1:]
+		target, err := c.resolve(p.schema.Ref, p.location)
+		if err != nil {
+			return nil, err
+		}
+		p.schema.ref = target
+	}
+	sort.Strings(c.unsupported)
+	s.Unsupported = c.unsupported
+	return s, nil
+}
+
+type pendingRef struct {
+	schema   *Schema
+	location string
+}
+
+type schemaCompiler struct {
+	root     interface{}
+	resolved map[string]*Schema
+	pending  []pendingRef
+	// unsupported lists the keywords skipped while compiling.
+	unsupported []string
+}
+
+// resolve compiles the target of a local $ref once.
+func (c *schemaCompiler) resolve(ref, location string) (*Schema, error) {
+	if s, ok := c.resolved[ref]; ok {
+		return s, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, &SchemaError{location, fmt.Sprintf("unsupported $ref %q: only local references are resolved", ref)}
+	}
+	target := c.root
+	if pointer := strings.TrimPrefix(ref, "#"); pointer != "" {
+		if !strings.HasPrefix(pointer, "/") {
+			return nil, &SchemaError{location, fmt.Sprintf("unsupported $ref %q", ref)}
+		}
+		for _, token := range strings.Split(pointer[1:], "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			switch t := target.(type) {
+			case map[string]interface{}:
+				next, ok := t[token]
+				if !ok {
+					return nil, &SchemaError{location, fmt.Sprintf("$ref %q does not resolve", ref)}
+				}
+				target = next
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(t) {
+					return nil, &SchemaError{location, fmt.Sprintf("$ref %q does not resolve", ref)}
+				}
+				target = t[i]
+			default:
+				return nil, &SchemaError{location, fmt.Sprintf("$ref %q does not resolve", ref)}
+			}
+		}
+	}
+	s, err := c.compile(target, ref)
+	if err != nil {
+		return nil, err
+	}
+	c.resolved[ref] = s
+	return s, nil
+}
+
+// skip records a keyword of the schema at loc left out of validation.
+func (c *schemaCompiler) skip(loc, keyword, format string, args ...interface{}) {
+	c.unsupported = append(c.unsupported, fmt.Sprintf("%s/%s: %s", loc, keyword, fmt.Sprintf(format, args...)))
+}
+
+func (c *schemaCompiler) compile(doc interface{}, loc string) (*Schema, error) {
+	if b, ok := doc.(bool); ok {
+		return &Schema{always: &b}, nil
+	}
+	obj, ok := doc.(map[string]interface{})
+	if !ok {
+		return nil, &SchemaError{loc, fmt.Sprintf("schema must be an object or a boolean, got %s", jsonType(doc))}
+	}
+	s := &Schema{}
+	fail := func(keyword, format string, args ...interface{}) error {
+		return &SchemaError{loc + "/" + keyword, fmt.Sprintf(format, args...)}
+	}
+
+	if v, ok := obj["type"]; ok {
+		switch t := v.(type) {
+		case string:
+			s.Types = []string{t}
+		case []interface{}:
+			for _, item := range t {
+				name, ok := item.(string)
+				if !ok {
+					return nil, fail("type", "must be a string or an array of strings")
+				}
+				s.Types = append(s.Types, name)
+			}
+		default:
+			return nil, fail("type", "must be a string or an array of strings")
+		}
+		for _, name := range s.Types {
+			if !containsString(jsonTypes, name) {
+				return nil, fail("type", "unknown type %q, valid types are %v", name, jsonTypes)
+			}
+		}
+	}
+	if v, ok := obj["enum"]; ok {
+		values, ok := v.([]interface{})
+		if !ok || len(values) == 0 {
+			return nil, fail("enum", "must be a non-empty array")
+		}
+		s.Enum = values
+	}
+	if v, ok := obj["const"]; ok {
+		s.Const, s.HasConst = v, true
+	}
+	s.Default = obj["default"]
+	if v, ok := obj["examples"]; ok {
+		values, ok := v.([]interface{})
+		if !ok {
+			return nil, fail("examples", "must be an array")
+		}
+		s.Examples = values
+	}
+	for _, keyword := range []string{"title", "description", "format", "$comment", "$schema", "$id"} {
+		if v, ok := obj[keyword]; ok {
+			if _, ok := v.(string); !ok {
+				return nil, fail(keyword, "must be a string")
+			}
+		}
+	}
+	s.Format, _ = obj["format"].(string)
+
+	numbers := []struct {
+		keyword string
+		dst     **float64
+	}{
+		{"minimum", &s.Minimum},
+		{"maximum", &s.Maximum},
+		{"multipleOf", &s.MultipleOf},
+	}
+	for _, n := range numbers {
+		if v, ok := obj[n.keyword]; ok {
+			f, ok := v.(float64)
+			if !ok {
+				return nil, fail(n.keyword, "must be a number")
+			}
+			*n.dst = &f
+		}
+	}
+	if s.MultipleOf != nil && *s.MultipleOf <= 0 {
+		return nil, fail("multipleOf", "must be greater than 0")
+	}
+	// exclusiveMinimum and exclusiveMaximum are numbers since draft 6 and
+	// booleans modifying minimum and maximum in draft 4.
+	exclusive := []struct {
+		keyword string
+		bound   *float64
+		dst     **float64
+		plain   **float64
+	}{
+		{"exclusiveMinimum", s.Minimum, &s.ExclusiveMinimum, &s.Minimum},
+		{"exclusiveMaximum", s.Maximum, &s.ExclusiveMaximum, &s.Maximum},
+	}
+	for _, e := range exclusive {
+		switch v := obj[e.keyword].(type) {
+		case nil:
+		case float64:
+			*e.dst = &v
+		case bool:
+			if v {
+				if e.bound == nil {
+					return nil, fail(e.keyword, "boolean form requires %s", strings.ToLower(strings.TrimPrefix(e.keyword, "exclusive")))
+				}
+				*e.dst, *e.plain = e.bound, nil
+			}
+		default:
+			return nil, fail(e.keyword, "must be a number")
+		}
+	}
+
+	counts := []struct {
+		keyword string
+		dst     **int
+	}{
+		{"minLength", &s.MinLength},
+		{"maxLength", &s.MaxLength},
+		{"minItems", &s.MinItems},
+		{"maxItems", &s.MaxItems},
+		{"minProperties", &s.MinProperties},
+		{"maxProperties", &s.MaxProperties},
+	}
+	for _, n := range counts {
+		if v, ok := obj[n.keyword]; ok {
+			f, ok := v.(float64)
+			if !ok || f < 0 || f != math.Trunc(f) {
+				return nil, fail(n.keyword, "must be a non-negative integer")
+			}
+			i := int(f)
+			*n.dst = &i
+		}
+	}
+	if v, ok := obj["pattern"]; ok {
+		pattern, ok := v.(string)
+		if !ok {
+			return nil, fail("pattern", "must be a string")
+		}
+		if re, err := regexp.Compile(pattern); err != nil {
+			c.skip(loc, "pattern", "%q is not an RE2 regular expression: %v", pattern, err)
+		} else {
+			s.Pattern = re
+		}
+	}
+	if v, ok := obj["uniqueItems"]; ok {
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fail("uniqueItems", "must be a boolean")
+		}
+		s.UniqueItems = b
+	}
+	if v, ok := obj["required"]; ok {
+		list, ok := v.([]interface{})
+		if !ok {
+			return nil, fail("required", "must be an array of strings")
+		}
+		for _, item := range list {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fail("required", "must be an array of strings")
+			}
+			if containsString(s.Required, name) {
+				return nil, fail("required", "duplicate property %q", name)
+			}
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	var err error
+	sub := func(keyword string) (*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		return c.compile(v, loc+"/"+keyword)
+	}
+	subList := func(keyword string) ([]*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		list, ok := v.([]interface{})
+		if !ok || len(list) == 0 {
+			return nil, fail(keyword, "must be a non-empty array of schemas")
+		}
+		out := make([]*Schema, len(list))
+		for i, item := range list {
+			if out[i], err = c.compile(item, fmt.Sprintf("%s/%s/%d", loc, keyword, i)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+	subMap := func(keyword string) (map[string]*Schema, error) {
+		v, ok := obj[keyword]
+		if !ok {
+			return nil, nil
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, fail(keyword, "must be an object")
+		}
+		out := make(map[string]*Schema, len(m))
+		for name, item := range m {
+			if out[name], err = c.compile(item, loc+"/"+keyword+"/"+escapePointer(name)); err != nil {
+				return nil, err
+			}
+		}
+		return out, nil
+	}
+
+	if items, ok := obj["items"].([]interface{}); ok {
+		// Draft 4 tuple form.
+		if len(items) > 0 {
+			if s.PrefixItems, err = subList("items"); err != nil {
+				return nil, err
+			}
+		}
+		if s.Items, err = sub("additionalItems"); err != nil {
+			return nil, err
+		}
+	} else {
+		if s.Items, err = sub("items"); err != nil {
+			return nil, err
+		}
+		if s.PrefixItems, err = subList("prefixItems"); err != nil {
+			return nil, err
+		}
+	}
+	if s.Properties, err = subMap("properties"); err != nil {
+		return nil, err
+	}
+	patterns, err := subMap("patternProperties")
+	if err != nil {
+		return nil, err
+	}
+	skipped := false
+	for pattern, schema := range patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			c.skip(loc, "patternProperties", "%q is not an RE2 regular expression: %v", pattern, err)
+			skipped = true
+			continue
+		}
+		if s.PatternProperties == nil {
+			s.PatternProperties = map[*regexp.Regexp]*Schema{}
+		}
+		s.PatternProperties[re] = schema
+	}
+	if s.AdditionalProperties, err = sub("additionalProperties"); err != nil {
+		return nil, err
+	}
+	if skipped && s.AdditionalProperties != nil {
+		// The properties it applies to depend on the skipped patterns.
+		c.skip(loc, "additionalProperties", "skipped along with the unsupported patterns of patternProperties")
+		s.AdditionalProperties = nil
+	}
+	if s.AllOf, err = subList("allOf"); err != nil {
+		return nil, err
+	}
+	if s.AnyOf, err = subList("anyOf"); err != nil {
+		return nil, err
+	}
+	if s.OneOf, err = subList("oneOf"); err != nil {
+		return nil, err
+	}
+	if s.Not, err = sub("not"); err != nil {
+		return nil, err
+	}
+	// Definitions are compiled so that errors in them are reported even when
+	// they are not referenced.
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, err := subMap(keyword)
+		if err != nil {
+			return nil, err
+		}
+		for name, def := range defs {
+			ref := loc + "/" + keyword + "/" + escapePointer(name)
+			if _, ok := c.resolved[ref]; !ok {
+				c.resolved[ref] = def
+			}
+		}
+	}
+	if v, ok := obj["$ref"]; ok {
+		ref, ok := v.(string)
+		if !ok {
+			return nil, fail("$ref", "must be a string")
+		}
+		s.Ref = ref
+		c.pending = append(c.pending, pendingRef{s, loc + "/$ref"})
+	}
+	return s, nil
+}
+
+func escapePointer(token string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(token)
+}
+
+// Validate checks value against the schema and returns one error per
+// violation, each prefixed with the JSONPath of the offending value.
+func (s *Schema) Validate(value interface{}) []error {
+	return s.validate(normalizeJSON(value), "$", 0)
+}
+
+func (s *Schema) validate(v interface{}, path string, depth int) []error {
+	if depth > maxSchemaDepth {
+		return []error{fmt.Errorf("%s: schema nesting too deep", path)}
+	}
+	if s.always != nil {
+		if *s.always {
+			return nil
+		}
+		return []error{fmt.Errorf("%s: no value is allowed", path)}
+	}
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+	}
+	if s.ref != nil {
+		errs = append(errs, s.ref.validate(v, path, depth+1)...)
+	}
+
+	if len(s.Types) > 0 {
+		got := jsonType(v)
+		matched := false
+		for _, t := range s.Types {
+			if t == got || (t == "number" && got == "integer") {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %s, got %s", strings.Join(s.Types, " or "), got)
+			return errs
+		}
+	}
+	if s.Enum != nil && !containsValue(s.Enum, v) {
+		fail("%s is not one of %s", compactJSON(v), compactJSON(s.Enum))
+	}
+	if s.HasConst && !reflect.DeepEqual(s.Const, v) {
+		fail("expected %s, got %s", compactJSON(s.Const), compactJSON(v))
+	}
+
+	switch t := v.(type) {
+	case float64:
+		if s.Minimum != nil && t < *s.Minimum {
+			fail("%v is less than the minimum %v", t, *s.Minimum)
+		}
+		if s.Maximum != nil && t > *s.Maximum {
+			fail("%v is greater than the maximum %v", t, *s.Maximum)
+		}
+		if s.ExclusiveMinimum != nil && t <= *s.ExclusiveMinimum {
+			fail("%v must be greater than %v", t, *s.ExclusiveMinimum)
+		}
+		if s.ExclusiveMaximum != nil && t >= *s.ExclusiveMaximum {
+			fail("%v must be less than %v", t, *s.ExclusiveMaximum)
+		}
+		if s.MultipleOf != nil {
+			if q := t / *s.MultipleOf; math.Abs(q-math.Round(q)) > 1e-9 {
+				fail("%v is not a multiple of %v", t, *s.MultipleOf)
+			}
+		}
+	case string:
+		n := utf8.RuneCountInString(t)
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("length %d is less than the minimum %d", n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("length %d is greater than the maximum %d", n, *s.MaxLength)
+		}
+		if s.Pattern != nil && !s.Pattern.MatchString(t) {
+			fail("%q does not match %q", t, s.Pattern.String())
+		}
+	case []interface{}:
+		if s.MinItems != nil && len(t) < *s.MinItems {
+			fail("%d items is less than the minimum %d", len(t), *s.MinItems)
+		}
+		if s.MaxItems != nil && len(t) > *s.MaxItems {
+			fail("%d items is more than the maximum %d", len(t), *s.MaxItems)
+		}
+		if s.UniqueItems {
+			for i := 1; i < len(t); i++ {
+				if containsValue(t[:i], t[i]) {
+					fail("item %d is a duplicate", i)
+					break
+				}
+			}
+		}
+		for i, item := range t {
+			itemPath := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i < len(s.PrefixItems):
+				errs = append(errs, s.PrefixItems[i].validate(item, itemPath, depth+1)...)
+			case s.Items != nil:
+				errs = append(errs, s.Items.validate(item, itemPath, depth+1)...)
+			}
+		}
+	case map[string]interface{}:
+		if s.MinProperties != nil && len(t) < *s.MinProperties {
+			fail("%d properties is less than the minimum %d", len(t), *s.MinProperties)
+		}
+		if s.MaxProperties != nil && len(t) > *s.MaxProperties {
+			fail("%d properties is more than the maximum %d", len(t), *s.MaxProperties)
+		}
+		for _, name := range s.Required {
+			if _, ok := t[name]; !ok {
+				fail("missing required property %q", name)
+			}
+		}
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			propPath := memberPath(path, k)
+			matched := false
+			if prop, ok := s.Properties[k]; ok {
+				matched = true
+				errs = append(errs, prop.validate(t[k], propPath, depth+1)...)
+			}
+			for re, prop := range s.PatternProperties {
+				if re.MatchString(k) {
+					matched = true
+					errs = append(errs, prop.validate(t[k], propPath, depth+1)...)
+				}
+			}
+			if !matched && s.AdditionalProperties != nil {
+				if a := s.AdditionalProperties.always; a != nil && !*a {
+					fail("unexpected property %q", k)
+					continue
+				}
+				errs = append(errs, s.AdditionalProperties.validate(t[k], propPath, depth+1)...)
+			}
+		}
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, sub.validate(v, path, depth+1)...)
+	}
+	if s.AnyOf != nil {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if len(sub.validate(v, path, depth+1)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("does not match any schema of anyOf")
+		}
+	}
+	if s.OneOf != nil {
+		matches := 0
+		for _, sub := range s.OneOf {
+			if len(sub.validate(v, path, depth+1)) == 0 {
+				matches++
+			}
+		}
+		if matches != 1 {
+			fail("matches %d schemas of oneOf instead of exactly one", matches)
+		}
+	}
+	if s.Not != nil && len(s.Not.validate(v, path, depth+1)) == 0 {
+		fail("must not match the schema of not")
+	}
+	return errs
+}
+
+// memberPath appends an object member to a JSONPath.
+func memberPath(path, name string) string {
+	if identifierPattern.MatchString(name) {
+		return path + "." + name
+	}
+	return path + "[" + strconv.Quote(name) + "]"
+}
+
+var identifierPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func containsValue(list []interface{}, v interface{}) bool {
+	for _, item := range list {
+		if reflect.DeepEqual(item, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts reports whether value conforms to the schema.
+func (s *Schema) Accepts(value interface{}) bool {
+	return len(s.Validate(value)) == 0
+}
+
+// IsType reports whether the schema requires values of the given JSON type.
+func (s *Schema) IsType(name string) bool {
+	return len(s.Types) == 1 && s.Types[0] == name
+}
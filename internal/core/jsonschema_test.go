@@ -0,0 +1,200 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseSchema_Invalid(t *testing.T) {
+	cases := map[string]string{
+		`[]`:                                       "must be an object or a boolean",
+		`{"type":"strng"}`:                         `#/type: unknown type "strng"`,
+		`{"type":["string",1]}`:                    "#/type: must be a string or an array",
+		`{"properties":{"a":{"type":1}}}`:          "#/properties/a/type",
+		`{"required":"a"}`:                         "#/required: must be an array of strings",
+		`{"required":["a","a"]}`:                   "duplicate property",
+		`{"enum":[]}`:                              "#/enum: must be a non-empty array",
+		`{"minLength":-1}`:                         "must be a non-negative integer",
+		`{"maxItems":1.5}`:                         "must be a non-negative integer",
+		`{"multipleOf":0}`:                         "must be greater than 0",
+		`{"items":{"anyOf":[]}}`:                   "#/items/anyOf: must be a non-empty array",
+		`{"$ref":"#/$defs/missing"}`:               "does not resolve",
+		`{"$ref":"https://example.com/s.json"}`:    "only local references",
+		`{"$defs":{"a":{"type":"bogus"}}}`:         "#/$defs/a/type",
+		`{"exclusiveMinimum":true}`:                "boolean form requires minimum",
+		`{"description":5}`:                        "#/description: must be a string",
+		`{"additionalProperties":{"minimum":"1"}}`: "#/additionalProperties/minimum: must be a number",
+	}
+	for input, want := range cases {
+		if _, err := ParseSchema([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+	if _, err := ParseSchema([]byte(`{`)); err == nil {
+		t.Error("expected error for invalid JSON")
+	}
+}
+
+func TestParseSchema_UnsupportedPatterns(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {"password": {"type": "string", "pattern": "^(?=.*\\d).+$"}},
+		"patternProperties": {"^x-(\\w)\\1$": {"type": "number"}},
+		"additionalProperties": false
+	}`))
+	if err != nil {
+		t.Fatalf("patterns RE2 cannot compile must not fail the schema: %v", err)
+	}
+	want := []string{
+		"#/additionalProperties: skipped along with the unsupported patterns of patternProperties",
+		`#/patternProperties: "^x-(\\w)\\1$" is not an RE2 regular expression`,
+		`#/properties/password/pattern: "^(?=.*\\d).+$" is not an RE2 regular expression`,
+	}
+	if len(schema.Unsupported) != len(want) {
+		t.Fatalf("expected %d unsupported keywords, got %q", len(want), schema.Unsupported)
+	}
+	for i, w := range want {
+		if !strings.HasPrefix(schema.Unsupported[i], w) {
+			t.Errorf("unsupported[%d] = %q, want prefix %q", i, schema.Unsupported[i], w)
+		}
+	}
+	if errs := schema.Validate(map[string]interface{}{"password": "secret1", "x-aa": 1}); len(errs) != 0 {
+		t.Errorf("skipped keywords must not reject values: %v", errs)
+	}
+	if errs := schema.Validate(map[string]interface{}{"password": 1}); len(errs) != 1 {
+		t.Errorf("the other keywords still apply, got %v", errs)
+	}
+}
+
+func TestSchema_Validate(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 2, "maxLength": 4, "pattern": "^[a-z]+$"},
+			"count": {"type": "integer", "minimum": 1, "exclusiveMaximum": 10, "multipleOf": 2},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "uniqueItems": true},
+			"mode": {"enum": ["fast", "slow"]},
+			"node": {"$ref": "#/$defs/node"},
+			"choice": {"oneOf": [{"type": "string"}, {"type": "number", "minimum": 0}]},
+			"fixed": {"const": true}
+		},
+		"required": ["name"],
+		"additionalProperties": false,
+		"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]interface{}{
+		"name":   "abc",
+		"count":  4,
+		"tags":   []string{"x", "y"},
+		"mode":   "fast",
+		"node":   map[string]interface{}{"next": map[string]interface{}{}},
+		"choice": "s",
+		"fixed":  true,
+	}
+	if errs := schema.Validate(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	invalid := map[string]interface{}{
+		"count":  3.5,
+		"tags":   []interface{}{"x", "x"},
+		"mode":   "medium",
+		"node":   map[string]interface{}{"next": "end"},
+		"choice": -1,
+		"fixed":  false,
+		"extra":  1,
+	}
+	errs := schema.Validate(invalid)
+	var messages []string
+	for _, e := range errs {
+		messages = append(messages, e.Error())
+	}
+	joined := strings.Join(messages, "\n")
+	for _, want := range []string{
+		`$: missing required property "name"`,
+		"$.count: expected integer, got number",
+		"$.tags: item 1 is a duplicate",
+		`$.mode: "medium" is not one of ["fast","slow"]`,
+		"$.node.next: expected object, got string",
+		"$.choice: matches 0 schemas of oneOf",
+		"$.fixed: expected true, got false",
+		`$: unexpected property "extra"`,
+	} {
+		if !strings.Contains(joined, want) {
+			t.Errorf("missing %q in:\n%s", want, joined)
+		}
+	}
+
+	if errs := schema.Validate(map[string]interface{}{"name": "ABCDE", "count": 10}); len(errs) != 3 {
+		t.Errorf("expected length, pattern and maximum errors, got %v", errs)
+	}
+}
+
+func TestSchema_ValidateDraft4ExclusiveBounds(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"type":"number","minimum":0,"exclusiveMinimum":true}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schema.Accepts(0) || !schema.Accepts(0.5) {
+		t.Error("exclusiveMinimum: true should exclude the minimum")
+	}
+}
+
+func TestSchema_BooleanSchemas(t *testing.T) {
+	schema, err := ParseSchema([]byte(`{"properties":{"any":true,"none":false}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !schema.Accepts(map[string]interface{}{"any": []int{1}}) {
+		t.Error("true schema should accept anything")
+	}
+	if schema.Accepts(map[string]interface{}{"none": 1}) {
+		t.Error("false schema should reject everything")
+	}
+}
+
+func TestSchema_Sample(t *testing.T) {
+	cases := []string{
+		`{"type":"object"}`,
+		`{"type":"object","properties":{"message":{"type":"string"}},"required":["message"]}`,
+		`{"type":"object","properties":{"n":{"type":"integer","exclusiveMinimum":5,"maximum":9,"multipleOf":3}},"required":["n"]}`,
+		`{"type":"object","properties":{"x":{"type":"number","minimum":-2.5,"maximum":-1.5}},"required":["x"]}`,
+		`{"type":"object","properties":{"code":{"type":"string","pattern":"^[A-Z]{3}-\\d+$"}},"required":["code"]}`,
+		`{"type":"object","properties":{"id":{"type":"string","format":"uuid"},"when":{"type":"string","format":"date-time"}},"required":["id","when"]}`,
+		`{"type":"object","properties":{"s":{"type":"string","minLength":10,"maxLength":12}},"required":["s"]}`,
+		`{"type":"object","properties":{"l":{"type":"array","items":{"enum":["a","b","c"]},"minItems":3,"uniqueItems":true}},"required":["l"]}`,
+		`{"type":"object","properties":{"mode":{"enum":["on","off"]},"flag":{"const":false}},"required":["mode","flag"]}`,
+		`{"type":"object","properties":{"v":{"oneOf":[{"type":"string","maxLength":0,"minLength":1},{"type":"boolean"}]}},"required":["v"]}`,
+		`{"type":"object","allOf":[{"properties":{"a":{"type":"string"}},"required":["a"]},{"properties":{"b":{"type":"integer"}},"required":["b"]}]}`,
+		`{"type":"object","properties":{"node":{"$ref":"#/$defs/node"}},"required":["node"],"$defs":{"node":{"type":"object","properties":{"next":{"$ref":"#/$defs/node"}}}}}`,
+		`{"type":"object","properties":{"a":{"type":"string"},"b":{"type":"string"}},"minProperties":1,"maxProperties":1}`,
+		`{"type":"object","properties":{"d":{"type":"string","default":"x"}},"required":["d"]}`,
+		`{"type":"object","properties":{"any":{}},"required":["any"]}`,
+	}
+	for _, input := range cases {
+		schema, err := ParseSchema([]byte(input))
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		v, err := schema.Sample()
+		if err != nil {
+			t.Errorf("%s: %v", input, err)
+			continue
+		}
+		if errs := schema.Validate(v); len(errs) != 0 {
+			t.Errorf("%s: sample %s does not validate: %v", input, compactJSON(v), errs)
+		}
+	}
+
+	impossible, err := ParseSchema([]byte(`{"type":"string","minLength":3,"maxLength":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := impossible.Sample(); err == nil {
+		t.Error("expected error for unsatisfiable schema")
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// LatestProtocolVersion is the newest MCP protocol revision understood by the client.
+const LatestProtocolVersion = "2025-06-18"
+
+// SupportedProtocolVersions lists every protocol revision the client can speak,
+// newest first.
+var SupportedProtocolVersions = []string{
+	LatestProtocolVersion,
+	"2025-03-26",
+	"2024-11-05",
+}
+
+// IsSupportedProtocolVersion reports whether the given protocol revision is
+// understood by the client.
+func IsSupportedProtocolVersion(version string) bool {
+	for _, v := range SupportedProtocolVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// Implementation describes the name and version of an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ClientCapabilities are the features a client advertises during initialization.
+type ClientCapabilities struct {
+	Roots        *RootsCapability       `json:"roots,omitempty"`
+	Sampling     map[string]interface{} `json:"sampling,omitempty"`
+	Elicitation  map[string]interface{} `json:"elicitation,omitempty"`
+	Experimental map[string]interface{} `json:"experimental,omitempty"`
+}
+
+// RootsCapability describes client support for filesystem roots.
+type RootsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// ServerCapabilities are the features a server advertises in its initialize result.
+type ServerCapabilities struct {
+	Logging      map[string]interface{}     `json:"logging,omitempty"`
+	Completions  map[string]interface{}     `json:"completions,omitempty"`
+	Prompts      *ServerPromptsCapability   `json:"prompts,omitempty"`
+	Resources    *ServerResourcesCapability `json:"resources,omitempty"`
+	Tools        *ServerToolsCapability     `json:"tools,omitempty"`
+	Experimental map[string]interface{}     `json:"experimental,omitempty"`
+}
+
+// ServerPromptsCapability describes server support for prompts.
+type ServerPromptsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// ServerResourcesCapability describes server support for resources.
+type ServerResourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// ServerToolsCapability describes server support for tools.
+type ServerToolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// InitializeParams is the payload of the initialize request.
+type InitializeParams struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ClientCapabilities `json:"capabilities"`
+	ClientInfo      Implementation     `json:"clientInfo"`
+}
+
+// InitializeResult is the server's answer to the initialize request.
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+// ProtocolVersionError reports a protocol revision returned by the server that
+// the client cannot speak.
+type ProtocolVersionError struct {
+	Requested string
+	Received  string
+}
+
+func (e *ProtocolVersionError) Error() string {
+	return fmt.Sprintf("unsupported protocol version %q (requested %q)", e.Received, e.Requested)
+}
+
+// Initialize performs the MCP handshake using the latest protocol version. See
+// InitializeWithVersion for details.
+func (c *MCPClient) Initialize(clientInfo Implementation, capabilities ClientCapabilities, id interface{}) (*InitializeResult, error) {
+	return c.InitializeWithVersion(LatestProtocolVersion, clientInfo, capabilities, id)
+}
+
+// InitializeWithVersion sends the initialize request for the given protocol
+// version, decodes the server's reply and, when the negotiated version is
+// supported, sends the notifications/initialized notification. When the server
+// answers with an unsupported version the result is returned together with a
+// *ProtocolVersionError and the handshake is not completed.
+func (c *MCPClient) InitializeWithVersion(version string, clientInfo Implementation, capabilities ClientCapabilities, id interface{}) (*InitializeResult, error) {
+	return c.InitializeContext(context.Background(), version, clientInfo, capabilities, id)
+}
+
+// InitializeContext is like InitializeWithVersion but gives up when ctx is
+// done. As required by the specification, an abandoned initialize request is
+// not cancelled on the server.
+func (c *MCPClient) InitializeContext(ctx context.Context, version string, clientInfo Implementation, capabilities ClientCapabilities, id interface{}) (*InitializeResult, error) {
+	params, err := toParams(InitializeParams{
+		ProtocolVersion: version,
+		Capabilities:    capabilities,
+		ClientInfo:      clientInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.call(ctx, "initialize", params, id, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Error != nil {
+		return nil, fmt.Errorf("initialize failed: %s (code %d)", resp.Error.Message, resp.Error.Code)
+	}
+	var result InitializeResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, fmt.Errorf("invalid initialize result: %w", err)
+	}
+	if result.ProtocolVersion == "" {
+		return &result, fmt.Errorf("invalid initialize result: protocolVersion is missing")
+	}
+	if !IsSupportedProtocolVersion(result.ProtocolVersion) {
+		return &result, &ProtocolVersionError{Requested: version, Received: result.ProtocolVersion}
+	}
+	if err := c.SendNotification("notifications/initialized", nil); err != nil {
+		return &result, err
+	}
+	return &result, nil
+}
+
+// SendNotification sends a request without an ID, for which no response is expected.
+func (c *MCPClient) SendNotification(method string, params map[string]interface{}) error {
+	return c.SendRequest(NewNotification(method, params))
+}
+
+// toParams converts a typed params struct into the generic map used by Request.
+func toParams(v interface{}) (map[string]interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal params: %w", err)
+	}
+	var params map[string]interface{}
+	if err := json.Unmarshal(data, &params); err != nil {
+		return nil, fmt.Errorf("failed to convert params: %w", err)
+	}
+	return params, nil
+}
+
+// decodeResult converts the generic result of a response into the given type.
+func decodeResult(resp *Response, v interface{}) error {
+	if resp.Result == nil {
+		return fmt.Errorf("result is missing")
+	}
+	data, err := json.Marshal(resp.Result)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return FormatJSONError(data, err, "failed to decode result")
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestInitializeSuccess(t *testing.T) {
+	in := bytes.NewBufferString(`{"id":1,"result":{"protocolVersion":"` + LatestProtocolVersion + `","capabilities":{"tools":{"listChanged":true},"resources":{"subscribe":true}},"serverInfo":{"name":"srv","version":"1.2.3"}}}` + "\n")
+	out := &bytes.Buffer{}
+	c := NewMCPClientWithIO(in, out, io.Discard)
+
+	result, err := c.Initialize(Implementation{Name: "mcpcli", Version: CLIVersion}, ClientCapabilities{}, 1)
+	if err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if result.ServerInfo.Name != "srv" || result.ServerInfo.Version != "1.2.3" {
+		t.Errorf("unexpected server info %+v", result.ServerInfo)
+	}
+	if result.Capabilities.Tools == nil || !result.Capabilities.Tools.ListChanged {
+		t.Errorf("tools capability not parsed: %+v", result.Capabilities)
+	}
+	if result.Capabilities.Resources == nil || !result.Capabilities.Resources.Subscribe {
+		t.Errorf("resources capability not parsed: %+v", result.Capabilities)
+	}
+	if result.Capabilities.Prompts != nil {
+		t.Errorf("prompts capability should be absent")
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected initialize request and initialized notification, got %q", out.String())
+	}
+	if !strings.Contains(lines[0], `"method":"initialize"`) || !strings.Contains(lines[0], `"clientInfo":{"name":"mcpcli"`) {
+		t.Errorf("unexpected initialize request %s", lines[0])
+	}
+	if !strings.Contains(lines[1], "notifications/initialized") {
+		t.Errorf("expected initialized notification, got %s", lines[1])
+	}
+}
+
+func TestInitializeUnsupportedVersion(t *testing.T) {
+	in := bytes.NewBufferString(`{"id":1,"result":{"protocolVersion":"1999-01-01","capabilities":{},"serverInfo":{"name":"old","version":"0"}}}` + "\n")
+	out := &bytes.Buffer{}
+	c := NewMCPClientWithIO(in, out, io.Discard)
+
+	result, err := c.Initialize(Implementation{Name: "mcpcli"}, ClientCapabilities{}, 1)
+	var versionErr *ProtocolVersionError
+	if !errors.As(err, &versionErr) {
+		t.Fatalf("expected protocol version error, got %v", err)
+	}
+	if versionErr.Received != "1999-01-01" || versionErr.Requested != LatestProtocolVersion {
+		t.Errorf("unexpected version error %+v", versionErr)
+	}
+	if result == nil || result.ServerInfo.Name != "old" {
+		t.Errorf("expected result to be returned, got %+v", result)
+	}
+	if strings.Contains(out.String(), "notifications/initialized") {
+		t.Error("initialized notification must not be sent for unsupported versions")
+	}
+}
+
+func TestInitializeErrors(t *testing.T) {
+	cases := map[string]string{
+		"mcp error":       `{"id":1,"error":{"code":-32601,"message":"Method not found"}}`,
+		"missing result":  `{"id":1}`,
+		"missing version": `{"id":1,"result":{"capabilities":{}}}`,
+		"bad result":      `{"id":1,"result":{"protocolVersion":5}}`,
+	}
+	for name, line := range cases {
+		c := NewMCPClientWithIO(bytes.NewBufferString(line+"\n"), io.Discard, io.Discard)
+		if _, err := c.Initialize(Implementation{Name: "mcpcli"}, ClientCapabilities{}, 1); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestIsSupportedProtocolVersion(t *testing.T) {
+	for _, v := range SupportedProtocolVersions {
+		if !IsSupportedProtocolVersion(v) {
+			t.Errorf("expected %s to be supported", v)
+		}
+	}
+	if IsSupportedProtocolVersion("2000-01-01") {
+		t.Error("unexpected support for unknown version")
+	}
+}
@@ -1,18 +1,25 @@
 package core
 
+// Request is a JSON-RPC 2.0 request. A Request without an ID is a
+// notification and must not be answered.
 type Request struct {
-	Method string                 `json:"method"`
-	Params map[string]interface{} `json:"params,omitempty"`
-	ID     interface{}            `json:"id,omitempty"`
+	JSONRPC string                 `json:"jsonrpc"`
+	Method  string                 `json:"method"`
+	Params  map[string]interface{} `json:"params,omitempty"`
+	ID      interface{}            `json:"id,omitempty"`
 }
 
+// Response is a JSON-RPC 2.0 response carrying either a result or an error.
 type Response struct {
-	Result interface{} `json:"result,omitempty"`
-	Error  *Error      `json:"error,omitempty"`
-	ID     interface{} `json:"id,omitempty"`
+	JSONRPC string      `json:"jsonrpc"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   *Error      `json:"error,omitempty"`
+	ID      interface{} `json:"id,omitempty"`
 }
 
+// Error is a JSON-RPC 2.0 error object.
 type Error struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
 }
@@ -1,79 +1,483 @@
 package core
 
 import (
-	"bufio"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
+// NotificationHandler receives notifications sent by the server.
+type NotificationHandler func(notification *Request)
+
+// ExchangeHook observes a request sent by Call together with the response it
+// got, which is nil when none arrived.
+type ExchangeHook func(request *Request, response *Response)
+
+// MCPClient talks to an MCP server over a ClientTransport.
+//
+// Call may be used from many goroutines at once: a background reader started
+// on the first call demultiplexes responses by ID, routes server
+// notifications to handlers registered with OnNotification and answers
+// server-initiated pings.
 type MCPClient struct {
-	stdin  io.Reader
-	stdout io.Writer
-	stderr io.Writer
+	transport ClientTransport
+	stderr    io.Writer
+	nextID    int64
+
+	startOnce   sync.Once
+	dispatching atomic.Bool
+	done        chan struct{}
+	readErr     error
+
+	// queued holds the notifications waiting for delivery, without bound so
+	// that the reader never waits on handlers; queueReady tells the
+	// delivery goroutine that it is not empty.
+	queueMu    sync.Mutex
+	queued     []*Request
+	queueReady chan struct{}
+
+	mu          sync.Mutex
+	pending     map[string]chan *Response
+	handlers    map[int]notificationSubscription
+	hooks       map[int]ExchangeHook
+	nextHandler int
+}
+
+type notificationSubscription struct {
+	method  string
+	handler NotificationHandler
 }
 
 func NewMCPClient() *MCPClient {
-	return &MCPClient{
-		stdin:  os.Stdin,
-		stdout: os.Stdout,
-		stderr: os.Stderr,
-	}
+	return NewMCPClientWithIO(os.Stdin, os.Stdout, os.Stderr)
 }
 
+// NewMCPClientWithIO creates a client exchanging newline-delimited messages
+// over stdin and stdout.
 func NewMCPClientWithIO(stdin io.Reader, stdout io.Writer, stderr io.Writer) *MCPClient {
+	return NewMCPClientWithTransport(NewStreamTransport(stdin, stdout), stderr)
+}
+
+// NewMCPClientWithTransport creates a client on top of an arbitrary transport.
+func NewMCPClientWithTransport(transport ClientTransport, stderr io.Writer) *MCPClient {
 	return &MCPClient{
-		stdin:  stdin,
-		stdout: stdout,
-		stderr: stderr,
+		transport:  transport,
+		stderr:     stderr,
+		done:       make(chan struct{}),
+		queueReady: make(chan struct{}, 1),
+		pending:    map[string]chan *Response{},
+		handlers:   map[int]notificationSubscription{},
+		hooks:      map[int]ExchangeHook{},
 	}
 }
 
 func (c *MCPClient) SendRequest(request *Request) error {
+	return c.sendRequest(context.Background(), request)
+}
+
+func (c *MCPClient) sendRequest(ctx context.Context, request *Request) error {
 	requestJSON, err := json.Marshal(request)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
 	}
-	_, err = fmt.Fprintln(c.stdout, string(requestJSON))
-	if err != nil {
+	if err := c.transport.Send(ctx, requestJSON); err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
 	return nil
 }
 
+// Transport returns the transport the client talks over.
+func (c *MCPClient) Transport() ClientTransport {
+	return c.transport
+}
+
+// Close closes the underlying transport, which also stops the background
+// reader and fails pending calls.
+func (c *MCPClient) Close() error {
+	return c.transport.Close()
+}
+
+// ReadResponse reads the next message from the server directly. It is meant
+// for simple request/response exchanges and cannot be used once Call has
+// started the background reader.
 func (c *MCPClient) ReadResponse() (*Response, error) {
-	scanner := bufio.NewScanner(c.stdin)
-	if !scanner.Scan() {
-		if err := scanner.Err(); err != nil {
-			return nil, fmt.Errorf("failed to read response: %w", err)
-		}
-		return nil, fmt.Errorf("no response received")
+	if c.dispatching.Load() {
+		return nil, fmt.Errorf("failed to read response: responses are being dispatched to Call")
+	}
+	line, err := c.readMessage()
+	if err != nil {
+		return nil, err
 	}
 	var response Response
-	text := scanner.Text()
-	if err := json.Unmarshal([]byte(text), &response); err != nil {
-		return nil, FormatJSONError([]byte(text), err, "failed to unmarshal response")
+	if err := json.Unmarshal(line, &response); err != nil {
+		return nil, FormatJSONError(line, err, "failed to unmarshal response")
 	}
 	return &response, nil
 }
 
+// readMessage returns the next message from the server.
+func (c *MCPClient) readMessage() ([]byte, error) {
+	msg, err := c.transport.Receive()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("no response received")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	return msg, nil
+}
+
+// NextID returns a fresh numeric request ID.
+func (c *MCPClient) NextID() int64 {
+	return atomic.AddInt64(&c.nextID, 1)
+}
+
+// Call sends a request and waits for the response carrying the same ID. When
+// id is nil a fresh ID is assigned.
 func (c *MCPClient) Call(method string, params map[string]interface{}, id interface{}) (*Response, error) {
-	req := &Request{
-		Method: method,
-		Params: params,
-		ID:     id,
+	return c.CallContext(context.Background(), method, params, id)
+}
+
+// CallContext is like Call but gives up when ctx is done. The server is then
+// told to stop working on the request with a notifications/cancelled message.
+func (c *MCPClient) CallContext(ctx context.Context, method string, params map[string]interface{}, id interface{}) (*Response, error) {
+	return c.call(ctx, method, params, id, true)
+}
+
+func (c *MCPClient) call(ctx context.Context, method string, params map[string]interface{}, id interface{}, notifyCancel bool) (*Response, error) {
+	if id == nil {
+		id = c.NextID()
+	}
+	key, ok := idKey(id)
+	if !ok {
+		return nil, fmt.Errorf("invalid request id %v: must be a string or number", id)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("%s request not sent: %w", method, err)
+	}
+	req := &Request{JSONRPC: JSONRPCVersion, Method: method, Params: params, ID: id}
+	resp, err := c.await(ctx, key, func() error { return c.sendRequest(ctx, req) })
+	c.observe(req, resp)
+	if err != nil && err == ctx.Err() {
+		if notifyCancel {
+			c.cancelRequest(id, ctx.Err())
+		}
+		return nil, fmt.Errorf("%s request %v: %w", method, id, ctx.Err())
+	}
+	return resp, err
+}
+
+// CallRaw sends an already encoded message, which need not be valid JSON-RPC,
+// and waits for the response carrying id. When id is nil it waits for a
+// response with a null or missing ID, which is what servers send when they
+// cannot read the request ID, for instance after a parse error. The server is
+// not notified when ctx is done.
+func (c *MCPClient) CallRaw(ctx context.Context, message []byte, id interface{}) (*Response, error) {
+	key := ""
+	if id != nil {
+		var ok bool
+		if key, ok = idKey(id); !ok {
+			return nil, fmt.Errorf("invalid request id %v: must be a string or number", id)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("message not sent: %w", err)
+	}
+	resp, err := c.await(ctx, key, func() error {
+		if err := c.transport.Send(ctx, message); err != nil {
+			return fmt.Errorf("failed to send message: %w", err)
+		}
+		return nil
+	})
+	if err != nil && err == ctx.Err() {
+		return nil, fmt.Errorf("no response: %w", ctx.Err())
+	}
+	return resp, err
+}
+
+// await registers a pending response under key, runs send and waits for the
+// response, the end of the connection or ctx. When ctx is done the error is
+// ctx.Err() itself.
+func (c *MCPClient) await(ctx context.Context, key string, send func() error) (*Response, error) {
+	ch := make(chan *Response, 1)
+	c.mu.Lock()
+	if _, exists := c.pending[key]; exists {
+		c.mu.Unlock()
+		if key == "" {
+			return nil, fmt.Errorf("already waiting for a response without id")
+		}
+		return nil, fmt.Errorf("request id %s is already in use", key)
 	}
-	if err := c.SendRequest(req); err != nil {
+	c.pending[key] = ch
+	c.mu.Unlock()
+
+	c.startDispatcher()
+	if err := send(); err != nil {
+		c.removePending(key)
 		return nil, err
 	}
-	return c.ReadResponse()
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-c.done:
+		// A response may have been delivered just before the reader stopped.
+		select {
+		case resp := <-ch:
+			return resp, nil
+		default:
+		}
+		c.removePending(key)
+		return nil, c.readErr
+	case <-ctx.Done():
+		c.removePending(key)
+		return nil, ctx.Err()
+	}
+}
+
+// cancelRequest notifies the server that the client no longer waits for the
+// request with the given ID.
+func (c *MCPClient) cancelRequest(id interface{}, reason error) {
+	params := map[string]interface{}{"requestId": id, "reason": reason.Error()}
+	if err := c.SendNotification("notifications/cancelled", params); err != nil {
+		c.PrintError("failed to cancel request %v: %v", id, err)
+	}
+}
+
+// OnNotification registers a handler for server notifications with the given
+// method, or for every notification when method is "*". Handlers run one at a
+// time, in arrival order, on a dedicated goroutine and may issue calls. The
+// returned function removes the handler.
+func (c *MCPClient) OnNotification(method string, handler NotificationHandler) func() {
+	c.mu.Lock()
+	id := c.nextHandler
+	c.nextHandler++
+	c.handlers[id] = notificationSubscription{method: method, handler: handler}
+	c.mu.Unlock()
+	c.startDispatcher()
+	return func() {
+		c.mu.Lock()
+		delete(c.handlers, id)
+		c.mu.Unlock()
+	}
+}
+
+// OnExchange registers a hook called on the calling goroutine once a call
+// is over, with the request sent and the response received. The returned
+// function removes the hook.
+func (c *MCPClient) OnExchange(hook ExchangeHook) func() {
+	c.mu.Lock()
+	id := c.nextHandler
+	c.nextHandler++
+	c.hooks[id] = hook
+	c.mu.Unlock()
+	return func() {
+		c.mu.Lock()
+		delete(c.hooks, id)
+		c.mu.Unlock()
+	}
+}
+
+func (c *MCPClient) observe(req *Request, resp *Response) {
+	c.mu.Lock()
+	hooks := make([]ExchangeHook, 0, len(c.hooks))
+	for _, hook := range c.hooks {
+		hooks = append(hooks, hook)
+	}
+	c.mu.Unlock()
+	for _, hook := range hooks {
+		hook(req, resp)
+	}
+}
+
+func (c *MCPClient) removePending(key string) {
+	c.mu.Lock()
+	delete(c.pending, key)
+	c.mu.Unlock()
+}
+
+// startDispatcher launches the background reader and notification delivery
+// goroutines exactly once.
+func (c *MCPClient) startDispatcher() {
+	c.startOnce.Do(func() {
+		c.dispatching.Store(true)
+		go c.readLoop()
+		go c.deliverNotifications()
+	})
+}
+
+// readLoop reads messages until the stream ends and routes each of them.
+func (c *MCPClient) readLoop() {
+	for {
+		line, err := c.readMessage()
+		if err != nil {
+			c.readErr = err
+			close(c.done)
+			return
+		}
+		c.dispatch(line)
+	}
+}
+
+// dispatch routes a single frame, which may be a JSON-RPC batch.
+func (c *MCPClient) dispatch(frame []byte) {
+	if len(frame) == 0 {
+		c.PrintError("ignored an empty message")
+		return
+	}
+	if frame[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(frame, &batch); err != nil {
+			c.PrintError("%v", FormatJSONError(frame, err, "failed to unmarshal message"))
+			return
+		}
+		for _, msg := range batch {
+			c.dispatch(msg)
+		}
+		return
+	}
+	var probe struct {
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(frame, &probe); err != nil {
+		c.PrintError("%v", FormatJSONError(frame, err, "failed to unmarshal message"))
+		return
+	}
+	if probe.Method != "" {
+		var req Request
+		if err := json.Unmarshal(frame, &req); err != nil {
+			c.PrintError("%v", FormatJSONError(frame, err, "failed to unmarshal request"))
+			return
+		}
+		c.handleServerMessage(&req)
+		return
+	}
+	var resp Response
+	if err := json.Unmarshal(frame, &resp); err != nil {
+		c.PrintError("%v", FormatJSONError(frame, err, "failed to unmarshal response"))
+		return
+	}
+	key := IDKey(resp.ID)
+	c.mu.Lock()
+	ch, ok := c.pending[key]
+	delete(c.pending, key)
+	c.mu.Unlock()
+	if !ok {
+		c.PrintError("received response for unknown request id %v", resp.ID)
+		return
+	}
+	ch <- &resp
+}
+
+// handleServerMessage queues notifications for delivery and answers requests
+// initiated by the server. Neither blocks the reader: the answer is sent from
+// its own goroutine, as a transport may only complete a send once the reader
+// has moved on.
+func (c *MCPClient) handleServerMessage(req *Request) {
+	if req.IsNotification() {
+		c.queueMu.Lock()
+		c.queued = append(c.queued, req)
+		c.queueMu.Unlock()
+		select {
+		case c.queueReady <- struct{}{}:
+		default:
+		}
+		return
+	}
+	go c.answerServerRequest(req)
+}
+
+// answerServerRequest replies to a request initiated by the server.
+func (c *MCPClient) answerServerRequest(req *Request) {
+	resp := &Response{JSONRPC: JSONRPCVersion, ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = map[string]interface{}{}
+	} else {
+		resp.Error = &Error{Code: MethodNotFound, Message: fmt.Sprintf("Method not found: %s", req.Method)}
+	}
+	if err := c.sendResponse(resp); err != nil {
+		c.PrintError("failed to answer %s request: %v", req.Method, err)
+	}
+}
+
+func (c *MCPClient) sendResponse(resp *Response) error {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal response: %w", err)
+	}
+	return c.transport.Send(context.Background(), data)
+}
+
+// nextNotification waits for the next queued notification. It returns false
+// once the reader has stopped and every notification was delivered.
+func (c *MCPClient) nextNotification() (*Request, bool) {
+	for {
+		c.queueMu.Lock()
+		if len(c.queued) > 0 {
+			n := c.queued[0]
+			c.queued = c.queued[1:]
+			c.queueMu.Unlock()
+			return n, true
+		}
+		c.queueMu.Unlock()
+		select {
+		case <-c.queueReady:
+		case <-c.done:
+			c.queueMu.Lock()
+			empty := len(c.queued) == 0
+			c.queueMu.Unlock()
+			if empty {
+				return nil, false
+			}
+		}
+	}
+}
+
+// deliverNotifications invokes the registered handlers for each notification.
+func (c *MCPClient) deliverNotifications() {
+	for {
+		n, ok := c.nextNotification()
+		if !ok {
+			return
+		}
+		c.mu.Lock()
+		var handlers []NotificationHandler
+		for i := 0; i < c.nextHandler; i++ {
+			sub, ok := c.handlers[i]
+			if ok && (sub.method == "*" || sub.method == n.Method) {
+				handlers = append(handlers, sub.handler)
+			}
+		}
+		c.mu.Unlock()
+		for _, h := range handlers {
+			h(n)
+		}
+	}
 }
 
 func (c *MCPClient) ListResources(id interface{}) (*Response, error) {
-	return c.Call("resources/list", nil, id)
+	return c.ListResourcesContext(context.Background(), id)
+}
+
+// ListResourcesContext calls resources/list and honors the context deadline.
+func (c *MCPClient) ListResourcesContext(ctx context.Context, id interface{}) (*Response, error) {
+	return c.CallContext(ctx, "resources/list", nil, id)
+}
+
+func (c *MCPClient) ListResourceTemplates(id interface{}) (*Response, error) {
+	return c.ListResourceTemplatesContext(context.Background(), id)
+}
+
+// ListResourceTemplatesContext calls resources/templates/list and honors the
+// context deadline.
+func (c *MCPClient) ListResourceTemplatesContext(ctx context.Context, id interface{}) (*Response, error) {
+	return c.CallContext(ctx, "resources/templates/list", nil, id)
 }
 
 func sanitizeURI(uri string) (string, error) {
@@ -88,21 +492,58 @@ func sanitizeURI(uri string) (string, error) {
 }
 
 func (c *MCPClient) ReadResource(uri string, id interface{}) (*Response, error) {
+	return c.ReadResourceContext(context.Background(), uri, id)
+}
+
+// ReadResourceContext calls resources/read and honors the context deadline.
+func (c *MCPClient) ReadResourceContext(ctx context.Context, uri string, id interface{}) (*Response, error) {
 	sanitized, err := sanitizeURI(uri)
 	if err != nil {
 		return nil, err
 	}
 	params := map[string]interface{}{"uri": sanitized}
-	return c.Call("resources/read", params, id)
+	return c.CallContext(ctx, "resources/read", params, id)
 }
 
 func (c *MCPClient) ListTools(id interface{}) (*Response, error) {
-	return c.Call("tools/list", nil, id)
+	return c.ListToolsContext(context.Background(), id)
+}
+
+// ListToolsContext calls tools/list and honors the context deadline.
+func (c *MCPClient) ListToolsContext(ctx context.Context, id interface{}) (*Response, error) {
+	return c.CallContext(ctx, "tools/list", nil, id)
+}
+
+func (c *MCPClient) ListPrompts(id interface{}) (*Response, error) {
+	return c.ListPromptsContext(context.Background(), id)
+}
+
+// ListPromptsContext calls prompts/list and honors the context deadline.
+func (c *MCPClient) ListPromptsContext(ctx context.Context, id interface{}) (*Response, error) {
+	return c.CallContext(ctx, "prompts/list", nil, id)
+}
+
+func (c *MCPClient) GetPrompt(name string, arguments map[string]string, id interface{}) (*Response, error) {
+	return c.GetPromptContext(context.Background(), name, arguments, id)
+}
+
+// GetPromptContext calls prompts/get and honors the context deadline.
+func (c *MCPClient) GetPromptContext(ctx context.Context, name string, arguments map[string]string, id interface{}) (*Response, error) {
+	params := map[string]interface{}{"name": name}
+	if len(arguments) > 0 {
+		params["arguments"] = arguments
+	}
+	return c.CallContext(ctx, "prompts/get", params, id)
 }
 
 func (c *MCPClient) CallTool(name string, arguments map[string]interface{}, id interface{}) (*Response, error) {
+	return c.CallToolContext(context.Background(), name, arguments, id)
+}
+
+// CallToolContext calls tools/call and honors the context deadline.
+func (c *MCPClient) CallToolContext(ctx context.Context, name string, arguments map[string]interface{}, id interface{}) (*Response, error) {
 	params := map[string]interface{}{"name": name, "arguments": arguments}
-	return c.Call("tools/call", params, id)
+	return c.CallContext(ctx, "tools/call", params, id)
 }
 
 func (c *MCPClient) PrintError(format string, args ...interface{}) {
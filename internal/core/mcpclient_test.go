@@ -1,12 +1,17 @@
 package core
 
 import (
+	"bufio"
 	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestSanitizeURI(t *testing.T) {
@@ -41,13 +46,54 @@ func TestMCPClientSendAndRead(t *testing.T) {
 	}
 }
 
-func TestMCPClientCallHelpers(t *testing.T) {
-	in := &bytes.Buffer{}
-	out := &bytes.Buffer{}
+// startFakeServer connects a client to an in-process server that answers each
+// request with the response returned by handle. A nil response sends nothing.
+func startFakeServer(t *testing.T, handle func(req *Request, send func(interface{})) *Response) (*MCPClient, *bytes.Buffer) {
+	t.Helper()
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
 	errBuf := &bytes.Buffer{}
-	c := NewMCPClientWithIO(in, out, errBuf)
+	c := NewMCPClientWithIO(clientIn, clientOut, errBuf)
+	var writeMu sync.Mutex
+	send := func(msg interface{}) {
+		data, _ := json.Marshal(msg)
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		fmt.Fprintln(serverOut, string(data))
+	}
+	go func() {
+		scanner := bufio.NewScanner(serverIn)
+		for scanner.Scan() {
+			var req Request
+			if err := json.Unmarshal(scanner.Bytes(), &req); err != nil || req.Method == "" {
+				// Ignore malformed lines and replies to server-initiated requests.
+				continue
+			}
+			if resp := handle(&req, send); resp != nil {
+				send(resp)
+			}
+		}
+	}()
+	t.Cleanup(func() {
+		serverOut.Close()
+		clientOut.Close()
+	})
+	return c, errBuf
+}
+
+func TestMCPClientCallHelpers(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	c, errBuf := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		mu.Lock()
+		methods = append(methods, req.Method)
+		mu.Unlock()
+		if req.Method == "test/method" {
+			return &Response{Result: "ok", ID: req.ID}
+		}
+		return &Response{ID: req.ID}
+	})
 
-	in.WriteString(`{"result":"ok"}` + "\n")
 	resp, err := c.Call("test/method", nil, 1)
 	if err != nil {
 		t.Fatalf("call failed: %v", err)
@@ -55,48 +101,32 @@ func TestMCPClientCallHelpers(t *testing.T) {
 	if resp.Result != "ok" {
 		t.Errorf("unexpected result %v", resp.Result)
 	}
-	if !bytes.Contains(out.Bytes(), []byte("test/method")) {
-		t.Errorf("request not sent correctly: %s", out.String())
-	}
-
-	out.Reset()
-	in.Reset()
-	in.WriteString(`{"result":null}` + "\n")
 	if _, err := c.ListResources(2); err != nil {
 		t.Fatalf("list resources failed: %v", err)
 	}
-	if !bytes.Contains(out.Bytes(), []byte("resources/list")) {
-		t.Errorf("list resources wrong request: %s", out.String())
-	}
-
-	out.Reset()
-	in.Reset()
-	in.WriteString(`{"result":null}` + "\n")
 	if _, err := c.ListTools(3); err != nil {
 		t.Fatalf("list tools failed: %v", err)
 	}
-	if !bytes.Contains(out.Bytes(), []byte("tools/list")) {
-		t.Errorf("list tools wrong request: %s", out.String())
-	}
-
-	out.Reset()
-	in.Reset()
-	in.WriteString(`{"result":null}` + "\n")
 	if _, err := c.ReadResource("http://x", 4); err != nil {
 		t.Fatalf("read resource failed: %v", err)
 	}
-	if !bytes.Contains(out.Bytes(), []byte("resources/read")) {
-		t.Errorf("read resource wrong request: %s", out.String())
-	}
-
-	out.Reset()
-	in.Reset()
-	in.WriteString(`{"result":null}` + "\n")
 	if _, err := c.CallTool("tool", nil, 5); err != nil {
 		t.Fatalf("call tool failed: %v", err)
 	}
-	if !bytes.Contains(out.Bytes(), []byte("tools/call")) {
-		t.Errorf("call tool wrong request: %s", out.String())
+	if _, err := c.ListPrompts(6); err != nil {
+		t.Fatalf("list prompts failed: %v", err)
+	}
+	if _, err := c.GetPrompt("prompt", map[string]string{"a": "b"}, 7); err != nil {
+		t.Fatalf("get prompt failed: %v", err)
+	}
+	if _, err := c.ListResourceTemplates(8); err != nil {
+		t.Fatalf("list resource templates failed: %v", err)
+	}
+	want := []string{"test/method", "resources/list", "tools/list", "resources/read", "tools/call", "prompts/list", "prompts/get", "resources/templates/list"}
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(methods, ",") != strings.Join(want, ",") {
+		t.Errorf("unexpected methods %v", methods)
 	}
 
 	c.PrintError("err %d", 1)
@@ -105,6 +135,186 @@ func TestMCPClientCallHelpers(t *testing.T) {
 		t.Errorf("stderr not written correctly: %s", errBuf.String())
 	}
 }
+
+func TestMCPClientConcurrentCalls(t *testing.T) {
+	var mu sync.Mutex
+	var held []*Request
+	c, _ := startFakeServer(t, func(req *Request, send func(interface{})) *Response {
+		// Hold requests back and answer them in reverse order once all arrived.
+		mu.Lock()
+		defer mu.Unlock()
+		held = append(held, req)
+		if len(held) < 10 {
+			return nil
+		}
+		for i := len(held) - 1; i >= 0; i-- {
+			send(&Response{Result: held[i].Params["n"], ID: held[i].ID})
+		}
+		return nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(n int) {
+			defer wg.Done()
+			resp, err := c.Call("echo", map[string]interface{}{"n": n}, nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got, ok := resp.Result.(float64); !ok || int(got) != n {
+				errs <- fmt.Errorf("call %d got result %v", n, resp.Result)
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestMCPClientNotifications(t *testing.T) {
+	c, _ := startFakeServer(t, func(req *Request, send func(interface{})) *Response {
+		send(NewNotification("notifications/message", map[string]interface{}{"data": "hello"}))
+		return &Response{Result: "done", ID: req.ID}
+	})
+	got := make(chan *Request, 1)
+	all := make(chan string, 4)
+	c.OnNotification("notifications/message", func(n *Request) { got <- n })
+	unsubscribe := c.OnNotification("*", func(n *Request) { all <- n.Method })
+
+	if _, err := c.Call("work", nil, 1); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	select {
+	case n := <-got:
+		if n.Params["data"] != "hello" {
+			t.Errorf("unexpected notification %+v", n)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("notification not delivered")
+	}
+	if m := <-all; m != "notifications/message" {
+		t.Errorf("wildcard handler got %s", m)
+	}
+	unsubscribe()
+}
+
+func TestMCPClientNotificationHandlerCalls(t *testing.T) {
+	const count = 200
+	c, _ := startFakeServer(t, func(req *Request, send func(interface{})) *Response {
+		if req.Method == "work" {
+			for i := 0; i < count; i++ {
+				send(NewNotification("notifications/progress", map[string]interface{}{"progress": i}))
+			}
+		}
+		return &Response{Result: req.Method, ID: req.ID}
+	})
+	delivered := make(chan struct{}, count)
+	var once sync.Once
+	c.OnNotification("notifications/progress", func(n *Request) {
+		// A handler issuing a call while notifications keep arriving must
+		// not stall the reader.
+		once.Do(func() {
+			if resp, err := c.Call("inner", nil, "inner"); err != nil || resp.Result != "inner" {
+				t.Errorf("call from handler: %v %v", resp, err)
+			}
+		})
+		delivered <- struct{}{}
+	})
+
+	go c.Call("work", nil, "work")
+	for i := 0; i < count; i++ {
+		select {
+		case <-delivered:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of %d notifications delivered", i, count)
+		}
+	}
+}
+
+func TestMCPClientAnswersServerPing(t *testing.T) {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	defer serverOut.Close()
+	c := NewMCPClientWithIO(clientIn, clientOut, io.Discard)
+	c.OnNotification("*", func(*Request) {})
+
+	go fmt.Fprintln(serverOut, `{"jsonrpc":"2.0","method":"ping","id":"s1"}`)
+	line, err := bufio.NewReader(serverIn).ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(line, `"id":"s1"`) || !strings.Contains(line, `"result":{}`) {
+		t.Errorf("unexpected ping reply %s", line)
+	}
+}
+
+func TestMCPClientIgnoresEmptyMessage(t *testing.T) {
+	var stderr bytes.Buffer
+	c := NewMCPClientWithIO(&bytes.Buffer{}, io.Discard, &stderr)
+	c.dispatch(nil)
+	c.dispatch([]byte("[]"))
+	if !strings.Contains(stderr.String(), "empty message") {
+		t.Errorf("expected the empty message to be reported, got %q", stderr.String())
+	}
+}
+
+func TestMCPClientOnExchange(t *testing.T) {
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: req.Method}
+	})
+	var requests []*Request
+	var responses []*Response
+	remove := c.OnExchange(func(req *Request, resp *Response) {
+		requests = append(requests, req)
+		responses = append(responses, resp)
+	})
+	if _, err := c.CallTool("echo", map[string]interface{}{"text": "hi"}, 1); err != nil {
+		t.Fatal(err)
+	}
+	remove()
+	if _, err := c.Call("after", nil, 2); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 1 || requests[0].Method != "tools/call" || requests[0].Params["name"] != "echo" {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+	if responses[0] == nil || responses[0].Result != "tools/call" {
+		t.Errorf("unexpected response %+v", responses[0])
+	}
+}
+
+func TestMCPClientConnectionClosed(t *testing.T) {
+	c := NewMCPClientWithIO(bytes.NewBuffer(nil), io.Discard, io.Discard)
+	if _, err := c.Call("m", nil, 1); err == nil || !strings.Contains(err.Error(), "no response") {
+		t.Fatalf("expected closed connection error, got %v", err)
+	}
+	if _, err := c.Call("m", nil, 2); err == nil {
+		t.Fatal("expected error after connection closed")
+	}
+	if _, err := c.ReadResponse(); err == nil {
+		t.Fatal("ReadResponse must fail while dispatching")
+	}
+}
+
+func TestMCPClientDuplicateID(t *testing.T) {
+	block := make(chan struct{})
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		<-block
+		return &Response{ID: req.ID}
+	})
+	go c.Call("slow", nil, 7)
+	time.Sleep(50 * time.Millisecond)
+	if _, err := c.Call("again", nil, 7); err == nil || !strings.Contains(err.Error(), "already in use") {
+		t.Errorf("expected duplicate id error, got %v", err)
+	}
+	close(block)
+}
+
 func TestSanitizeURIEmpty(t *testing.T) {
 	if _, err := sanitizeURI("   "); err == nil {
 		t.Error("expected error for empty uri")
@@ -175,3 +385,137 @@ func TestFormatJSONErrorDefault(t *testing.T) {
 		t.Fatalf("unexpected error %v", err)
 	}
 }
+
+func TestCallContextTimeoutSendsCancelled(t *testing.T) {
+	cancelled := make(chan *Request, 1)
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		if req.Method == "notifications/cancelled" {
+			cancelled <- req
+		}
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.CallToolContext(ctx, "slow", nil, 9)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	select {
+	case n := <-cancelled:
+		if IDKey(n.Params["requestId"]) != "9" || n.Params["reason"] == "" {
+			t.Errorf("unexpected cancellation %+v", n.Params)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("notifications/cancelled not sent")
+	}
+}
+
+func TestCallContextAlreadyCancelled(t *testing.T) {
+	out := &bytes.Buffer{}
+	c := NewMCPClientWithIO(bytes.NewBuffer(nil), out, io.Discard)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.ListToolsContext(ctx, 1); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected canceled error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("request must not be sent for a cancelled context: %s", out.String())
+	}
+}
+
+func TestContextHelpers(t *testing.T) {
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		return &Response{Result: req.Method, ID: req.ID}
+	})
+	ctx := context.Background()
+	checks := []struct {
+		want string
+		call func() (*Response, error)
+	}{
+		{"resources/list", func() (*Response, error) { return c.ListResourcesContext(ctx, nil) }},
+		{"resources/read", func() (*Response, error) { return c.ReadResourceContext(ctx, "file:///a", nil) }},
+		{"resources/templates/list", func() (*Response, error) { return c.ListResourceTemplatesContext(ctx, nil) }},
+		{"resources/subscribe", func() (*Response, error) { return c.SubscribeResourceContext(ctx, "file:///a", nil) }},
+		{"resources/unsubscribe", func() (*Response, error) { return c.UnsubscribeResourceContext(ctx, "file:///a", nil) }},
+		{"tools/list", func() (*Response, error) { return c.ListToolsContext(ctx, nil) }},
+		{"tools/call", func() (*Response, error) { return c.CallToolContext(ctx, "t", nil, nil) }},
+		{"prompts/list", func() (*Response, error) { return c.ListPromptsContext(ctx, nil) }},
+		{"prompts/get", func() (*Response, error) { return c.GetPromptContext(ctx, "p", nil, nil) }},
+	}
+	for _, chk := range checks {
+		resp, err := chk.call()
+		if err != nil || resp.Result != chk.want {
+			t.Errorf("%s: got %+v, %v", chk.want, resp, err)
+		}
+	}
+	if _, err := c.ReadResourceContext(ctx, " ", nil); err == nil {
+		t.Error("expected error for empty uri")
+	}
+}
+
+func TestInitializeContextDoesNotCancel(t *testing.T) {
+	var mu sync.Mutex
+	var methods []string
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		mu.Lock()
+		methods = append(methods, req.Method)
+		mu.Unlock()
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := c.InitializeContext(ctx, LatestProtocolVersion, Implementation{Name: "x"}, ClientCapabilities{}, 1); err == nil {
+		t.Fatal("expected timeout")
+	}
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	for _, m := range methods {
+		if m == "notifications/cancelled" {
+			t.Fatal("initialize must not be cancelled")
+		}
+	}
+}
+
+func TestMCPClientCallRaw(t *testing.T) {
+	seen := make(chan string, 8)
+	c, _ := startFakeServer(t, func(req *Request, _ func(interface{})) *Response {
+		seen <- req.Method
+		switch req.Method {
+		case "garbled":
+			return NewErrorResponse(nil, ParseError, "Parse error", nil)
+		case "ping":
+			return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: map[string]interface{}{}}
+		}
+		return nil
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	resp, err := c.CallRaw(ctx, []byte(`{"jsonrpc":"2.0","method":"garbled"}`), nil)
+	if err != nil || resp.Error == nil || resp.Error.Code != ParseError || resp.ID != nil {
+		t.Fatalf("expected parse error with null id, got %+v %v", resp, err)
+	}
+	resp, err = c.CallRaw(ctx, []byte(`{"jsonrpc":"2.0","method":"ping","id":"raw"}`), "raw")
+	if err != nil || resp.ID != "raw" {
+		t.Fatalf("expected response to the raw ping, got %+v %v", resp, err)
+	}
+
+	short, cancelShort := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelShort()
+	if _, err := c.CallRaw(short, []byte(`{"jsonrpc":"2.0","method":"silent","id":5}`), 5); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+	if _, err := c.Call("ping", nil, 6); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"garbled", "ping", "silent", "ping"} {
+		if got := <-seen; got != want {
+			t.Errorf("expected %s, got %s: raw calls must not send cancellations", want, got)
+		}
+	}
+
+	if _, err := c.CallRaw(ctx, []byte(`{}`), []int{1}); err == nil {
+		t.Error("expected error for invalid id")
+	}
+}
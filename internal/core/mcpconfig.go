@@ -13,6 +13,7 @@ type MCPConfig struct {
 	Capabilities Capabilities `json:"capabilities"`
 	Tools        []Tool       `json:"tools,omitempty"`
 	Resources    []Resource   `json:"resources,omitempty"`
+	Prompts      []Prompt     `json:"prompts,omitempty"`
 }
 
 // NewMCPConfig returns an MCPConfig initialized with default values
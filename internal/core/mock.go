@@ -0,0 +1,718 @@
+package core
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// MockServerName is the server name reported by a MockServer whose
+// configuration does not name the server.
+const MockServerName = "mcpcli-mock"
+
+// InjectedErrorMessage is the message of the errors injected by MockRules
+// error rates.
+const InjectedErrorMessage = "Injected error"
+
+// maxMockBody bounds the size of a request posted to a MockServer.
+const maxMockBody = 32 << 20
+
+// MockRules customise the responses of a MockServer. Latencies are Go
+// durations such as "250ms".
+type MockRules struct {
+	// Latency delays every response.
+	Latency string `json:"latency,omitempty"`
+	// ErrorRate is the probability, between 0 and 1, of answering a request
+	// other than initialize with an internal error.
+	ErrorRate float64 `json:"errorRate,omitempty"`
+	// Seed makes the injected errors reproducible. Zero picks a random seed.
+	Seed  int64      `json:"seed,omitempty"`
+	Rules []MockRule `json:"rules,omitempty"`
+
+	latency time.Duration
+}
+
+// MockRule overrides the response to the requests it matches. The first
+// matching rule applies. String values of Result and the error message are Go
+// templates executed with a MockRequest.
+type MockRule struct {
+	Method string `json:"method"`
+	// Name restricts a tools/call or prompts/get rule to one tool or prompt.
+	Name string `json:"name,omitempty"`
+	// URI restricts a resources/read, resources/subscribe or
+	// resources/unsubscribe rule to one resource.
+	URI    string      `json:"uri,omitempty"`
+	Result interface{} `json:"result,omitempty"`
+	Error  *Error      `json:"error,omitempty"`
+	// ErrorRate is the probability of answering with Error rather than
+	// Result. Zero always answers with Error when it is set.
+	ErrorRate float64 `json:"errorRate,omitempty"`
+	// Latency replaces the global latency for matching requests.
+	Latency string `json:"latency,omitempty"`
+
+	latency time.Duration
+}
+
+// MockRequest is the data available to response templates.
+type MockRequest struct {
+	Method    string
+	Params    map[string]interface{}
+	Arguments map[string]interface{}
+	// Name is the tool called or the prompt retrieved and URI the resource
+	// read or subscribed to, if any.
+	Name string
+	URI  string
+	// Count is the number of requests matched by the rule so far, including
+	// this one.
+	Count int
+}
+
+// mockTemplateFuncs are the functions available to response templates.
+var mockTemplateFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		data, err := json.Marshal(v)
+		return string(data), err
+	},
+}
+
+// LoadMockRules reads and validates a mock rules file.
+func LoadMockRules(path string) (*MockRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+	return ParseMockRules(data)
+}
+
+// ParseMockRules decodes and validates JSON mock rules. Unknown fields are
+// rejected so that typos do not silently disable a rule.
+func ParseMockRules(data []byte) (*MockRules, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var rules MockRules
+	if err := dec.Decode(&rules); err != nil {
+		return nil, FormatJSONError(data, err, "failed to parse rules file")
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	return &rules, nil
+}
+
+// Validate checks the rules and parses their latencies and templates.
+func (r *MockRules) Validate() error {
+	var err error
+	if r.latency, err = parseLatency(r.Latency); err != nil {
+		return err
+	}
+	if r.ErrorRate < 0 || r.ErrorRate > 1 {
+		return fmt.Errorf("errorRate must be between 0 and 1, got %v", r.ErrorRate)
+	}
+	for i := range r.Rules {
+		rule := &r.Rules[i]
+		label := fmt.Sprintf("rule %d", i+1)
+		if rule.Method == "" {
+			return fmt.Errorf("%s: method is required", label)
+		}
+		label = fmt.Sprintf("rule %d (%s)", i+1, rule.Method)
+		if rule.latency, err = parseLatency(rule.Latency); err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		if rule.ErrorRate < 0 || rule.ErrorRate > 1 {
+			return fmt.Errorf("%s: errorRate must be between 0 and 1, got %v", label, rule.ErrorRate)
+		}
+		if rule.ErrorRate > 0 && rule.Error == nil {
+			return fmt.Errorf("%s: errorRate requires an error", label)
+		}
+		if rule.Result != nil && rule.Error != nil && rule.ErrorRate == 0 {
+			return fmt.Errorf("%s: set errorRate to answer with either the result or the error", label)
+		}
+		if _, err := renderTemplates(rule.Result, nil); err != nil {
+			return fmt.Errorf("%s: %w", label, err)
+		}
+		if rule.Error != nil {
+			if _, err := renderTemplate(rule.Error.Message, nil); err != nil {
+				return fmt.Errorf("%s: %w", label, err)
+			}
+		}
+	}
+	return nil
+}
+
+func parseLatency(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid latency %q", s)
+	}
+	return d, nil
+}
+
+// matches reports whether the rule applies to the request.
+func (rule *MockRule) matches(req *MockRequest) bool {
+	return rule.Method == req.Method &&
+		(rule.Name == "" || rule.Name == req.Name) &&
+		(rule.URI == "" || rule.URI == req.URI)
+}
+
+// renderTemplates executes the string values nested in value as templates.
+// A nil data only parses them.
+func renderTemplates(value interface{}, data *MockRequest) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		return renderTemplate(v, data)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(v))
+		for k, item := range v {
+			rendered, err := renderTemplates(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = rendered
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, item := range v {
+			rendered, err := renderTemplates(item, data)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = rendered
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+func renderTemplate(text string, data *MockRequest) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New("response").Funcs(mockTemplateFuncs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid template %q: %w", text, err)
+	}
+	if data == nil {
+		return text, nil
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("failed to render template %q: %w", text, err)
+	}
+	return b.String(), nil
+}
+
+// recordedExchange is a request of a session recording with its response.
+type recordedExchange struct {
+	method   string
+	params   map[string]interface{}
+	response *Response
+}
+
+// MockServer is a fake MCP server answering with canned responses built from
+// an MCP configuration, a session recording, or both, customised by rules.
+type MockServer struct {
+	config   *MCPConfig
+	recorded []recordedExchange
+	rules    *MockRules
+
+	mu     sync.Mutex
+	rng    *rand.Rand
+	counts map[int]int
+}
+
+// NewMockServer creates a mock serving the tools and resources of config and
+// the responses recorded in frames. Recorded responses take precedence over
+// the ones built from the configuration. Either source may be nil, as may
+// rules.
+func NewMockServer(config *MCPConfig, frames []RecordedFrame, rules *MockRules) (*MockServer, error) {
+	if rules == nil {
+		rules = &MockRules{}
+	}
+	if err := rules.Validate(); err != nil {
+		return nil, err
+	}
+	seed := rules.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &MockServer{
+		config:   config,
+		recorded: recordedExchanges(frames),
+		rules:    rules,
+		rng:      rand.New(rand.NewSource(seed)),
+		counts:   map[int]int{},
+	}, nil
+}
+
+// recordedExchanges pairs the requests sent in a recording with the responses
+// received for them.
+func recordedExchanges(frames []RecordedFrame) []recordedExchange {
+	responses := map[string]*Response{}
+	for _, f := range frames {
+		var resp Response
+		if f.Direction != DirectionReceive || json.Unmarshal(f.Bytes(), &resp) != nil || resp.ID == nil {
+			continue
+		}
+		responses[IDKey(resp.ID)] = &resp
+	}
+	var exchanges []recordedExchange
+	for _, f := range frames {
+		var req Request
+		if f.Direction != DirectionSend || json.Unmarshal(f.Bytes(), &req) != nil || req.Method == "" || req.IsNotification() {
+			continue
+		}
+		if resp, ok := responses[IDKey(req.ID)]; ok {
+			exchanges = append(exchanges, recordedExchange{method: req.Method, params: req.Params, response: resp})
+		}
+	}
+	return exchanges
+}
+
+// Handle answers a request, after the configured latency. It returns nil for
+// notifications, and when ctx is done before the response is due.
+func (s *MockServer) Handle(ctx context.Context, req *Request) *Response {
+	if req.IsNotification() {
+		return nil
+	}
+	data := newMockRequest(req)
+	rule, index := s.matchRule(data)
+	if rule != nil {
+		s.mu.Lock()
+		s.counts[index]++
+		data.Count = s.counts[index]
+		s.mu.Unlock()
+	}
+
+	latency := s.rules.latency
+	if rule != nil && rule.Latency != "" {
+		latency = rule.latency
+	}
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+
+	if req.Method != "initialize" && s.chance(s.rules.ErrorRate) {
+		return NewErrorResponse(req.ID, InternalError, InjectedErrorMessage, nil)
+	}
+	if rule != nil {
+		if resp := s.applyRule(rule, req, data); resp != nil {
+			return resp
+		}
+	}
+	if resp := s.replay(req, data); resp != nil {
+		return resp
+	}
+	return s.builtin(req, data)
+}
+
+func newMockRequest(req *Request) *MockRequest {
+	data := &MockRequest{Method: req.Method, Params: req.Params}
+	data.Arguments, _ = req.Params["arguments"].(map[string]interface{})
+	if data.Arguments == nil {
+		data.Arguments = map[string]interface{}{}
+	}
+	switch req.Method {
+	case "tools/call", "prompts/get":
+		data.Name, _ = req.Params["name"].(string)
+	case "resources/read", "resources/subscribe", "resources/unsubscribe":
+		data.URI, _ = req.Params["uri"].(string)
+	}
+	return data
+}
+
+func (s *MockServer) matchRule(data *MockRequest) (*MockRule, int) {
+	for i := range s.rules.Rules {
+		if s.rules.Rules[i].matches(data) {
+			return &s.rules.Rules[i], i
+		}
+	}
+	return nil, -1
+}
+
+// chance returns true with the given probability.
+func (s *MockServer) chance(p float64) bool {
+	if p <= 0 {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rng.Float64() < p
+}
+
+// applyRule builds the response of a rule. It returns nil when the rule sets
+// neither a result nor an error, or when its error was not drawn and it has
+// no result.
+func (s *MockServer) applyRule(rule *MockRule, req *Request, data *MockRequest) *Response {
+	if rule.Error != nil && (rule.ErrorRate == 0 || s.chance(rule.ErrorRate)) {
+		message, err := renderTemplate(rule.Error.Message, data)
+		if err != nil {
+			return NewErrorResponse(req.ID, InternalError, err.Error(), nil)
+		}
+		return NewErrorResponse(req.ID, rule.Error.Code, message, rule.Error.Data)
+	}
+	if rule.Result == nil {
+		return nil
+	}
+	result, err := renderTemplates(rule.Result, data)
+	if err != nil {
+		return NewErrorResponse(req.ID, InternalError, err.Error(), nil)
+	}
+	return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: result}
+}
+
+// replay returns the recorded response to the request with the same
+// parameters or, failing that, to the same tool or resource and then to the
+// same method.
+func (s *MockServer) replay(req *Request, data *MockRequest) *Response {
+	var sameTarget, sameMethod *recordedExchange
+	for i := range s.recorded {
+		ex := &s.recorded[i]
+		if ex.method != req.Method {
+			continue
+		}
+		if reflect.DeepEqual(normalizeJSON(ex.params), normalizeJSON(req.Params)) {
+			return ex.reply(req.ID)
+		}
+		target := newMockRequest(&Request{Method: ex.method, Params: ex.params})
+		if sameTarget == nil && target.Name == data.Name && target.URI == data.URI {
+			sameTarget = ex
+		}
+		if sameMethod == nil {
+			sameMethod = ex
+		}
+	}
+	switch {
+	case sameTarget != nil && (data.Name != "" || data.URI != ""):
+		return sameTarget.reply(req.ID)
+	case sameMethod != nil:
+		return sameMethod.reply(req.ID)
+	}
+	return nil
+}
+
+func (ex *recordedExchange) reply(id interface{}) *Response {
+	return &Response{JSONRPC: JSONRPCVersion, ID: id, Result: ex.response.Result, Error: ex.response.Error}
+}
+
+// builtin answers from the configuration. Capabilities enabled in the
+// configuration are advertised even when it declares no tool or resource.
+func (s *MockServer) builtin(req *Request, data *MockRequest) *Response {
+	config := s.config
+	if config == nil {
+		config = &MCPConfig{}
+	}
+	result := func(v interface{}) *Response {
+		return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: v}
+	}
+	switch req.Method {
+	case "initialize":
+		version, _ := req.Params["protocolVersion"].(string)
+		if !IsSupportedProtocolVersion(version) {
+			version = LatestProtocolVersion
+		}
+		info := Implementation{Name: config.Name, Version: config.Version}
+		if info.Name == "" {
+			info.Name = MockServerName
+		}
+		if info.Version == "" {
+			info.Version = "0.0.0"
+		}
+		var caps ServerCapabilities
+		if config.Capabilities.Tools.Enabled || len(config.Tools) > 0 {
+			caps.Tools = &ServerToolsCapability{}
+		}
+		if config.Capabilities.Resources.Enabled || len(config.Resources) > 0 {
+			caps.Resources = &ServerResourcesCapability{}
+		}
+		if config.Capabilities.Prompts.Enabled || len(config.Prompts) > 0 {
+			caps.Prompts = &ServerPromptsCapability{}
+		}
+		return result(InitializeResult{ProtocolVersion: version, Capabilities: caps, ServerInfo: info})
+	case "ping":
+		return result(map[string]interface{}{})
+	case "tools/list":
+		tools := []interface{}{}
+		for _, t := range config.Tools {
+			tool := map[string]interface{}{
+				"name":        t.Name,
+				"description": t.Description,
+				"inputSchema": t.InputSchema(),
+			}
+			if len(t.OutputSchema) > 0 {
+				tool["outputSchema"] = t.OutputSchema
+			}
+			tools = append(tools, tool)
+		}
+		return result(map[string]interface{}{"tools": tools})
+	case "tools/call":
+		if data.Name == "" {
+			return NewErrorResponse(req.ID, InvalidParams, "name must be a string", nil)
+		}
+		for _, t := range config.Tools {
+			if t.Name == data.Name {
+				text := fmt.Sprintf("%s called with %s", t.Name, compactJSON(data.Arguments))
+				return result(map[string]interface{}{
+					"content": []interface{}{map[string]interface{}{"type": "text", "text": text}},
+				})
+			}
+		}
+		return NewErrorResponse(req.ID, InvalidParams, fmt.Sprintf("Unknown tool: %s", data.Name), nil)
+	case "resources/list":
+		resources := []interface{}{}
+		for _, r := range config.Resources {
+			if r.URITemplate == "" {
+				resources = append(resources, map[string]interface{}{"uri": r.Name, "name": r.Name, "description": r.Type})
+			}
+		}
+		return result(map[string]interface{}{"resources": resources})
+	case "resources/templates/list":
+		templates := []interface{}{}
+		for _, r := range config.Resources {
+			if r.URITemplate != "" {
+				templates = append(templates, map[string]interface{}{"uriTemplate": r.URITemplate, "name": r.Name, "description": r.Type})
+			}
+		}
+		return result(map[string]interface{}{"resourceTemplates": templates})
+	case "resources/subscribe", "resources/unsubscribe":
+		// Subscriptions are accepted, but the mock never changes its
+		// resources and so sends no updates.
+		if _, ok := req.Params["uri"].(string); !ok {
+			return NewErrorResponse(req.ID, InvalidParams, "uri must be a string", nil)
+		}
+		return result(map[string]interface{}{})
+	case "prompts/list":
+		if !config.Capabilities.Prompts.Enabled && len(config.Prompts) == 0 {
+			break
+		}
+		prompts := []interface{}{}
+		for _, p := range config.Prompts {
+			args := []interface{}{}
+			for _, a := range p.Arguments {
+				args = append(args, map[string]interface{}{"name": a.Name, "description": a.Description, "required": a.Required})
+			}
+			prompts = append(prompts, map[string]interface{}{"name": p.Name, "description": p.Description, "arguments": args})
+		}
+		return result(map[string]interface{}{"prompts": prompts})
+	case "prompts/get":
+		if !config.Capabilities.Prompts.Enabled && len(config.Prompts) == 0 {
+			break
+		}
+		if data.Name == "" {
+			return NewErrorResponse(req.ID, InvalidParams, "name must be a string", nil)
+		}
+		for _, p := range config.Prompts {
+			if p.Name != data.Name {
+				continue
+			}
+			for _, a := range p.Arguments {
+				if _, ok := data.Arguments[a.Name]; a.Required && !ok {
+					return NewErrorResponse(req.ID, InvalidParams, fmt.Sprintf("Missing required argument: %s", a.Name), nil)
+				}
+			}
+			text := fmt.Sprintf("%s called with %s", p.Name, compactJSON(data.Arguments))
+			return result(map[string]interface{}{
+				"description": p.Description,
+				"messages": []interface{}{map[string]interface{}{
+					"role":    "user",
+					"content": map[string]interface{}{"type": "text", "text": text},
+				}},
+			})
+		}
+		return NewErrorResponse(req.ID, InvalidParams, fmt.Sprintf("Unknown prompt: %s", data.Name), nil)
+	case "resources/read":
+		if _, ok := req.Params["uri"].(string); !ok {
+			return NewErrorResponse(req.ID, InvalidParams, "uri must be a string", nil)
+		}
+		for _, r := range config.Resources {
+			if r.URITemplate == "" && r.Name == data.URI {
+				return result(map[string]interface{}{"contents": []interface{}{
+					map[string]interface{}{"uri": r.Name, "mimeType": "text/plain", "text": "This is the content of resource: " + r.Name},
+				}})
+			}
+		}
+		for _, r := range config.Resources {
+			if r.URITemplate == "" {
+				continue
+			}
+			tmpl, err := ParseURITemplate(r.URITemplate)
+			if err != nil {
+				continue
+			}
+			if values, ok := tmpl.Match(data.URI); ok {
+				text := fmt.Sprintf("This is the content of resource %s with %s", r.Name, compactJSON(values))
+				return result(map[string]interface{}{"contents": []interface{}{
+					map[string]interface{}{"uri": data.URI, "mimeType": "text/plain", "text": text},
+				}})
+			}
+		}
+		return NewErrorResponse(req.ID, ResourceNotFound, "Resource not found", map[string]interface{}{"uri": data.URI})
+	}
+	return NewErrorResponse(req.ID, MethodNotFound, fmt.Sprintf("Method not found: %s", req.Method), nil)
+}
+
+// HandleMessage answers an encoded message, which may be a batch. It returns
+// nil when nothing is to be sent back.
+func (s *MockServer) HandleMessage(ctx context.Context, message []byte) []byte {
+	message = bytes.TrimSpace(message)
+	if len(message) > 0 && message[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(message, &batch); err != nil {
+			return encodeMock(NewErrorResponse(nil, ParseError, "Parse error", nil))
+		}
+		if len(batch) == 0 {
+			return encodeMock(NewErrorResponse(nil, InvalidRequest, "Invalid Request: empty batch", nil))
+		}
+		responses := make([]*Response, len(batch))
+		var wg sync.WaitGroup
+		for i, item := range batch {
+			wg.Add(1)
+			go func(i int, item json.RawMessage) {
+				defer wg.Done()
+				responses[i] = s.handleOne(ctx, item)
+			}(i, item)
+		}
+		wg.Wait()
+		var answered []*Response
+		for _, resp := range responses {
+			if resp != nil {
+				answered = append(answered, resp)
+			}
+		}
+		if len(answered) == 0 {
+			return nil
+		}
+		return encodeMock(answered)
+	}
+	if resp := s.handleOne(ctx, message); resp != nil {
+		return encodeMock(resp)
+	}
+	return nil
+}
+
+func (s *MockServer) handleOne(ctx context.Context, message []byte) *Response {
+	var req Request
+	if err := json.Unmarshal(message, &req); err != nil {
+		return NewErrorResponse(nil, ParseError, "Parse error", nil)
+	}
+	if req.Method == "" {
+		// Responses to server requests are never expected: ignore them.
+		return nil
+	}
+	return s.Handle(ctx, &req)
+}
+
+func encodeMock(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(NewErrorResponse(nil, InternalError, err.Error(), nil))
+	}
+	return data
+}
+
+// ServeStream answers newline-delimited messages read from r on w, as a
+// stdio server does, until r is exhausted. Requests are handled concurrently
+// so that a delayed response does not hold back the others.
+func (s *MockServer) ServeStream(r io.Reader, w io.Writer) error {
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMockBody)
+	for scanner.Scan() {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		message := append([]byte(nil), scanner.Bytes()...)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reply := s.HandleMessage(context.Background(), message); reply != nil {
+				mu.Lock()
+				defer mu.Unlock()
+				w.Write(append(reply, '\n'))
+			}
+		}()
+	}
+	wg.Wait()
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read request: %w", err)
+	}
+	return nil
+}
+
+// ServeTransport answers the messages received on t until it is closed.
+func (s *MockServer) ServeTransport(ctx context.Context, t ClientTransport) error {
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for {
+		message, err := t.Receive()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if reply := s.HandleMessage(ctx, message); reply != nil {
+				t.Send(ctx, reply)
+			}
+		}()
+	}
+}
+
+// ServeHTTP answers JSON-RPC messages posted as JSON and, when the request
+// asks for it, upgrades the connection to a WebSocket.
+func (s *MockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+		ws, err := AcceptWebSocket(w, r)
+		if err != nil {
+			return
+		}
+		defer ws.Close()
+		s.ServeTransport(context.Background(), ws)
+		return
+	}
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxMockBody))
+	if err != nil {
+		http.Error(w, "failed to read request", http.StatusBadRequest)
+		return
+	}
+	reply := s.HandleMessage(r.Context(), body)
+	if reply == nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(reply)
+}
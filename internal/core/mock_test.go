@@ -0,0 +1,335 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func mockConfig() *MCPConfig {
+	return &MCPConfig{
+		Name:      "demo",
+		Version:   "1.2.3",
+		Tools:     []Tool{{Name: "echo", Description: "Echoes"}},
+		Resources: []Resource{{Name: "docs/readme", Type: "filesystem"}},
+	}
+}
+
+func mockCall(t *testing.T, s *MockServer, method string, params map[string]interface{}) *Response {
+	t.Helper()
+	resp := s.Handle(context.Background(), &Request{JSONRPC: JSONRPCVersion, Method: method, Params: params, ID: 1})
+	if resp == nil {
+		t.Fatalf("%s: no response", method)
+	}
+	return resp
+}
+
+func TestMockServer_Builtin(t *testing.T) {
+	s, err := NewMockServer(mockConfig(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	init := mockCall(t, s, "initialize", map[string]interface{}{"protocolVersion": "2024-11-05"})
+	got := compactJSON(normalizeJSON(init.Result))
+	for _, want := range []string{`"protocolVersion":"2024-11-05"`, `"serverInfo":{"name":"demo","version":"1.2.3"}`, `"tools":{}`, `"resources":{}`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("initialize result %s missing %s", got, want)
+		}
+	}
+
+	call := mockCall(t, s, "tools/call", map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "hi"}})
+	if got := compactJSON(normalizeJSON(call.Result)); !strings.Contains(got, `echo called with {\"message\":\"hi\"}`) {
+		t.Errorf("unexpected tools/call result %s", got)
+	}
+
+	read := mockCall(t, s, "resources/read", map[string]interface{}{"uri": "docs/readme"})
+	if got := compactJSON(normalizeJSON(read.Result)); !strings.Contains(got, "This is the content of resource: docs/readme") {
+		t.Errorf("unexpected resources/read result %s", got)
+	}
+
+	if sub := mockCall(t, s, "resources/subscribe", map[string]interface{}{"uri": "docs/readme"}); sub.Error != nil {
+		t.Errorf("unexpected resources/subscribe error %+v", sub.Error)
+	}
+
+	for _, tc := range []struct {
+		method string
+		params map[string]interface{}
+		code   int
+	}{
+		{"tools/call", map[string]interface{}{"name": "missing"}, InvalidParams},
+		{"resources/read", map[string]interface{}{}, InvalidParams},
+		{"resources/read", map[string]interface{}{"uri": "missing"}, ResourceNotFound},
+		{"resources/unsubscribe", map[string]interface{}{}, InvalidParams},
+		{"prompts/list", nil, MethodNotFound},
+	} {
+		resp := mockCall(t, s, tc.method, tc.params)
+		if resp.Error == nil || resp.Error.Code != tc.code {
+			t.Errorf("%s %v: expected error %d, got %+v", tc.method, tc.params, tc.code, resp)
+		}
+	}
+
+	if resp := s.Handle(context.Background(), NewNotification("notifications/initialized", nil)); resp != nil {
+		t.Errorf("notification answered with %+v", resp)
+	}
+}
+
+func TestMockServer_Prompts(t *testing.T) {
+	config := mockConfig()
+	config.Prompts = []Prompt{{Name: "review", Description: "Reviews code", Arguments: []PromptArgument{{Name: "code", Required: true}}}}
+	s, err := NewMockServer(config, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	init := mockCall(t, s, "initialize", nil)
+	if got := compactJSON(normalizeJSON(init.Result)); !strings.Contains(got, `"prompts":{}`) {
+		t.Errorf("initialize result %s does not advertise prompts", got)
+	}
+	list := mockCall(t, s, "prompts/list", nil)
+	if got := compactJSON(normalizeJSON(list.Result)); got != `{"prompts":[{"arguments":[{"description":"","name":"code","required":true}],"description":"Reviews code","name":"review"}]}` {
+		t.Errorf("unexpected prompts/list result %s", got)
+	}
+	get := mockCall(t, s, "prompts/get", map[string]interface{}{"name": "review", "arguments": map[string]interface{}{"code": "x := 1"}})
+	if errs := CheckPromptResult(get.Result); len(errs) > 0 {
+		t.Errorf("malformed prompts/get result: %v", errs)
+	}
+	if got := compactJSON(normalizeJSON(get.Result)); !strings.Contains(got, `review called with {\"code\":\"x := 1\"}`) {
+		t.Errorf("unexpected prompts/get result %s", got)
+	}
+
+	for _, tc := range []struct {
+		params map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{}, "name must be a string"},
+		{map[string]interface{}{"name": "review"}, "Missing required argument: code"},
+		{map[string]interface{}{"name": "missing"}, "Unknown prompt: missing"},
+	} {
+		resp := mockCall(t, s, "prompts/get", tc.params)
+		if resp.Error == nil || resp.Error.Code != InvalidParams || resp.Error.Message != tc.want {
+			t.Errorf("%v: expected %q, got %+v", tc.params, tc.want, resp.Error)
+		}
+	}
+}
+
+func TestMockServer_ToolSchemas(t *testing.T) {
+	config := mockConfig()
+	config.Tools = []Tool{{
+		Name:         "search",
+		Parameters:   []ToolParameter{{Name: "query", Type: "string", Required: true}},
+		OutputSchema: json.RawMessage(`{"type":"object"}`),
+	}}
+	s, err := NewMockServer(config, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := mockCall(t, s, "tools/list", nil)
+	want := `{"tools":[{"description":"","inputSchema":{"properties":{"query":{"type":"string"}},"required":["query"],"type":"object"},"name":"search","outputSchema":{"type":"object"}}]}`
+	if got := compactJSON(normalizeJSON(list.Result)); got != want {
+		t.Errorf("unexpected tools/list result %s", got)
+	}
+}
+
+func TestMockServer_ResourceTemplates(t *testing.T) {
+	config := mockConfig()
+	config.Resources = append(config.Resources, Resource{Name: "notes", Type: "filesystem", URITemplate: "notes:///{folder}/{name}"})
+	s, err := NewMockServer(config, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	list := mockCall(t, s, "resources/templates/list", nil)
+	if got := compactJSON(normalizeJSON(list.Result)); got != `{"resourceTemplates":[{"description":"filesystem","name":"notes","uriTemplate":"notes:///{folder}/{name}"}]}` {
+		t.Errorf("unexpected resources/templates/list result %s", got)
+	}
+	if got := compactJSON(normalizeJSON(mockCall(t, s, "resources/list", nil).Result)); strings.Contains(got, "notes") {
+		t.Errorf("templates should not be listed as resources: %s", got)
+	}
+
+	read := mockCall(t, s, "resources/read", map[string]interface{}{"uri": "notes:///work/todo%20list"})
+	if errs := CheckReadResourceResult(read.Result); len(errs) > 0 {
+		t.Errorf("malformed resources/read result: %v", errs)
+	}
+	if got := compactJSON(normalizeJSON(read.Result)); !strings.Contains(got, `resource notes with {\"folder\":\"work\",\"name\":\"todo list\"}`) {
+		t.Errorf("unexpected resources/read result %s", got)
+	}
+	if resp := mockCall(t, s, "resources/read", map[string]interface{}{"uri": "notes:///work"}); resp.Error == nil || resp.Error.Code != ResourceNotFound {
+		t.Errorf("expected a resource not found error, got %+v", resp.Error)
+	}
+}
+
+func TestMockServer_Rules(t *testing.T) {
+	rules, err := ParseMockRules([]byte(`{
+		"rules": [
+			{"method": "tools/call", "name": "echo", "result": {"content": [{"type": "text", "text": "{{.Arguments.message}} #{{.Count}}"}]}},
+			{"method": "resources/read", "error": {"code": -32001, "message": "{{.URI}} is locked"}}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewMockServer(mockConfig(), nil, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, want := range []string{"hi #1", "hi #2"} {
+		resp := mockCall(t, s, "tools/call", map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "hi"}})
+		if got := compactJSON(normalizeJSON(resp.Result)); !strings.Contains(got, want) {
+			t.Errorf("call %d: expected %q in %s", i+1, want, got)
+		}
+	}
+	resp := mockCall(t, s, "resources/read", map[string]interface{}{"uri": "docs/readme"})
+	if resp.Error == nil || resp.Error.Code != -32001 || resp.Error.Message != "docs/readme is locked" {
+		t.Errorf("unexpected resources/read response %+v", resp.Error)
+	}
+}
+
+func TestMockServer_ErrorRate(t *testing.T) {
+	outcomes := func() string {
+		s, err := NewMockServer(mockConfig(), nil, &MockRules{ErrorRate: 0.5, Seed: 7})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var b strings.Builder
+		for i := 0; i < 20; i++ {
+			if resp := mockCall(t, s, "ping", nil); resp.Error != nil {
+				if resp.Error.Code != InternalError || resp.Error.Message != InjectedErrorMessage {
+					t.Fatalf("unexpected injected error %+v", resp.Error)
+				}
+				b.WriteByte('E')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		if resp := mockCall(t, s, "initialize", nil); resp.Error != nil {
+			t.Errorf("initialize must not fail: %+v", resp.Error)
+		}
+		return b.String()
+	}
+	first := outcomes()
+	if !strings.Contains(first, "E") || !strings.Contains(first, ".") {
+		t.Errorf("expected a mix of errors and results, got %s", first)
+	}
+	if second := outcomes(); second != first {
+		t.Errorf("seeded runs differ: %s and %s", first, second)
+	}
+}
+
+func TestMockServer_Latency(t *testing.T) {
+	s, err := NewMockServer(mockConfig(), nil, &MockRules{Latency: "50ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	mockCall(t, s, "ping", nil)
+	if elapsed := time.Since(start); elapsed < 50*time.Millisecond {
+		t.Errorf("response after %v, expected at least 50ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if resp := s.Handle(ctx, &Request{JSONRPC: JSONRPCVersion, Method: "ping", ID: 2}); resp != nil {
+		t.Errorf("expected no response once cancelled, got %+v", resp)
+	}
+}
+
+func TestMockServer_Recording(t *testing.T) {
+	frames, err := ReadRecording(strings.NewReader(strings.Join([]string{
+		`{"direction":"send","message":{"jsonrpc":"2.0","method":"initialize","params":{},"id":1}}`,
+		`{"direction":"receive","message":{"jsonrpc":"2.0","result":{"protocolVersion":"2025-06-18","capabilities":{},"serverInfo":{"name":"recorded","version":"9"}},"id":1}}`,
+		`{"direction":"send","message":{"jsonrpc":"2.0","method":"notifications/initialized"}}`,
+		`{"direction":"send","message":{"jsonrpc":"2.0","method":"tools/call","params":{"name":"echo","arguments":{"message":"a"}},"id":2}}`,
+		`{"direction":"receive","message":{"jsonrpc":"2.0","result":{"content":[{"type":"text","text":"a"}]},"id":2}}`,
+		`{"direction":"send","message":{"jsonrpc":"2.0","method":"tools/call","params":{"name":"echo","arguments":{"message":"b"}},"id":3}}`,
+		`{"direction":"receive","message":{"jsonrpc":"2.0","result":{"content":[{"type":"text","text":"b"}]},"id":3}}`,
+		`{"direction":"send","message":{"jsonrpc":"2.0","method":"tools/call","params":{"name":"fail"},"id":4}}`,
+		`{"direction":"receive","message":{"jsonrpc":"2.0","error":{"code":-32602,"message":"Unknown tool: fail"},"id":4}}`,
+	}, "\n")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewMockServer(nil, frames, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	init := mockCall(t, s, "initialize", map[string]interface{}{"protocolVersion": "2025-06-18"})
+	if got := compactJSON(normalizeJSON(init.Result)); !strings.Contains(got, `"name":"recorded"`) {
+		t.Errorf("expected the recorded initialize result, got %s", got)
+	}
+	for _, tc := range []struct {
+		params map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "b"}}, `"text":"b"`},
+		{map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": "z"}}, `"text":"a"`},
+		{map[string]interface{}{"name": "fail", "arguments": map[string]interface{}{}}, `"code":-32602`},
+	} {
+		resp := mockCall(t, s, "tools/call", tc.params)
+		data, _ := json.Marshal(resp)
+		if !strings.Contains(string(data), tc.want) {
+			t.Errorf("%v: expected %s in %s", tc.params, tc.want, data)
+		}
+	}
+	if resp := mockCall(t, s, "prompts/list", nil); resp.Error == nil || resp.Error.Code != MethodNotFound {
+		t.Errorf("expected unrecorded methods to be unknown, got %+v", resp)
+	}
+}
+
+func TestMockServer_HandleMessage(t *testing.T) {
+	s, err := NewMockServer(mockConfig(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if got := string(s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"ping","id":`))); got != `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}` {
+		t.Errorf("unexpected parse error response %s", got)
+	}
+	batch := `[{"jsonrpc":"2.0","method":"ping","id":1},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","method":"ping","id":"b"}]`
+	if got := string(s.HandleMessage(ctx, []byte(batch))); got != `[{"jsonrpc":"2.0","id":1,"result":{}},{"jsonrpc":"2.0","id":"b","result":{}}]` {
+		t.Errorf("unexpected batch response %s", got)
+	}
+	if got := s.HandleMessage(ctx, []byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)); got != nil {
+		t.Errorf("notification answered with %s", got)
+	}
+}
+
+func TestMockServer_ServeStream(t *testing.T) {
+	s, err := NewMockServer(mockConfig(), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	in := strings.NewReader("{\"jsonrpc\":\"2.0\",\"method\":\"ping\",\"id\":1}\n\n{\"jsonrpc\":\"2.0\",\"method\":\"notifications/initialized\"}\n")
+	var out bytes.Buffer
+	if err := s.ServeStream(in, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "{\"jsonrpc\":\"2.0\",\"id\":1,\"result\":{}}\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+func TestParseMockRules_Invalid(t *testing.T) {
+	for _, tc := range []struct {
+		rules string
+		want  string
+	}{
+		{`{"latency":"soon"}`, `invalid latency "soon"`},
+		{`{"errorRate":2}`, "errorRate must be between 0 and 1"},
+		{`{"rules":[{"name":"echo"}]}`, "rule 1: method is required"},
+		{`{"rules":[{"method":"tools/call","result":"{{.Missing"}]}`, "rule 1 (tools/call): invalid template"},
+		{`{"rules":[{"method":"ping","errorRate":0.5}]}`, "errorRate requires an error"},
+		{`{"rules":[{"method":"ping","result":{},"error":{"code":1,"message":"x"}}]}`, "set errorRate"},
+		{`{"rules":[{"method":"ping","reslt":{}}]}`, "unknown field"},
+	} {
+		if _, err := ParseMockRules([]byte(tc.rules)); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.rules, tc.want, err)
+		}
+	}
+}
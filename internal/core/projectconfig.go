@@ -2,6 +2,10 @@ package core
 
 import "time"
 
+// CLIVersion is the mcpcli release recorded in generated projects and sent as
+// client info during the MCP handshake.
+const CLIVersion = "0.4.1"
+
 type ProjectConfig struct {
 	Name        string    `json:"name"`
 	Language    string    `json:"language"`
@@ -16,13 +20,14 @@ type ProjectConfig struct {
 
 	Tools        []Tool       `json:"tools,omitempty"`
 	Resources    []Resource   `json:"resources,omitempty"`
+	Prompts      []Prompt     `json:"prompts,omitempty"`
 	Capabilities []Capability `json:"capabilities,omitempty"`
 }
 
 // NewProjectConfig creates a new project configuration with defaults
 func NewProjectConfig() *ProjectConfig {
 	return &ProjectConfig{
-		Version:   "0.4.1",
+		Version:   CLIVersion,
 		CreatedAt: time.Now(),
 	}
 }
@@ -31,6 +36,7 @@ func NewProjectConfig() *ProjectConfig {
 func (pc *ProjectConfig) GetTemplateData() *TemplateData {
 	mcpConfig := NewMCPConfig(pc.Name, pc.Version, pc.Description, pc.Tools, pc.Resources)
 	mcpConfig.SetTransport(pc.Transport, getTransportOptions(pc.Transport))
+	mcpConfig.Prompts = pc.Prompts
 
 	return &TemplateData{
 		Config:      pc,
@@ -51,6 +57,12 @@ func getTransportOptions(transportType string) map[string]interface{} {
 			"port": 8080,
 			"host": "localhost",
 		}
+	case "streamable-http", "sse":
+		return map[string]interface{}{
+			"port": 8080,
+			"host": "localhost",
+			"path": "/mcp",
+		}
 	case "websocket":
 		return map[string]interface{}{
 			"port": 8081,
@@ -24,6 +24,10 @@ func TestGetTransportOptions(t *testing.T) {
 	if ws["port"] != 8081 || ws["path"] != "/ws" {
 		t.Errorf("unexpected websocket options: %v", ws)
 	}
+	streamable := getTransportOptions("streamable-http")
+	if streamable["port"] != 8080 || streamable["path"] != "/mcp" {
+		t.Errorf("unexpected streamable-http options: %v", streamable)
+	}
 	if getTransportOptions("stdio") != nil {
 		t.Error("expected nil for stdio transport")
 	}
@@ -33,6 +37,7 @@ func TestNewProjectConfig_GetTemplateData(t *testing.T) {
 	pc := NewProjectConfig()
 	pc.Name = "example"
 	pc.Transport = "rest"
+	pc.Prompts = []Prompt{{Name: "summarize", Arguments: []PromptArgument{{Name: "text", Required: true}}}}
 	data := pc.GetTemplateData()
 	if data.Config != pc {
 		t.Error("template data should reference original config")
@@ -43,6 +48,9 @@ func TestNewProjectConfig_GetTemplateData(t *testing.T) {
 	if data.MCPConfig.Transport.Type != "rest" {
 		t.Errorf("expected transport 'rest', got %s", data.MCPConfig.Transport.Type)
 	}
+	if len(data.MCPConfig.Prompts) != 1 || data.MCPConfig.Prompts[0].Name != "summarize" {
+		t.Errorf("expected the prompts in the MCP config, got %+v", data.MCPConfig.Prompts)
+	}
 	opts := data.MCPConfig.Transport.Options
 	if opts["port"] != 8080 {
 		t.Errorf("unexpected port %v", opts["port"])
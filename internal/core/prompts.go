@@ -0,0 +1,77 @@
+package core
+
+import "fmt"
+
+// PromptDefinition is a prompt advertised by a server in its prompts/list
+// result.
+type PromptDefinition struct {
+	Name        string                     `json:"name"`
+	Title       string                     `json:"title,omitempty"`
+	Description string                     `json:"description,omitempty"`
+	Arguments   []PromptArgumentDefinition `json:"arguments,omitempty"`
+}
+
+// PromptArgumentDefinition describes an argument accepted by a prompt.
+type PromptArgumentDefinition struct {
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// ListPromptsResult is the result of the prompts/list request.
+type ListPromptsResult struct {
+	Prompts    []PromptDefinition `json:"prompts"`
+	NextCursor string             `json:"nextCursor,omitempty"`
+}
+
+// DecodeListPromptsResult decodes the result of a prompts/list response.
+func DecodeListPromptsResult(resp *Response) (*ListPromptsResult, error) {
+	var result ListPromptsResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, fmt.Errorf("invalid prompts/list result: %w", err)
+	}
+	return &result, nil
+}
+
+// CheckPromptResult verifies that the result of a prompts/get request is well
+// formed: messages must be an array of objects with a user or assistant role
+// and a single content block, checked like the content of a tool result. It
+// returns one error per problem found.
+func CheckPromptResult(result interface{}) []error {
+	obj, ok := normalizeJSON(result).(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("result must be an object, got %s", jsonType(result))}
+	}
+	var errs []error
+	if v, ok := obj["description"]; ok {
+		if _, ok := v.(string); !ok {
+			errs = append(errs, fmt.Errorf("description must be a string, got %s", jsonType(v)))
+		}
+	}
+	messages, ok := obj["messages"].([]interface{})
+	if !ok {
+		if _, present := obj["messages"]; !present {
+			return append(errs, fmt.Errorf("messages is missing"))
+		}
+		return append(errs, fmt.Errorf("messages must be an array, got %s", jsonType(obj["messages"])))
+	}
+	for i, item := range messages {
+		message, ok := item.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("messages[%d]: must be an object, got %s", i, jsonType(item)))
+			continue
+		}
+		if role, _ := message["role"].(string); role != "user" && role != "assistant" {
+			errs = append(errs, fmt.Errorf("messages[%d]: role must be \"user\" or \"assistant\"", i))
+		}
+		if _, present := message["content"]; !present {
+			errs = append(errs, fmt.Errorf("messages[%d]: content is missing", i))
+			continue
+		}
+		for _, err := range checkContentItem(message["content"]) {
+			errs = append(errs, fmt.Errorf("messages[%d].content: %w", i, err))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckPromptResult(t *testing.T) {
+	valid := map[string]interface{}{
+		"description": "A greeting",
+		"messages": []interface{}{
+			map[string]interface{}{"role": "user", "content": map[string]interface{}{"type": "text", "text": "hi"}},
+			map[string]interface{}{"role": "assistant", "content": map[string]interface{}{"type": "image", "data": "aGk=", "mimeType": "image/png"}},
+		},
+	}
+	if errs := CheckPromptResult(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	cases := []struct {
+		result interface{}
+		want   string
+	}{
+		{"text", "result must be an object"},
+		{map[string]interface{}{}, "messages is missing"},
+		{map[string]interface{}{"messages": "hi"}, "messages must be an array"},
+		{map[string]interface{}{"messages": []interface{}{}, "description": 1}, "description must be a string"},
+		{map[string]interface{}{"messages": []interface{}{"hi"}}, "messages[0]: must be an object"},
+		{map[string]interface{}{"messages": []interface{}{map[string]interface{}{"role": "system", "content": map[string]interface{}{"type": "text", "text": "hi"}}}}, `messages[0]: role must be "user" or "assistant"`},
+		{map[string]interface{}{"messages": []interface{}{map[string]interface{}{"role": "user"}}}, "messages[0]: content is missing"},
+		{map[string]interface{}{"messages": []interface{}{map[string]interface{}{"role": "user", "content": map[string]interface{}{"type": "text"}}}}, "messages[0].content: text must be a string"},
+	}
+	for _, c := range cases {
+		errs := CheckPromptResult(c.result)
+		if len(errs) == 0 {
+			t.Errorf("%v: expected an error containing %q", c.result, c.want)
+			continue
+		}
+		found := false
+		for _, err := range errs {
+			if strings.Contains(err.Error(), c.want) {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("%v: expected an error containing %q, got %v", c.result, c.want, errs)
+		}
+	}
+}
+
+func TestDecodeListPromptsResult(t *testing.T) {
+	resp := &Response{Result: map[string]interface{}{"prompts": []interface{}{
+		map[string]interface{}{"name": "review", "arguments": []interface{}{map[string]interface{}{"name": "code", "required": true}}},
+	}}}
+	result, err := DecodeListPromptsResult(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Prompts) != 1 || result.Prompts[0].Name != "review" || !result.Prompts[0].Arguments[0].Required {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := DecodeListPromptsResult(&Response{Result: map[string]interface{}{"prompts": "none"}}); err == nil {
+		t.Error("expected an error for malformed prompts")
+	}
+}
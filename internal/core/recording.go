@@ -0,0 +1,160 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Directions of recorded frames, seen from the client.
+const (
+	DirectionSend    = "send"
+	DirectionReceive = "receive"
+)
+
+// RecordedFrame is one message exchanged with a server, as stored in a
+// session recording. Messages that are not valid JSON are kept in Raw.
+type RecordedFrame struct {
+	Time      time.Time       `json:"time"`
+	Direction string          `json:"direction"`
+	Message   json.RawMessage `json:"message,omitempty"`
+	Raw       string          `json:"raw,omitempty"`
+}
+
+// Bytes returns the message as it was sent or received.
+func (f *RecordedFrame) Bytes() []byte {
+	if f.Message != nil {
+		return f.Message
+	}
+	return []byte(f.Raw)
+}
+
+// RecordingTransport wraps a transport and writes every frame sent and
+// received through it to a JSON Lines recording.
+type RecordingTransport struct {
+	inner ClientTransport
+
+	mu     sync.Mutex
+	w      io.Writer
+	err    error
+	closed bool
+	now    func() time.Time
+}
+
+// NewRecordingTransport records the frames carried by inner to w.
+func NewRecordingTransport(inner ClientTransport, w io.Writer) *RecordingTransport {
+	return &RecordingTransport{inner: inner, w: w, now: time.Now}
+}
+
+// Unwrap returns the recorded transport.
+func (t *RecordingTransport) Unwrap() ClientTransport {
+	return t.inner
+}
+
+// Send records the message and delivers it.
+func (t *RecordingTransport) Send(ctx context.Context, message []byte) error {
+	t.record(DirectionSend, message)
+	return t.inner.Send(ctx, message)
+}
+
+// Receive returns the next message from the server and records it.
+func (t *RecordingTransport) Receive() ([]byte, error) {
+	message, err := t.inner.Receive()
+	if err == nil {
+		t.record(DirectionReceive, message)
+	}
+	return message, err
+}
+
+// Close stops recording and closes the recorded transport. Frames still in
+// flight are not recorded, so the writer may be closed afterwards.
+func (t *RecordingTransport) Close() error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+	return t.inner.Close()
+}
+
+// Err returns the first error met while writing the recording.
+func (t *RecordingTransport) Err() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.err
+}
+
+func (t *RecordingTransport) record(direction string, message []byte) {
+	frame := RecordedFrame{Time: t.now().UTC(), Direction: direction}
+	if json.Valid(message) {
+		frame.Message = append(json.RawMessage(nil), message...)
+	} else {
+		frame.Raw = string(message)
+	}
+	data, err := json.Marshal(frame)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.err != nil || t.closed {
+		return
+	}
+	if err == nil {
+		_, err = t.w.Write(append(data, '\n'))
+	}
+	if err != nil {
+		t.err = fmt.Errorf("failed to record frame: %w", err)
+	}
+}
+
+// LoadRecording reads a session recording written by RecordingTransport.
+func LoadRecording(path string) ([]RecordedFrame, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer f.Close()
+	return ReadRecording(f)
+}
+
+// ReadRecording decodes a session recording, one frame per line.
+func ReadRecording(r io.Reader) ([]RecordedFrame, error) {
+	var frames []RecordedFrame
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var frame RecordedFrame
+		if err := json.Unmarshal(scanner.Bytes(), &frame); err != nil {
+			return nil, fmt.Errorf("invalid recording line %d: %w", line, err)
+		}
+		if frame.Direction != DirectionSend && frame.Direction != DirectionReceive {
+			return nil, fmt.Errorf("invalid recording line %d: unknown direction %q", line, frame.Direction)
+		}
+		frames = append(frames, frame)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read recording: %w", err)
+	}
+	return frames, nil
+}
+
+// AsCommandTransport returns the CommandTransport underneath t, looking
+// through wrappers such as RecordingTransport.
+func AsCommandTransport(t ClientTransport) (*CommandTransport, bool) {
+	for {
+		switch v := t.(type) {
+		case *CommandTransport:
+			return v, true
+		case interface{ Unwrap() ClientTransport }:
+			t = v.Unwrap()
+		default:
+			return nil, false
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// scriptedTransport returns canned messages from Receive.
+type scriptedTransport struct {
+	sent     [][]byte
+	incoming [][]byte
+}
+
+func (t *scriptedTransport) Send(_ context.Context, message []byte) error {
+	t.sent = append(t.sent, message)
+	return nil
+}
+
+func (t *scriptedTransport) Receive() ([]byte, error) {
+	if len(t.incoming) == 0 {
+		return nil, io.EOF
+	}
+	msg := t.incoming[0]
+	t.incoming = t.incoming[1:]
+	return msg, nil
+}
+
+func (t *scriptedTransport) Close() error { return nil }
+
+func TestRecordingTransport(t *testing.T) {
+	inner := &scriptedTransport{incoming: [][]byte{[]byte(`{"jsonrpc":"2.0","id":1,"result":{}}`)}}
+	var buf bytes.Buffer
+	rec := NewRecordingTransport(inner, &buf)
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	rec.now = func() time.Time { now = now.Add(time.Millisecond); return now }
+
+	if err := rec.Send(context.Background(), []byte(`{"jsonrpc":"2.0","method":"ping","id":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Receive(); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Send(context.Background(), []byte(`{"broken`)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rec.Receive(); err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+	rec.Close()
+	rec.Send(context.Background(), []byte(`{}`))
+	if rec.Err() != nil {
+		t.Fatal(rec.Err())
+	}
+	if len(inner.sent) != 3 {
+		t.Errorf("expected every message to be delivered, got %d", len(inner.sent))
+	}
+
+	frames, err := ReadRecording(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(frames) != 3 {
+		t.Fatalf("expected 3 frames, got %d", len(frames))
+	}
+	if frames[0].Direction != DirectionSend || string(frames[0].Bytes()) != `{"jsonrpc":"2.0","method":"ping","id":1}` || !frames[0].Time.Equal(time.Date(2025, 6, 1, 12, 0, 0, int(time.Millisecond), time.UTC)) {
+		t.Errorf("unexpected first frame %+v", frames[0])
+	}
+	if frames[1].Direction != DirectionReceive || !frames[1].Time.After(frames[0].Time) {
+		t.Errorf("unexpected second frame %+v", frames[1])
+	}
+	if frames[2].Message != nil || frames[2].Raw != `{"broken` {
+		t.Errorf("expected malformed message to be kept raw, got %+v", frames[2])
+	}
+}
+
+func TestRecordingTransport_WriteError(t *testing.T) {
+	rec := NewRecordingTransport(&scriptedTransport{}, failWriter{})
+	if err := rec.Send(context.Background(), []byte(`{}`)); err != nil {
+		t.Fatalf("recording errors must not fail the exchange: %v", err)
+	}
+	if err := rec.Err(); err == nil || !strings.Contains(err.Error(), "failed to record frame") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestReadRecording_Invalid(t *testing.T) {
+	for input, want := range map[string]string{
+		"{\"direction\":\"send\"}\nnot json\n":         "line 2",
+		`{"direction":"sideways","message":{}}` + "\n": "unknown direction",
+	} {
+		if _, err := ReadRecording(strings.NewReader(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected error containing %q, got %v", input, want, err)
+		}
+	}
+	if _, err := LoadRecording("/nonexistent/session.jsonl"); err == nil {
+		t.Error("expected error for missing file")
+	}
+}
+
+func TestAsCommandTransport(t *testing.T) {
+	cmd := &CommandTransport{}
+	if got, ok := AsCommandTransport(NewRecordingTransport(cmd, io.Discard)); !ok || got != cmd {
+		t.Error("expected the wrapped command transport")
+	}
+	if _, ok := AsCommandTransport(&scriptedTransport{}); ok {
+		t.Error("unexpected command transport")
+	}
+}
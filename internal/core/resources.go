@@ -0,0 +1,117 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// ResourceDefinition is a resource advertised by a server in its
+// resources/list result.
+type ResourceDefinition struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ListResourcesResult is the result of the resources/list request.
+type ListResourcesResult struct {
+	Resources  []ResourceDefinition `json:"resources"`
+	NextCursor string               `json:"nextCursor,omitempty"`
+}
+
+// DecodeListResourcesResult decodes the result of a resources/list response.
+func DecodeListResourcesResult(resp *Response) (*ListResourcesResult, error) {
+	var result ListResourcesResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, fmt.Errorf("invalid resources/list result: %w", err)
+	}
+	return &result, nil
+}
+
+// ResourceTemplateDefinition is a resource template advertised by a server in
+// its resources/templates/list result.
+type ResourceTemplateDefinition struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ListResourceTemplatesResult is the result of the resources/templates/list
+// request.
+type ListResourceTemplatesResult struct {
+	ResourceTemplates []ResourceTemplateDefinition `json:"resourceTemplates"`
+	NextCursor        string                       `json:"nextCursor,omitempty"`
+}
+
+// DecodeListResourceTemplatesResult decodes the result of a
+// resources/templates/list response.
+func DecodeListResourceTemplatesResult(resp *Response) (*ListResourceTemplatesResult, error) {
+	var result ListResourceTemplatesResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, fmt.Errorf("invalid resources/templates/list result: %w", err)
+	}
+	return &result, nil
+}
+
+// CheckReadResourceResult verifies that the result of a resources/read
+// request is well formed: contents must be an array of objects, each with a
+// uri and either a text or a base64 blob. It returns one error per problem
+// found.
+func CheckReadResourceResult(result interface{}) []error {
+	obj, ok := normalizeJSON(result).(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("result must be an object, got %s", jsonType(result))}
+	}
+	contents, ok := obj["contents"].([]interface{})
+	if !ok {
+		if _, present := obj["contents"]; !present {
+			return []error{fmt.Errorf("contents is missing")}
+		}
+		return []error{fmt.Errorf("contents must be an array, got %s", jsonType(obj["contents"]))}
+	}
+	var errs []error
+	for i, item := range contents {
+		resource, ok := item.(map[string]interface{})
+		if !ok {
+			errs = append(errs, fmt.Errorf("contents[%d]: must be an object, got %s", i, jsonType(item)))
+			continue
+		}
+		for _, err := range checkResourceContents(resource) {
+			errs = append(errs, fmt.Errorf("contents[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// checkResourceContents verifies the contents of a resource, as read or
+// embedded in a tool result.
+func checkResourceContents(resource map[string]interface{}) []error {
+	var errs []error
+	if _, ok := resource["uri"].(string); !ok {
+		errs = append(errs, fmt.Errorf("uri must be a string"))
+	}
+	_, hasText := resource["text"]
+	blob, hasBlob := resource["blob"]
+	switch {
+	case hasText && hasBlob:
+		errs = append(errs, fmt.Errorf("resource must not have both text and blob"))
+	case hasText:
+		if _, ok := resource["text"].(string); !ok {
+			errs = append(errs, fmt.Errorf("text must be a string"))
+		}
+	case hasBlob:
+		s, ok := blob.(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("blob must be a string"))
+		} else if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+			errs = append(errs, fmt.Errorf("blob is not valid base64"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("resource must have text or blob"))
+	}
+	return errs
+}
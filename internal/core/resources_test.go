@@ -0,0 +1,72 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckReadResourceResult(t *testing.T) {
+	valid := map[string]interface{}{"contents": []interface{}{
+		map[string]interface{}{"uri": "file:///a.txt", "mimeType": "text/plain", "text": "hi"},
+		map[string]interface{}{"uri": "file:///a.png", "blob": "aGk="},
+	}}
+	if errs := CheckReadResourceResult(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	cases := []struct {
+		result interface{}
+		want   string
+	}{
+		{"text", "result must be an object"},
+		{map[string]interface{}{}, "contents is missing"},
+		{map[string]interface{}{"contents": "hi"}, "contents must be an array"},
+		{map[string]interface{}{"contents": []interface{}{"hi"}}, "contents[0]: must be an object"},
+		{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"text": "hi"}}}, "contents[0]: uri must be a string"},
+		{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"uri": "x"}}}, "contents[0]: resource must have text or blob"},
+		{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"uri": "x", "text": "a", "blob": "aGk="}}}, "must not have both text and blob"},
+		{map[string]interface{}{"contents": []interface{}{map[string]interface{}{"uri": "x", "blob": "!"}}}, "contents[0]: blob is not valid base64"},
+	}
+	for _, c := range cases {
+		errs := CheckReadResourceResult(c.result)
+		found := false
+		for _, err := range errs {
+			found = found || strings.Contains(err.Error(), c.want)
+		}
+		if !found {
+			t.Errorf("%v: expected an error containing %q, got %v", c.result, c.want, errs)
+		}
+	}
+}
+
+func TestDecodeListResourceTemplatesResult(t *testing.T) {
+	resp := &Response{Result: map[string]interface{}{"resourceTemplates": []interface{}{
+		map[string]interface{}{"uriTemplate": "file:///{path}", "name": "files"},
+	}}}
+	result, err := DecodeListResourceTemplatesResult(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.ResourceTemplates) != 1 || result.ResourceTemplates[0].URITemplate != "file:///{path}" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := DecodeListResourceTemplatesResult(&Response{Result: map[string]interface{}{"resourceTemplates": "none"}}); err == nil {
+		t.Error("expected an error for malformed templates")
+	}
+}
+
+func TestDecodeListResourcesResult(t *testing.T) {
+	resp := &Response{Result: map[string]interface{}{"resources": []interface{}{
+		map[string]interface{}{"uri": "file:///a", "name": "a", "mimeType": "text/plain"},
+	}}}
+	result, err := DecodeListResourcesResult(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Resources) != 1 || result.Resources[0].URI != "file:///a" {
+		t.Errorf("unexpected result %+v", result)
+	}
+	if _, err := DecodeListResourcesResult(&Response{Result: map[string]interface{}{"resources": "none"}}); err == nil {
+		t.Error("expected an error for malformed resources")
+	}
+}
@@ -0,0 +1,395 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Scenario is a scripted sequence of MCP requests with expectations, run by
+// `mcpcli test --script` after the initialize handshake.
+type Scenario struct {
+	Name  string         `json:"name,omitempty"`
+	Steps []ScenarioStep `json:"steps"`
+}
+
+// ScenarioStep sends one request, or notification, and checks the response.
+// String values in params and assertions may reference captured variables as
+// ${name}.
+type ScenarioStep struct {
+	Name         string                 `json:"name,omitempty"`
+	Method       string                 `json:"method"`
+	Params       map[string]interface{} `json:"params,omitempty"`
+	Notification bool                   `json:"notification,omitempty"`
+	ExpectError  *ExpectedError         `json:"expectError,omitempty"`
+	Assert       []Assertion            `json:"assert,omitempty"`
+	// Capture maps variable names to JSONPath expressions evaluated against
+	// the response.
+	Capture map[string]string `json:"capture,omitempty"`
+}
+
+// ExpectedError describes the JSON-RPC error a step must fail with.
+type ExpectedError struct {
+	Code *int `json:"code,omitempty"`
+	// Message is a regular expression the error message must match.
+	Message string `json:"message,omitempty"`
+}
+
+// Assertion checks the value found at a JSONPath of the response object, so
+// paths start with $.result or $.error.
+type Assertion struct {
+	Path   string          `json:"path"`
+	Equals json.RawMessage `json:"equals,omitempty"`
+	// Matches is a regular expression the string value must match.
+	Matches string `json:"matches,omitempty"`
+	// Type is one of string, number, integer, boolean, object, array or null.
+	Type   string `json:"type,omitempty"`
+	Exists *bool  `json:"exists,omitempty"`
+}
+
+// ScenarioVars holds the values captured by previous steps.
+type ScenarioVars map[string]interface{}
+
+var (
+	variablePattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+	jsonTypes       = []string{"string", "number", "integer", "boolean", "object", "array", "null"}
+)
+
+// LoadScenario reads and validates a scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read script file: %w", err)
+	}
+	return ParseScenario(data)
+}
+
+// ParseScenario decodes and validates a JSON scenario. Unknown fields are
+// rejected so that typos in assertions do not silently pass.
+func ParseScenario(data []byte) (*Scenario, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var s Scenario
+	if err := dec.Decode(&s); err != nil {
+		return nil, FormatJSONError(data, err, "failed to parse script file")
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Validate checks that every step is well formed.
+func (s *Scenario) Validate() error {
+	for i, step := range s.Steps {
+		label := fmt.Sprintf("step %d", i+1)
+		if step.Name != "" {
+			label = fmt.Sprintf("step %d (%s)", i+1, step.Name)
+		}
+		if step.Method == "" {
+			return fmt.Errorf("%s: method is required", label)
+		}
+		if step.Notification && (step.ExpectError != nil || len(step.Assert) > 0 || len(step.Capture) > 0) {
+			return fmt.Errorf("%s: notifications have no response to check", label)
+		}
+		if step.ExpectError != nil && step.ExpectError.Message != "" {
+			if _, err := regexp.Compile(step.ExpectError.Message); err != nil {
+				return fmt.Errorf("%s: invalid expectError message pattern: %w", label, err)
+			}
+		}
+		for _, a := range step.Assert {
+			if err := a.validate(); err != nil {
+				return fmt.Errorf("%s: %w", label, err)
+			}
+		}
+		for name, path := range step.Capture {
+			if !variablePattern.MatchString("${" + name + "}") {
+				return fmt.Errorf("%s: invalid variable name %q", label, name)
+			}
+			if _, err := parseJSONPath(path); err != nil {
+				return fmt.Errorf("%s: capture %s: %w", label, name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (a Assertion) validate() error {
+	if _, err := parseJSONPath(a.Path); err != nil {
+		return err
+	}
+	if a.Equals == nil && a.Matches == "" && a.Type == "" && a.Exists == nil {
+		return fmt.Errorf("assertion on %s checks nothing: set equals, matches, type or exists", a.Path)
+	}
+	if a.Matches != "" {
+		if _, err := regexp.Compile(a.Matches); err != nil {
+			return fmt.Errorf("assertion on %s: invalid pattern: %w", a.Path, err)
+		}
+	}
+	if a.Type != "" && !containsString(jsonTypes, a.Type) {
+		return fmt.Errorf("assertion on %s: unknown type %q, valid types are %v", a.Path, a.Type, jsonTypes)
+	}
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Expand replaces ${name} references in strings nested anywhere in value. A
+// string consisting of a single reference is replaced by the captured value
+// itself, keeping its JSON type.
+func (v ScenarioVars) Expand(value interface{}) (interface{}, error) {
+	switch t := value.(type) {
+	case string:
+		if m := variablePattern.FindStringSubmatch(t); m != nil && m[0] == t {
+			captured, ok := v[m[1]]
+			if !ok {
+				return nil, fmt.Errorf("undefined variable %s", m[1])
+			}
+			return captured, nil
+		}
+		var missing string
+		out := variablePattern.ReplaceAllStringFunc(t, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			captured, ok := v[name]
+			if !ok {
+				missing = name
+				return ref
+			}
+			if s, ok := captured.(string); ok {
+				return s
+			}
+			data, _ := json.Marshal(captured)
+			return string(data)
+		})
+		if missing != "" {
+			return nil, fmt.Errorf("undefined variable %s", missing)
+		}
+		return out, nil
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, item := range t {
+			expanded, err := v.Expand(item)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = expanded
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, item := range t {
+			expanded, err := v.Expand(item)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = expanded
+		}
+		return out, nil
+	default:
+		return value, nil
+	}
+}
+
+// ExpandParams expands the variables referenced in the step parameters.
+func (s *ScenarioStep) ExpandParams(vars ScenarioVars) (map[string]interface{}, error) {
+	if s.Params == nil {
+		return nil, nil
+	}
+	expanded, err := vars.Expand(s.Params)
+	if err != nil {
+		return nil, err
+	}
+	return expanded.(map[string]interface{}), nil
+}
+
+// Check verifies the response against the step expectations and returns one
+// error per failed expectation.
+func (s *ScenarioStep) Check(resp *Response, vars ScenarioVars) []error {
+	var failures []error
+	if s.ExpectError != nil {
+		failures = append(failures, s.ExpectError.check(resp)...)
+	} else if resp.Error != nil {
+		failures = append(failures, fmt.Errorf("unexpected error: %v", resp.Error))
+	}
+	doc, err := ResponseDocument(resp)
+	if err != nil {
+		return append(failures, err)
+	}
+	for _, a := range s.Assert {
+		if err := a.Check(doc, vars); err != nil {
+			failures = append(failures, err)
+		}
+	}
+	return failures
+}
+
+// CaptureInto stores the captured response values in vars.
+func (s *ScenarioStep) CaptureInto(resp *Response, vars ScenarioVars) error {
+	if len(s.Capture) == 0 {
+		return nil
+	}
+	doc, err := ResponseDocument(resp)
+	if err != nil {
+		return err
+	}
+	for name, path := range s.Capture {
+		value, found, err := EvalJSONPath(doc, path)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return fmt.Errorf("capture %s: %s not found in response", name, path)
+		}
+		vars[name] = value
+	}
+	return nil
+}
+
+func (e *ExpectedError) check(resp *Response) []error {
+	if resp.Error == nil {
+		return []error{fmt.Errorf("expected an error response, got result %s", compactJSON(resp.Result))}
+	}
+	var failures []error
+	if e.Code != nil && resp.Error.Code != *e.Code {
+		failures = append(failures, fmt.Errorf("expected error code %d, got %d", *e.Code, resp.Error.Code))
+	}
+	if e.Message != "" && !regexp.MustCompile(e.Message).MatchString(resp.Error.Message) {
+		failures = append(failures, fmt.Errorf("error message %q does not match %q", resp.Error.Message, e.Message))
+	}
+	return failures
+}
+
+// Check evaluates the assertion against a response document.
+func (a Assertion) Check(doc interface{}, vars ScenarioVars) error {
+	value, found, err := EvalJSONPath(doc, a.Path)
+	if err != nil {
+		return err
+	}
+	if a.Exists != nil {
+		if found != *a.Exists {
+			if found {
+				return fmt.Errorf("%s: expected no value, got %s", a.Path, compactJSON(value))
+			}
+			return fmt.Errorf("%s: expected a value, found none", a.Path)
+		}
+		if !found {
+			return nil
+		}
+	}
+	if !found {
+		return fmt.Errorf("%s: not found", a.Path)
+	}
+	if a.Type != "" {
+		if got := jsonType(value); got != a.Type && !(a.Type == "number" && got == "integer") {
+			return fmt.Errorf("%s: expected type %s, got %s", a.Path, a.Type, got)
+		}
+	}
+	if a.Matches != "" {
+		expanded, err := vars.Expand(a.Matches)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Path, err)
+		}
+		pattern := fmt.Sprint(expanded)
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string to match %q, got %s", a.Path, pattern, compactJSON(value))
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", a.Path, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %q", a.Path, s, pattern)
+		}
+	}
+	if a.Equals != nil {
+		var raw interface{}
+		if err := json.Unmarshal(a.Equals, &raw); err != nil {
+			return fmt.Errorf("%s: invalid expected value: %w", a.Path, err)
+		}
+		expected, err := vars.Expand(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", a.Path, err)
+		}
+		if !reflect.DeepEqual(normalizeJSON(expected), normalizeJSON(value)) {
+			return fmt.Errorf("%s: expected %s, got %s", a.Path, compactJSON(expected), compactJSON(value))
+		}
+	}
+	return nil
+}
+
+// ResponseDocument converts a response to its decoded JSON form so that it
+// can be queried with EvalJSONPath.
+func ResponseDocument(resp *Response) (interface{}, error) {
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode response: %w", err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return doc, nil
+}
+
+// normalizeJSON round-trips a value through JSON so that numbers compare
+// equal regardless of their Go type.
+func normalizeJSON(v interface{}) interface{} {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out interface{}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return v
+	}
+	return out
+}
+
+// jsonType returns the JSON type name of a decoded value.
+func jsonType(v interface{}) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case float64:
+		if t == float64(int64(t)) {
+			return "integer"
+		}
+		return "number"
+	case json.Number:
+		if strings.ContainsAny(t.String(), ".eE") {
+			return "number"
+		}
+		return "integer"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// compactJSON renders a value for error messages.
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,94 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseScenario_Validation(t *testing.T) {
+	cases := map[string]string{
+		`{"steps":[{}]}`: "method is required",
+		`{"steps":[{"method":"x","notification":true,"assert":[{"path":"$","exists":true}]}]}`: "no response",
+		`{"steps":[{"method":"x","assert":[{"path":"$.result"}]}]}`:                            "checks nothing",
+		`{"steps":[{"method":"x","assert":[{"path":"$","type":"text"}]}]}`:                     "unknown type",
+		`{"steps":[{"method":"x","assert":[{"path":"$","matches":"("}]}]}`:                     "invalid pattern",
+		`{"steps":[{"method":"x","capture":{"bad-name":"$"}}]}`:                                "invalid variable name",
+		`{"steps":[{"method":"x","capture":{"v":"result"}}]}`:                                  "must start with $",
+		`{"steps":[{"method":"x","expect":{}}]}`:                                               "expect",
+	}
+	for input, want := range cases {
+		if _, err := ParseScenario([]byte(input)); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: expected error containing %q, got %v", input, want, err)
+		}
+	}
+}
+
+func TestScenarioVars_Expand(t *testing.T) {
+	vars := ScenarioVars{"name": "echo", "count": float64(2)}
+	got, err := vars.Expand(map[string]interface{}{
+		"a": "${count}",
+		"b": []interface{}{"tool ${name} x${count}"},
+		"c": true,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	m := got.(map[string]interface{})
+	if m["a"] != float64(2) || m["b"].([]interface{})[0] != "tool echo x2" || m["c"] != true {
+		t.Errorf("unexpected expansion: %v", m)
+	}
+	if _, err := vars.Expand("${missing}"); err == nil {
+		t.Error("expected error for undefined variable")
+	}
+	if _, err := vars.Expand("a ${missing}"); err == nil {
+		t.Error("expected error for undefined variable")
+	}
+}
+
+func TestScenarioStep_CheckAndCapture(t *testing.T) {
+	s, err := ParseScenario([]byte(`{"steps":[{"method":"tools/list",
+		"assert":[
+			{"path":"$.result.tools[0].name","equals":"${want}"},
+			{"path":"$.result.tools[0].n","type":"number"},
+			{"path":"$.result.tools[0].name","matches":"^e"},
+			{"path":"$.result.other","exists":false}
+		],
+		"capture":{"first":"$.result.tools[0]"}}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	step := &s.Steps[0]
+	resp := &Response{JSONRPC: JSONRPCVersion, ID: 1, Result: map[string]interface{}{
+		"tools": []interface{}{map[string]interface{}{"name": "echo", "n": 3}},
+	}}
+	vars := ScenarioVars{"want": "echo"}
+	if failures := step.Check(resp, vars); len(failures) != 0 {
+		t.Fatalf("unexpected failures: %v", failures)
+	}
+	if err := step.CaptureInto(resp, vars); err != nil {
+		t.Fatal(err)
+	}
+	if first, ok := vars["first"].(map[string]interface{}); !ok || first["name"] != "echo" {
+		t.Errorf("unexpected capture: %v", vars["first"])
+	}
+
+	vars["want"] = "other"
+	failures := step.Check(&Response{JSONRPC: JSONRPCVersion, ID: 1, Error: &Error{Code: MethodNotFound, Message: "nope"}}, vars)
+	if len(failures) != 4 || !strings.Contains(failures[0].Error(), "unexpected error") {
+		t.Errorf("unexpected failures: %v", failures)
+	}
+}
+
+func TestExpectedError_Check(t *testing.T) {
+	code := InvalidParams
+	e := &ExpectedError{Code: &code, Message: "^Unknown"}
+	if failures := e.check(&Response{Error: &Error{Code: InvalidParams, Message: "Unknown tool"}}); len(failures) != 0 {
+		t.Errorf("unexpected failures: %v", failures)
+	}
+	if failures := e.check(&Response{Error: &Error{Code: InternalError, Message: "boom"}}); len(failures) != 2 {
+		t.Errorf("expected code and message failures, got %v", failures)
+	}
+	if failures := e.check(&Response{Result: map[string]interface{}{}}); len(failures) != 1 {
+		t.Errorf("expected missing error failure, got %v", failures)
+	}
+}
@@ -0,0 +1,399 @@
+package core
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Sizes of the oversized values produced by the fuzzer.
+const (
+	fuzzHugeStringLength = 1 << 20
+	fuzzLongArrayLength  = 10000
+	fuzzNestingDepth     = 128
+)
+
+// fuzzUnicode mixes scripts, emoji, combining marks, bidirectional overrides,
+// zero-width characters and control characters.
+const fuzzUnicode = "h\u00e9llo w\u00f6rld \u4f60\u597d \u3053\u3093\u306b\u3061\u306f \u0645\u0631\u062d\u0628\u0627 " +
+	"\U0001F680 \U0001F469\u200d\U0001F469\u200d\U0001F467 e\u0301 \u202egnp.exe \u200b\ufeff \x00\x07\t\r\n"
+
+// fuzzRunes is the alphabet of random strings. It leaves out $ so that the
+// reproducers written as scenarios are not subject to variable expansion.
+var fuzzRunes = []rune("abcXYZ019 _-./\\\"'<>&%#@!?\u00e9\u4f60\U0001F680\x00\n\t\u200b")
+
+// FuzzInput is a value generated by SchemaFuzzer.
+type FuzzInput struct {
+	// Label describes how the value was built.
+	Label string
+	Value interface{}
+	// Valid reports whether the value conforms to the schema.
+	Valid bool
+}
+
+// SchemaFuzzer generates conforming, edge-case and random values for a JSON
+// Schema. Its output is fully determined by the seed.
+type SchemaFuzzer struct {
+	rng *rand.Rand
+}
+
+// NewSchemaFuzzer returns a fuzzer seeded with seed.
+func NewSchemaFuzzer(seed int64) *SchemaFuzzer {
+	return &SchemaFuzzer{rng: rand.New(rand.NewSource(seed))}
+}
+
+// EdgeCases returns inputs for an object schema built from a conforming
+// sample: the sample itself, no or non-object arguments, each required
+// property missing, an unknown property, and for each property null, a value
+// of the wrong type and boundary values such as huge strings, unicode and
+// extreme numbers.
+func (f *SchemaFuzzer) EdgeCases(s *Schema) []FuzzInput {
+	base, _ := s.Sample()
+	obj, ok := base.(map[string]interface{})
+	if !ok {
+		obj = map[string]interface{}{}
+	}
+	var inputs []FuzzInput
+	add := func(label string, v interface{}) {
+		inputs = append(inputs, FuzzInput{Label: label, Value: v, Valid: s.Accepts(v)})
+	}
+	add("conforming sample", obj)
+	add("empty arguments", map[string]interface{}{})
+	add("null arguments", nil)
+	add("array arguments", []interface{}{obj})
+	for _, name := range s.Required {
+		v := copyObject(obj)
+		delete(v, name)
+		add("missing required "+name, v)
+	}
+	unknown := copyObject(obj)
+	unknown["__fuzz_unknown__"] = "unexpected"
+	add("unknown property", unknown)
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, edge := range edgeValues(s.Properties[name]) {
+			v := copyObject(obj)
+			v[name] = edge.Value
+			add(name+": "+edge.Label, v)
+		}
+	}
+	return inputs
+}
+
+// edgeValues lists boundary values for a property schema.
+func edgeValues(s *Schema) []FuzzInput {
+	values := []FuzzInput{
+		{Label: "null", Value: nil},
+		{Label: "wrong type", Value: wrongType(s)},
+	}
+	types := s.Types
+	if s.ref != nil && len(types) == 0 {
+		types = s.ref.Types
+	}
+	for _, t := range types {
+		switch t {
+		case "string":
+			values = append(values,
+				FuzzInput{Label: "empty string", Value: ""},
+				FuzzInput{Label: "huge string", Value: strings.Repeat("A", fuzzHugeStringLength)},
+				FuzzInput{Label: "unicode", Value: fuzzUnicode},
+			)
+		case "integer", "number":
+			values = append(values,
+				FuzzInput{Label: "zero", Value: 0.0},
+				FuzzInput{Label: "negative", Value: -1.0},
+				FuzzInput{Label: "huge number", Value: math.MaxFloat64},
+				FuzzInput{Label: "huge negative number", Value: -math.MaxFloat64},
+				FuzzInput{Label: "max int64", Value: float64(math.MaxInt64)},
+				FuzzInput{Label: "fraction", Value: 0.5},
+			)
+		case "array":
+			long := make([]interface{}, fuzzLongArrayLength)
+			for i := range long {
+				long[i] = float64(i)
+			}
+			values = append(values,
+				FuzzInput{Label: "empty array", Value: []interface{}{}},
+				FuzzInput{Label: "long array", Value: long},
+			)
+		case "object":
+			var deep interface{} = map[string]interface{}{}
+			for i := 0; i < fuzzNestingDepth; i++ {
+				deep = map[string]interface{}{"nested": deep}
+			}
+			values = append(values,
+				FuzzInput{Label: "empty object", Value: map[string]interface{}{}},
+				FuzzInput{Label: "deep nesting", Value: deep},
+			)
+		case "boolean":
+			values = append(values, FuzzInput{Label: "string boolean", Value: "true"})
+		}
+	}
+	return values
+}
+
+// wrongType returns a value whose type the schema does not allow.
+func wrongType(s *Schema) interface{} {
+	candidates := []interface{}{"not a number", 12345.0, true, []interface{}{}, map[string]interface{}{}}
+	for _, c := range candidates {
+		if len(s.Types) == 0 || !containsString(s.Types, jsonType(c)) && !(jsonType(c) == "integer" && containsString(s.Types, "number")) {
+			return c
+		}
+	}
+	return nil
+}
+
+// Random returns a random value shaped by the schema. Most values follow the
+// schema's types and properties, with random contents, so some of them
+// conform and others break constraints; a few ignore the schema entirely.
+func (f *SchemaFuzzer) Random(s *Schema) FuzzInput {
+	v := f.randomValue(s, 0)
+	label := "random"
+	if desc := compactJSON(v); len(desc) < 200 {
+		label = "random " + desc
+	}
+	return FuzzInput{Label: label, Value: v, Valid: s.Accepts(v)}
+}
+
+func (f *SchemaFuzzer) randomValue(s *Schema, depth int) interface{} {
+	if s != nil && s.ref != nil && depth < 8 {
+		return f.randomValue(s.ref, depth+1)
+	}
+	if s == nil || depth > 8 || f.rng.Intn(10) == 0 {
+		return f.randomAny(depth)
+	}
+	if len(s.Enum) > 0 && f.rng.Intn(4) != 0 {
+		return s.Enum[f.rng.Intn(len(s.Enum))]
+	}
+	if s.HasConst && f.rng.Intn(4) != 0 {
+		return s.Const
+	}
+	for _, branches := range [][]*Schema{s.OneOf, s.AnyOf} {
+		if len(branches) > 0 {
+			return f.randomValue(branches[f.rng.Intn(len(branches))], depth+1)
+		}
+	}
+	types := s.Types
+	if len(types) == 0 {
+		types = s.impliedTypes()
+	}
+	if len(types) == 0 {
+		return f.randomAny(depth)
+	}
+	switch types[f.rng.Intn(len(types))] {
+	case "null":
+		return nil
+	case "boolean":
+		return f.rng.Intn(2) == 0
+	case "integer":
+		return math.Round(f.randomNumber(s))
+	case "number":
+		return f.randomNumber(s)
+	case "string":
+		return f.randomString(s)
+	case "array":
+		n := f.rng.Intn(5)
+		if s.MinItems != nil && f.rng.Intn(2) == 0 {
+			n = *s.MinItems + f.rng.Intn(3) - 1
+		}
+		out := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			item := s.Items
+			if i < len(s.PrefixItems) {
+				item = s.PrefixItems[i]
+			}
+			out = append(out, f.randomValue(item, depth+1))
+		}
+		return out
+	default:
+		out := map[string]interface{}{}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			chance := 2
+			if containsString(s.Required, name) {
+				chance = 10
+			}
+			if f.rng.Intn(10) < chance {
+				out[name] = f.randomValue(s.Properties[name], depth+1)
+			}
+		}
+		if f.rng.Intn(10) == 0 {
+			out[f.randomString(nil)] = f.randomAny(depth + 1)
+		}
+		return out
+	}
+}
+
+func (f *SchemaFuzzer) randomAny(depth int) interface{} {
+	n := 6
+	if depth > 8 {
+		n = 4
+	}
+	switch f.rng.Intn(n) {
+	case 0:
+		return nil
+	case 1:
+		return f.rng.Intn(2) == 0
+	case 2:
+		return f.randomNumber(nil)
+	case 3:
+		return f.randomString(nil)
+	case 4:
+		return []interface{}{f.randomAny(depth + 1)}
+	default:
+		return map[string]interface{}{f.randomString(nil): f.randomAny(depth + 1)}
+	}
+}
+
+// randomNumber returns a number around the schema bounds, or an extreme value.
+func (f *SchemaFuzzer) randomNumber(s *Schema) float64 {
+	var bounds []float64
+	if s != nil {
+		for _, b := range []*float64{s.Minimum, s.Maximum, s.ExclusiveMinimum, s.ExclusiveMaximum} {
+			if b != nil {
+				bounds = append(bounds, *b)
+			}
+		}
+	}
+	switch r := f.rng.Intn(10); {
+	case r < 4 && len(bounds) > 0:
+		return bounds[f.rng.Intn(len(bounds))] + float64(f.rng.Intn(3)-1)
+	case r == 9:
+		return []float64{math.MaxFloat64, -math.MaxFloat64, math.SmallestNonzeroFloat64, float64(math.MaxInt64), float64(math.MinInt64)}[f.rng.Intn(5)]
+	case r == 8:
+		return f.rng.NormFloat64() * 1e6
+	default:
+		return float64(f.rng.Intn(201) - 100)
+	}
+}
+
+// randomString returns a string of random length drawn from fuzzRunes.
+func (f *SchemaFuzzer) randomString(s *Schema) string {
+	n := f.rng.Intn(16)
+	if s != nil {
+		if s.MinLength != nil && f.rng.Intn(2) == 0 {
+			n = *s.MinLength + f.rng.Intn(3) - 1
+		}
+		if s.MaxLength != nil && f.rng.Intn(2) == 0 {
+			n = *s.MaxLength + f.rng.Intn(3) - 1
+		}
+		if v, ok := formatSamples[s.Format]; ok && f.rng.Intn(2) == 0 {
+			return v
+		}
+	}
+	if f.rng.Intn(20) == 0 {
+		n = fuzzHugeStringLength / 16
+	}
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		b.WriteRune(fuzzRunes[f.rng.Intn(len(fuzzRunes))])
+	}
+	return b.String()
+}
+
+// ShrinkValue looks for a smaller value that still fails, by removing object
+// members and array items and shortening strings and numbers, calling fails
+// at most maxAttempts times. It returns the smallest failing value found.
+func ShrinkValue(v interface{}, fails func(interface{}) bool, maxAttempts int) interface{} {
+	attempts := 0
+	for {
+		improved := false
+		for _, c := range simplerValues(v) {
+			if attempts >= maxAttempts {
+				return v
+			}
+			attempts++
+			if fails(c) {
+				v, improved = c, true
+				break
+			}
+		}
+		if !improved {
+			return v
+		}
+	}
+}
+
+// simplerValues lists the values one shrinking step away from v, most
+// aggressive first.
+func simplerValues(v interface{}) []interface{} {
+	var out []interface{}
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			c := copyObject(t)
+			delete(c, k)
+			out = append(out, c)
+		}
+		for _, k := range keys {
+			for _, child := range simplerValues(t[k]) {
+				c := copyObject(t)
+				c[k] = child
+				out = append(out, c)
+			}
+		}
+	case []interface{}:
+		if len(t) == 0 {
+			return nil
+		}
+		half := len(t) / 2
+		out = append(out, []interface{}{}, cloneSlice(t[:half]), cloneSlice(t[half:]))
+		if len(t) <= 8 {
+			for i := range t {
+				c := append(cloneSlice(t[:i]), t[i+1:]...)
+				out = append(out, c)
+			}
+			for i := range t {
+				for _, child := range simplerValues(t[i]) {
+					c := cloneSlice(t)
+					c[i] = child
+					out = append(out, c)
+				}
+			}
+		}
+	case string:
+		if t == "" {
+			return nil
+		}
+		r := []rune(t)
+		out = append(out, "", string(r[:len(r)/2]))
+		if len(r) > 1 {
+			out = append(out, string(r[len(r)/2:]))
+		}
+	case float64:
+		if t == 0 {
+			return nil
+		}
+		out = append(out, 0.0)
+		if math.Trunc(t) != t {
+			out = append(out, math.Trunc(t))
+		}
+		if math.Abs(t) > 1 {
+			out = append(out, math.Trunc(t/2))
+		}
+	case bool:
+		if t {
+			out = append(out, false)
+		}
+	}
+	return out
+}
+
+func cloneSlice(s []interface{}) []interface{} {
+	return append([]interface{}{}, s...)
+}
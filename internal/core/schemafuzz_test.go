@@ -0,0 +1,117 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func fuzzSchema(t *testing.T) *Schema {
+	t.Helper()
+	s, err := ParseSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"count": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}}
+		},
+		"required": ["name"]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestSchemaFuzzer_EdgeCases(t *testing.T) {
+	s := fuzzSchema(t)
+	inputs := NewSchemaFuzzer(1).EdgeCases(s)
+	byLabel := map[string]FuzzInput{}
+	for _, in := range inputs {
+		byLabel[in.Label] = in
+	}
+	want := map[string]bool{
+		"conforming sample":     true,
+		"empty arguments":       false,
+		"null arguments":        false,
+		"missing required name": false,
+		"unknown property":      true,
+		"name: empty string":    false,
+		"name: huge string":     true,
+		"name: unicode":         true,
+		"count: wrong type":     false,
+		"count: negative":       false,
+		"count: fraction":       false,
+		"tags: long array":      false,
+		"tags: empty array":     true,
+	}
+	for label, valid := range want {
+		in, ok := byLabel[label]
+		if !ok {
+			t.Errorf("missing edge case %q", label)
+			continue
+		}
+		if in.Valid != valid {
+			t.Errorf("%s: Valid = %v, want %v", label, in.Valid, valid)
+		}
+	}
+	if huge := byLabel["name: huge string"].Value.(map[string]interface{})["name"].(string); len(huge) != fuzzHugeStringLength {
+		t.Errorf("unexpected huge string length %d", len(huge))
+	}
+}
+
+func TestSchemaFuzzer_Deterministic(t *testing.T) {
+	s := fuzzSchema(t)
+	a, b := NewSchemaFuzzer(42), NewSchemaFuzzer(42)
+	differs := false
+	c := NewSchemaFuzzer(43)
+	for i := 0; i < 50; i++ {
+		va, vb, vc := a.Random(s), b.Random(s), c.Random(s)
+		if !reflect.DeepEqual(va, vb) {
+			t.Fatalf("iteration %d: same seed produced %v and %v", i, va.Value, vb.Value)
+		}
+		if !reflect.DeepEqual(va.Value, vc.Value) {
+			differs = true
+		}
+		if va.Valid != s.Accepts(va.Value) {
+			t.Errorf("iteration %d: Valid flag does not match the schema", i)
+		}
+	}
+	if !differs {
+		t.Error("different seeds produced the same values")
+	}
+}
+
+func TestShrinkValue(t *testing.T) {
+	v := map[string]interface{}{
+		"name":  strings.Repeat("x", 100),
+		"count": 1234.5,
+		"tags":  []interface{}{"a", "b", "c"},
+	}
+	// Fails whenever name is longer than 3 characters.
+	fails := func(v interface{}) bool {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		name, _ := obj["name"].(string)
+		return len(name) > 3
+	}
+	got := ShrinkValue(v, fails, 100)
+	obj := got.(map[string]interface{})
+	if len(obj) != 1 {
+		t.Errorf("expected other members to be removed, got %v", obj)
+	}
+	if name := obj["name"].(string); len(name) < 4 || len(name) > 7 {
+		t.Errorf("expected name shrunk close to 4 characters, got %d", len(name))
+	}
+	if len(v["name"].(string)) != 100 {
+		t.Error("ShrinkValue modified its input")
+	}
+
+	calls := 0
+	ShrinkValue(v, func(interface{}) bool { calls++; return false }, 5)
+	if calls != 5 {
+		t.Errorf("expected 5 attempts, got %d", calls)
+	}
+}
@@ -0,0 +1,429 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp/syntax"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// formatSamples are conforming values for the common string formats.
+var formatSamples = map[string]string{
+	"date-time":     "2025-01-01T00:00:00Z",
+	"date":          "2025-01-01",
+	"time":          "00:00:00Z",
+	"duration":      "P1D",
+	"email":         "user@example.com",
+	"idn-email":     "user@example.com",
+	"hostname":      "example.com",
+	"idn-hostname":  "example.com",
+	"ipv4":          "127.0.0.1",
+	"ipv6":          "::1",
+	"uri":           "https://example.com/",
+	"iri":           "https://example.com/",
+	"url":           "https://example.com/",
+	"uri-reference": "/sample",
+	"iri-reference": "/sample",
+	"uri-template":  "https://example.com/{id}",
+	"uuid":          "00000000-0000-4000-8000-000000000000",
+	"json-pointer":  "/sample",
+	"regex":         ".*",
+}
+
+// Sample returns a value conforming to the schema. Constants, enums, defaults
+// and examples are preferred; otherwise a value is built from the type and
+// constraints of the schema. Object samples only contain the required
+// properties unless more are needed to satisfy minProperties.
+func (s *Schema) Sample() (interface{}, error) {
+	v, ok := s.sample(0)
+	if !ok {
+		return nil, fmt.Errorf("no conforming value could be generated")
+	}
+	return v, nil
+}
+
+func (s *Schema) sample(depth int) (interface{}, bool) {
+	if depth > maxSchemaDepth {
+		return nil, false
+	}
+	for _, c := range s.candidates(depth) {
+		if len(s.validate(c, "$", 0)) == 0 {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// candidates lists values likely to conform to the schema, best first.
+func (s *Schema) candidates(depth int) []interface{} {
+	if s.always != nil {
+		return []interface{}{"sample"}
+	}
+	if s.HasConst {
+		return []interface{}{s.Const}
+	}
+	var out []interface{}
+	out = append(out, s.Enum...)
+	if s.Default != nil {
+		out = append(out, s.Default)
+	}
+	out = append(out, s.Examples...)
+	if s.ref != nil {
+		out = append(out, s.ref.candidates(depth+1)...)
+	}
+	for _, branches := range [][]*Schema{s.OneOf, s.AnyOf} {
+		for _, b := range branches {
+			if v, ok := b.sample(depth + 1); ok {
+				out = append(out, v)
+			}
+		}
+	}
+
+	types := s.Types
+	if len(types) == 0 {
+		types = s.impliedTypes()
+	}
+	for _, t := range types {
+		var typed []interface{}
+		switch t {
+		case "null":
+			typed = []interface{}{nil}
+		case "boolean":
+			typed = []interface{}{true, false}
+		case "integer":
+			typed = s.numberCandidates(true)
+		case "number":
+			typed = s.numberCandidates(false)
+		case "string":
+			typed = s.stringCandidates()
+		case "array":
+			typed = s.arrayCandidates(depth)
+		case "object":
+			typed = s.objectCandidates(depth)
+		}
+		out = append(out, typed...)
+	}
+	if len(s.AllOf) > 0 {
+		out = append(out, s.mergeAllOf(out, depth)...)
+	}
+	return out
+}
+
+// impliedTypes guesses the type of a schema without a type keyword from the
+// other keywords it uses.
+func (s *Schema) impliedTypes() []string {
+	switch {
+	case s.Properties != nil || s.Required != nil || s.AdditionalProperties != nil || s.MinProperties != nil:
+		return []string{"object"}
+	case s.Items != nil || s.PrefixItems != nil || s.MinItems != nil:
+		return []string{"array"}
+	case s.Minimum != nil || s.Maximum != nil || s.ExclusiveMinimum != nil || s.ExclusiveMaximum != nil || s.MultipleOf != nil:
+		return []string{"number"}
+	case s.Pattern != nil || s.MinLength != nil || s.MaxLength != nil || s.Format != "":
+		return []string{"string"}
+	case s.ref != nil || len(s.AllOf) > 0 || len(s.AnyOf) > 0 || len(s.OneOf) > 0:
+		return nil
+	default:
+		return []string{"string", "number", "boolean", "object", "array", "null"}
+	}
+}
+
+// mergeAllOf combines the object candidates with the samples of every allOf
+// branch, so that properties required by different branches are all present.
+func (s *Schema) mergeAllOf(candidates []interface{}, depth int) []interface{} {
+	var out []interface{}
+	merged := map[string]interface{}{}
+	isObject := true
+	for _, b := range s.AllOf {
+		v, ok := b.sample(depth + 1)
+		if !ok {
+			continue
+		}
+		out = append(out, v)
+		if m, ok := v.(map[string]interface{}); ok {
+			for k, item := range m {
+				merged[k] = item
+			}
+		} else {
+			isObject = false
+		}
+	}
+	if !isObject {
+		return out
+	}
+	for _, c := range candidates {
+		if m, ok := c.(map[string]interface{}); ok {
+			combined := map[string]interface{}{}
+			for k, item := range merged {
+				combined[k] = item
+			}
+			for k, item := range m {
+				combined[k] = item
+			}
+			out = append(out, combined)
+		}
+	}
+	return append(out, merged)
+}
+
+func (s *Schema) numberCandidates(integer bool) []interface{} {
+	values := []float64{0, 1}
+	var lower, upper *float64
+	if s.Minimum != nil {
+		values = append(values, *s.Minimum, math.Ceil(*s.Minimum), *s.Minimum+1)
+		lower = s.Minimum
+	}
+	if s.ExclusiveMinimum != nil {
+		values = append(values, math.Floor(*s.ExclusiveMinimum)+1, *s.ExclusiveMinimum+0.5)
+		lower = s.ExclusiveMinimum
+	}
+	if s.Maximum != nil {
+		values = append(values, *s.Maximum, math.Floor(*s.Maximum), *s.Maximum-1)
+		upper = s.Maximum
+	}
+	if s.ExclusiveMaximum != nil {
+		values = append(values, math.Ceil(*s.ExclusiveMaximum)-1, *s.ExclusiveMaximum-0.5)
+		upper = s.ExclusiveMaximum
+	}
+	if lower != nil && upper != nil {
+		values = append(values, (*lower+*upper)/2, math.Round((*lower+*upper)/2))
+	}
+	if m := s.MultipleOf; m != nil {
+		n := len(values)
+		for _, v := range values[:n] {
+			values = append(values, math.Ceil(v / *m)**m, math.Floor(v / *m)**m, (math.Floor(v / *m)+1)**m)
+		}
+		values = append(values, *m)
+	}
+	var out []interface{}
+	for _, v := range values {
+		if integer && v != math.Trunc(v) {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+func (s *Schema) stringCandidates() []interface{} {
+	var base []string
+	if v, ok := formatSamples[s.Format]; ok {
+		base = append(base, v)
+	}
+	if s.Pattern != nil {
+		minLength := 0
+		if s.MinLength != nil {
+			minLength = *s.MinLength
+		}
+		if re, err := syntax.Parse(s.Pattern.String(), syntax.Perl); err == nil {
+			base = append(base, regexSample(re.Simplify(), 0), regexSample(re.Simplify(), minLength))
+		}
+	}
+	base = append(base, "sample")
+	var out []interface{}
+	for _, v := range base {
+		out = append(out, s.fitLength(v))
+	}
+	return out
+}
+
+// fitLength pads or truncates a string to the length constraints.
+func (s *Schema) fitLength(v string) string {
+	if s.MinLength != nil {
+		if n := utf8.RuneCountInString(v); n < *s.MinLength {
+			v += strings.Repeat("a", *s.MinLength-n)
+		}
+	}
+	if s.MaxLength != nil && utf8.RuneCountInString(v) > *s.MaxLength {
+		v = string([]rune(v)[:*s.MaxLength])
+	}
+	return v
+}
+
+// regexSample builds a string matching a parsed regular expression, repeating
+// every unbounded repetition n times, or once for +, when n is zero.
+func regexSample(re *syntax.Regexp, n int) string {
+	var b strings.Builder
+	var walk func(re *syntax.Regexp)
+	repeat := func(sub *syntax.Regexp, times int) {
+		for i := 0; i < times; i++ {
+			walk(sub)
+		}
+	}
+	walk = func(re *syntax.Regexp) {
+		switch re.Op {
+		case syntax.OpLiteral:
+			b.WriteString(string(re.Rune))
+		case syntax.OpCharClass:
+			if len(re.Rune) > 0 {
+				r := re.Rune[0]
+				// Prefer a printable letter or digit when the class allows it.
+				for i := 0; i+1 < len(re.Rune); i += 2 {
+					if re.Rune[i] <= 'a' && 'a' <= re.Rune[i+1] {
+						r = 'a'
+						break
+					}
+					if re.Rune[i] <= '0' && '0' <= re.Rune[i+1] {
+						r = '0'
+					}
+				}
+				b.WriteRune(r)
+			}
+		case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+			b.WriteRune('a')
+		case syntax.OpCapture:
+			walk(re.Sub[0])
+		case syntax.OpConcat:
+			for _, sub := range re.Sub {
+				walk(sub)
+			}
+		case syntax.OpAlternate:
+			walk(re.Sub[0])
+		case syntax.OpStar, syntax.OpQuest:
+			if re.Op == syntax.OpStar {
+				repeat(re.Sub[0], n)
+			}
+		case syntax.OpPlus:
+			repeat(re.Sub[0], max(n, 1))
+		case syntax.OpRepeat:
+			times := re.Min
+			if re.Max < 0 || re.Max > times {
+				times = max(times, min(n, maxOrUnbounded(re.Max)))
+			}
+			repeat(re.Sub[0], times)
+		}
+	}
+	walk(re)
+	return b.String()
+}
+
+func maxOrUnbounded(m int) int {
+	if m < 0 {
+		return math.MaxInt32
+	}
+	return m
+}
+
+func (s *Schema) arrayCandidates(depth int) []interface{} {
+	n := len(s.PrefixItems)
+	if s.MinItems != nil && *s.MinItems > n {
+		n = *s.MinItems
+	}
+	items := make([]interface{}, 0, n)
+	for i := 0; i < n; i++ {
+		var item interface{} = "sample"
+		switch {
+		case i < len(s.PrefixItems):
+			v, ok := s.PrefixItems[i].sample(depth + 1)
+			if !ok {
+				return nil
+			}
+			item = v
+		case s.Items != nil:
+			v, ok := s.Items.sampleDistinct(items, depth+1)
+			if !ok {
+				return nil
+			}
+			item = v
+		default:
+			item = float64(i)
+		}
+		items = append(items, item)
+	}
+	return []interface{}{items}
+}
+
+// sampleDistinct prefers a conforming value not already in taken, so that
+// samples of arrays with uniqueItems are valid.
+func (s *Schema) sampleDistinct(taken []interface{}, depth int) (interface{}, bool) {
+	var fallback interface{}
+	found := false
+	for _, c := range s.candidates(depth) {
+		if len(s.validate(c, "$", 0)) != 0 {
+			continue
+		}
+		if !containsValue(taken, c) {
+			return c, true
+		}
+		if !found {
+			fallback, found = c, true
+		}
+	}
+	return fallback, found
+}
+
+func (s *Schema) objectCandidates(depth int) []interface{} {
+	required := map[string]interface{}{}
+	for _, name := range s.Required {
+		prop := s.propertySchema(name)
+		if prop == nil {
+			required[name] = "sample"
+			continue
+		}
+		v, ok := prop.sample(depth + 1)
+		if !ok {
+			return nil
+		}
+		required[name] = v
+	}
+
+	names := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	minProperties := 0
+	if s.MinProperties != nil {
+		minProperties = *s.MinProperties
+	}
+	// Optional properties are added one at a time: the first object reaching
+	// minProperties and the object with every property are both candidates.
+	out := []interface{}{required}
+	full := copyObject(required)
+	for _, name := range names {
+		if _, ok := full[name]; ok {
+			continue
+		}
+		v, ok := s.Properties[name].sample(depth + 1)
+		if !ok {
+			continue
+		}
+		full[name] = v
+		if len(full) == minProperties {
+			out = append(out, copyObject(full))
+		}
+	}
+	if s.AdditionalProperties != nil {
+		for i := 0; len(full) < minProperties; i++ {
+			v, ok := s.AdditionalProperties.sample(depth + 1)
+			if !ok {
+				break
+			}
+			full[fmt.Sprintf("property%d", i)] = v
+		}
+	}
+	return append(out, full)
+}
+
+func copyObject(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// propertySchema returns the schema applying to a property name.
+func (s *Schema) propertySchema(name string) *Schema {
+	if prop, ok := s.Properties[name]; ok {
+		return prop
+	}
+	for re, prop := range s.PatternProperties {
+		if re.MatchString(name) {
+			return prop
+		}
+	}
+	return s.AdditionalProperties
+}
@@ -0,0 +1,341 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// projectSpecSchema is the JSON Schema of a project spec file. It mirrors
+// ProjectConfig, minus the fields recorded at generation time.
+const projectSpecSchema = `{
+  "type": "object",
+  "additionalProperties": false,
+  "properties": {
+    "name": {"type": "string", "minLength": 1},
+    "language": {"enum": ["golang", "javascript", "java", "python"]},
+    "transport": {"enum": ["stdio", "rest", "streamable-http", "websocket"]},
+    "docker": {"type": "boolean"},
+    "examples": {"type": "boolean"},
+    "output": {"type": "string"},
+    "author": {"type": "string"},
+    "description": {"type": "string"},
+    "tools": {"type": "array", "items": {"$ref": "#/$defs/tool"}},
+    "resources": {"type": "array", "items": {"$ref": "#/$defs/resource"}},
+    "prompts": {"type": "array", "items": {"$ref": "#/$defs/prompt"}},
+    "capabilities": {"type": "array", "items": {"$ref": "#/$defs/capability"}}
+  },
+  "$defs": {
+    "identifier": {"type": "string", "pattern": "^[A-Za-z_][A-Za-z0-9_]*$"},
+    "tool": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name"],
+      "properties": {
+        "name": {"$ref": "#/$defs/identifier"},
+        "description": {"type": "string"},
+        "parameters": {"type": "array", "items": {"$ref": "#/$defs/parameter"}},
+        "outputSchema": {"type": "object"},
+        "updates": {"type": "array", "items": {"$ref": "#/$defs/identifier"}}
+      }
+    },
+    "parameter": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name", "type"],
+      "properties": {
+        "name": {"type": "string", "minLength": 1},
+        "type": {"enum": ["string", "number", "integer", "boolean", "array", "object"]},
+        "description": {"type": "string"},
+        "required": {"type": "boolean"},
+        "enum": {"type": "array", "minItems": 1},
+        "default": {}
+      }
+    },
+    "resource": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name", "type"],
+      "properties": {
+        "name": {"$ref": "#/$defs/identifier"},
+        "type": {"enum": ["database", "filesystem", "time"]},
+        "uriTemplate": {"type": "string"}
+      }
+    },
+    "prompt": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name"],
+      "properties": {
+        "name": {"$ref": "#/$defs/identifier"},
+        "description": {"type": "string"},
+        "arguments": {
+          "type": "array",
+          "items": {
+            "type": "object",
+            "additionalProperties": false,
+            "required": ["name"],
+            "properties": {
+              "name": {"type": "string", "minLength": 1},
+              "description": {"type": "string"},
+              "required": {"type": "boolean"}
+            }
+          }
+        }
+      }
+    },
+    "capability": {
+      "type": "object",
+      "additionalProperties": false,
+      "required": ["name"],
+      "properties": {
+        "name": {"$ref": "#/$defs/identifier"},
+        "enabled": {"type": "boolean"}
+      }
+    }
+  }
+}`
+
+// LoadProjectSpec reads a YAML or JSON project spec file.
+func LoadProjectSpec(path string) (*ProjectConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spec file: %w", err)
+	}
+	return ParseProjectSpec(data, path)
+}
+
+// ParseProjectSpec decodes and validates a project spec. Files named *.json,
+// or starting with '{', are parsed as JSON and anything else as YAML. Every
+// problem found is reported with the line and column of the offending value
+// in source.
+func ParseProjectSpec(data []byte, source string) (*ProjectConfig, error) {
+	doc, positions, err := decodeSpec(data, source)
+	if err != nil {
+		return nil, err
+	}
+	if doc == nil {
+		doc = map[string]interface{}{}
+	}
+	schema, err := ParseSchema([]byte(projectSpecSchema))
+	if err != nil {
+		return nil, err
+	}
+	var problems []specProblem
+	for _, e := range schema.Validate(doc) {
+		problems = append(problems, newSpecProblem(e.Error(), positions))
+	}
+	if len(problems) > 0 {
+		return nil, specErrors(source, problems)
+	}
+
+	encoded, err := json.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode spec: %w", err)
+	}
+	config := &ProjectConfig{}
+	if err := json.Unmarshal(encoded, config); err != nil {
+		return nil, fmt.Errorf("failed to decode spec: %w", err)
+	}
+	if problems := checkSpec(config, positions); len(problems) > 0 {
+		return nil, specErrors(source, problems)
+	}
+	return config, nil
+}
+
+// decodeSpec decodes a spec document along with the positions of its values.
+func decodeSpec(data []byte, source string) (interface{}, map[string]textPosition, error) {
+	ext := strings.ToLower(filepath.Ext(source))
+	if ext == ".json" || (ext != ".yaml" && ext != ".yml" && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))) {
+		var doc interface{}
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return nil, nil, FormatJSONError(data, err, source)
+		}
+		return doc, jsonPositions(data), nil
+	}
+	doc, positions, err := decodeYAML(data)
+	var yerr *YAMLError
+	if errors.As(err, &yerr) {
+		return nil, nil, fmt.Errorf("%s at line %d, column %d: %s", source, yerr.Line, yerr.Column, yerr.Message)
+	}
+	return doc, positions, err
+}
+
+// jsonPositions returns the position of every value of a valid JSON document
+// keyed by its JSONPath.
+func jsonPositions(data []byte) map[string]textPosition {
+	positions := map[string]textPosition{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var walk func(path string) error
+	walk = func(path string) error {
+		start := dec.InputOffset()
+		for start < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[start]) >= 0 {
+			start++
+		}
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		if line, column, err := lineAndColumn(data, start); err == nil {
+			positions[path] = textPosition{line, column}
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(memberPath(path, fmt.Sprint(key))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(fmt.Sprintf("%s[%d]", path, i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	walk("$")
+	return positions
+}
+
+// specProblem is a spec validation error located in the source document.
+type specProblem struct {
+	pos     textPosition
+	message string
+}
+
+// newSpecProblem locates an error whose message starts with a JSONPath at the
+// closest value of the document that has a known position.
+func newSpecProblem(message string, positions map[string]textPosition) specProblem {
+	path, detail := splitErrorPath(message)
+	target := path
+	var name string
+	if _, err := fmt.Sscanf(detail, "unexpected property %q", &name); err == nil {
+		target = memberPath(path, name)
+	}
+	for target != "" {
+		if pos, ok := positions[target]; ok {
+			return specProblem{pos, message}
+		}
+		target = parentPath(target)
+	}
+	return specProblem{textPosition{1, 1}, message}
+}
+
+// splitErrorPath splits a "$.path: message" error into its path and message.
+func splitErrorPath(message string) (string, string) {
+	inQuote := false
+	for i := 0; i < len(message); i++ {
+		switch c := message[i]; {
+		case c == '\\' && inQuote:
+			i++
+		case c == '"':
+			inQuote = !inQuote
+		case c == ':' && !inQuote && strings.HasPrefix(message[i:], ": "):
+			return message[:i], message[i+2:]
+		}
+	}
+	return "", message
+}
+
+// parentPath strips the last member or index from a JSONPath.
+func parentPath(path string) string {
+	if strings.HasSuffix(path, "]") {
+		inQuote := false
+		for i := len(path) - 2; i >= 0; i-- {
+			switch path[i] {
+			case '"':
+				if i == 0 || path[i-1] != '\\' {
+					inQuote = !inQuote
+				}
+			case '[':
+				if !inQuote {
+					return path[:i]
+				}
+			}
+		}
+		return ""
+	}
+	if i := strings.LastIndexByte(path, '.'); i > 0 {
+		return path[:i]
+	}
+	return ""
+}
+
+// checkSpec checks what the schema cannot express: unique names, consistent
+// tool parameters, tools updating declared resources and valid URI templates.
+func checkSpec(config *ProjectConfig, positions map[string]textPosition) []specProblem {
+	var problems []specProblem
+	fail := func(path, format string, args ...interface{}) {
+		problems = append(problems, newSpecProblem(fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, args...)), positions))
+	}
+	tools := map[string]bool{}
+	for i, tool := range config.Tools {
+		path := fmt.Sprintf("$.tools[%d]", i)
+		if tools[tool.Name] {
+			fail(path+".name", "duplicate tool name %q", tool.Name)
+		}
+		tools[tool.Name] = true
+		failed := false
+		for j, p := range tool.Parameters {
+			if err := p.Validate(); err != nil {
+				fail(fmt.Sprintf("%s.parameters[%d]", path, j), "%v", err)
+				failed = true
+			}
+		}
+		if !failed {
+			if err := tool.Validate(); err != nil {
+				fail(path, "%v", err)
+			}
+		}
+		if err := tool.ValidateUpdates(config.Resources); err != nil {
+			fail(path+".updates", "%v", err)
+		}
+	}
+	resources := map[string]bool{}
+	for i, r := range config.Resources {
+		path := fmt.Sprintf("$.resources[%d]", i)
+		if resources[r.Name] {
+			fail(path+".name", "duplicate resource name %q", r.Name)
+		}
+		resources[r.Name] = true
+		if r.URITemplate != "" {
+			if _, err := ParseURITemplate(r.URITemplate); err != nil {
+				fail(path+".uriTemplate", "%v", err)
+			}
+		}
+	}
+	prompts := map[string]bool{}
+	for i, p := range config.Prompts {
+		if prompts[p.Name] {
+			fail(fmt.Sprintf("$.prompts[%d].name", i), "duplicate prompt name %q", p.Name)
+		}
+		prompts[p.Name] = true
+	}
+	return problems
+}
+
+// specErrors joins spec problems, in document order, into one error.
+func specErrors(source string, problems []specProblem) error {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i].pos, problems[j].pos
+		return a.line < b.line || (a.line == b.line && a.column < b.column)
+	})
+	errs := make([]error, len(problems))
+	for i, p := range problems {
+		errs[i] = fmt.Errorf("%s at line %d, column %d: %s", source, p.pos.line, p.pos.column, p.message)
+	}
+	return errors.Join(errs...)
+}
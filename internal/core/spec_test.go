@@ -0,0 +1,203 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const yamlSpec = `# Weather server
+name: weather
+language: golang
+transport: stdio
+docker: true
+author: Jane Doe
+description: Forecasts as a service
+tools:
+  - name: forecast
+    description: Get the forecast for a city
+    parameters:
+      - name: city
+        type: string
+        required: true
+      - name: days
+        type: integer
+        default: 3
+        enum: [1, 3, 7]
+    outputSchema:
+      type: object
+      properties:
+        summary: {type: string}
+resources:
+  - name: stations
+    type: filesystem
+    uriTemplate: file:///stations/{id}
+prompts:
+  - name: summarize
+    arguments:
+      - name: city
+        required: true
+capabilities:
+  - name: logging
+    enabled: true
+`
+
+func TestParseProjectSpec_YAML(t *testing.T) {
+	config, err := ParseProjectSpec([]byte(yamlSpec), "spec.yaml")
+	if err != nil {
+		t.Fatalf("ParseProjectSpec: %v", err)
+	}
+	if config.Name != "weather" || config.Language != "golang" || config.Transport != "stdio" || !config.Docker {
+		t.Errorf("unexpected basic options: %+v", config)
+	}
+	if config.Author != "Jane Doe" || config.Description != "Forecasts as a service" {
+		t.Errorf("unexpected metadata: %q, %q", config.Author, config.Description)
+	}
+	if len(config.Tools) != 1 || len(config.Tools[0].Parameters) != 2 {
+		t.Fatalf("unexpected tools: %+v", config.Tools)
+	}
+	days := config.Tools[0].Parameters[1]
+	if days.Type != "integer" || days.Default != float64(3) || len(days.Enum) != 3 {
+		t.Errorf("unexpected parameter: %+v", days)
+	}
+	if got := config.Tools[0].OutputSchemaJSON(); got != `{"properties":{"summary":{"type":"string"}},"type":"object"}` {
+		t.Errorf("unexpected output schema: %s", got)
+	}
+	if len(config.Resources) != 1 || config.Resources[0].URITemplate != "file:///stations/{id}" {
+		t.Errorf("unexpected resources: %+v", config.Resources)
+	}
+	if len(config.Prompts) != 1 || !config.Prompts[0].Arguments[0].Required {
+		t.Errorf("unexpected prompts: %+v", config.Prompts)
+	}
+	if len(config.Capabilities) != 1 || !config.Capabilities[0].Enabled {
+		t.Errorf("unexpected capabilities: %+v", config.Capabilities)
+	}
+}
+
+func TestParseProjectSpec_JSON(t *testing.T) {
+	data := `{
+  "name": "weather",
+  "language": "python",
+  "tools": [{"name": "forecast", "parameters": [{"name": "city", "type": "string"}]}]
+}`
+	config, err := ParseProjectSpec([]byte(data), "spec.json")
+	if err != nil {
+		t.Fatalf("ParseProjectSpec: %v", err)
+	}
+	if config.Language != "python" || len(config.Tools) != 1 || config.Tools[0].Parameters[0].Name != "city" {
+		t.Errorf("unexpected config: %+v", config)
+	}
+}
+
+func TestParseProjectSpec_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		source string
+		data   string
+		want   []string
+	}{
+		{
+			"yaml syntax",
+			"spec.yaml",
+			"name: a\n  language: golang\n",
+			[]string{"spec.yaml at line 2, column 3: unexpected indentation"},
+		},
+		{
+			"json syntax",
+			"spec.json",
+			"{\n  \"name\": \"a\",\n}",
+			[]string{"spec.json at line 3, column 2: invalid character '}'"},
+		},
+		{
+			"schema",
+			"spec.yaml",
+			"name: a\nlanguage: rust\ntools:\n  - name: my tool\n    colour: red\n",
+			[]string{
+				`spec.yaml at line 2, column 11: $.language: "rust" is not one of`,
+				`spec.yaml at line 4, column 11: $.tools[0].name: "my tool" does not match`,
+				`spec.yaml at line 5, column 13: $.tools[0]: unexpected property "colour"`,
+			},
+		},
+		{
+			"identifiers",
+			"spec.yaml",
+			"resources:\n  - {name: my-notes, type: filesystem}\nprompts:\n  - name: code review\ncapabilities:\n  - name: 1logging\n",
+			[]string{
+				`spec.yaml at line 2, column 12: $.resources[0].name: "my-notes" does not match`,
+				`spec.yaml at line 4, column 11: $.prompts[0].name: "code review" does not match`,
+				`spec.yaml at line 6, column 11: $.capabilities[0].name: "1logging" does not match`,
+			},
+		},
+		{
+			"json schema",
+			"spec.json",
+			"{\"name\": \"a\",\n \"docker\": \"yes\"}",
+			[]string{"spec.json at line 2, column 12: $.docker: expected boolean, got string"},
+		},
+		{
+			"semantics",
+			"spec.yaml",
+			`tools:
+  - name: a
+    parameters:
+      - name: n
+        type: integer
+        default: 1.5
+  - name: a
+    outputSchema: {type: array}
+resources:
+  - name: r
+    type: time
+    uriTemplate: "file:///{bad"
+`,
+			[]string{
+				"spec.yaml at line 4, column 9: $.tools[0].parameters[0]: default 1.5 is not of type integer",
+				`spec.yaml at line 7, column 5: $.tools[1]: outputSchema must have type "object"`,
+				`spec.yaml at line 7, column 11: $.tools[1].name: duplicate tool name "a"`,
+				"spec.yaml at line 12, column 18: $.resources[0].uriTemplate:",
+			},
+		},
+		{
+			"updates",
+			"spec.yaml",
+			"tools:\n  - name: save\n    updates: [notes, files, missing]\nresources:\n  - {name: notes, type: filesystem}\n  - {name: files, type: filesystem, uriTemplate: \"file:///{path}\"}\n",
+			[]string{
+				"spec.yaml at line 3, column 14: $.tools[0].updates: updates resource template files",
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseProjectSpec([]byte(tc.data), tc.source)
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			lines := strings.Split(err.Error(), "\n")
+			if len(lines) != len(tc.want) {
+				t.Fatalf("got %d errors, want %d:\n%v", len(lines), len(tc.want), err)
+			}
+			for i, want := range tc.want {
+				if !strings.HasPrefix(lines[i], want) {
+					t.Errorf("error %d = %q, want prefix %q", i, lines[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadProjectSpec(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "spec.yml")
+	if err := os.WriteFile(path, []byte(yamlSpec), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err := LoadProjectSpec(path)
+	if err != nil {
+		t.Fatalf("LoadProjectSpec: %v", err)
+	}
+	if config.Name != "weather" {
+		t.Errorf("unexpected name %q", config.Name)
+	}
+	if _, err := LoadProjectSpec(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
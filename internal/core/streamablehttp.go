@@ -0,0 +1,549 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// HTTP headers defined by the Streamable HTTP transport.
+const (
+	SessionIDHeader       = "Mcp-Session-Id"
+	ProtocolVersionHeader = "MCP-Protocol-Version"
+	LastEventIDHeader     = "Last-Event-ID"
+)
+
+// ErrSessionExpired is returned when the server no longer knows the session.
+// The client must initialize a new session.
+var ErrSessionExpired = errors.New("mcp session expired")
+
+// ErrNoEventStream is returned by OpenStream when the server does not offer a
+// standalone event stream.
+var ErrNoEventStream = errors.New("server does not offer an event stream")
+
+const (
+	// defaultReconnectDelay is used between stream reconnection attempts
+	// unless the server sends a retry hint.
+	defaultReconnectDelay = 500 * time.Millisecond
+	// maxStreamReconnects bounds resumption attempts for a single stream.
+	maxStreamReconnects = 5
+)
+
+// StreamableHTTPTransport implements the MCP Streamable HTTP transport. Each
+// message is POSTed to a single endpoint and the server answers with either a
+// JSON body or an SSE stream. The session ID assigned during initialization is
+// echoed on every request, interrupted streams are resumed with Last-Event-ID
+// and servers that only speak the older HTTP+SSE transport are detected and
+// handled transparently.
+type StreamableHTTPTransport struct {
+	endpoint string
+	client   *http.Client
+
+	// ReconnectDelay is the delay before resuming an interrupted stream when
+	// the server did not send a retry hint.
+	ReconnectDelay time.Duration
+
+	mu              sync.Mutex
+	sessionID       string
+	protocolVersion string
+	initKey         string
+	legacyPostURL   string
+
+	incoming      chan []byte
+	closed        chan struct{}
+	closeOnce     sync.Once
+	streamCtx     context.Context
+	cancelStreams context.CancelFunc
+	streams       sync.WaitGroup
+}
+
+// NewStreamableHTTPTransport creates a transport for the MCP endpoint URL. A
+// nil client defaults to http.DefaultClient.
+func NewStreamableHTTPTransport(endpoint string, client *http.Client) *StreamableHTTPTransport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &StreamableHTTPTransport{
+		endpoint:       endpoint,
+		client:         client,
+		ReconnectDelay: defaultReconnectDelay,
+		incoming:       make(chan []byte, 64),
+		closed:         make(chan struct{}),
+		streamCtx:      ctx,
+		cancelStreams:  cancel,
+	}
+}
+
+// SessionID returns the session assigned by the server, if any.
+func (t *StreamableHTTPTransport) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// messageProbe extracts the routing members of a JSON-RPC message.
+type messageProbe struct {
+	Method string          `json:"method"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// requestKey returns the ID key of a request expecting a response, or "".
+func (p messageProbe) requestKey() string {
+	if p.Method == "" || len(p.ID) == 0 || string(p.ID) == "null" {
+		return ""
+	}
+	var id interface{}
+	if err := json.Unmarshal(p.ID, &id); err != nil {
+		return ""
+	}
+	return IDKey(id)
+}
+
+// Send POSTs the message. JSON replies are queued directly while SSE replies
+// are consumed in the background until the response to the request arrives.
+func (t *StreamableHTTPTransport) Send(ctx context.Context, message []byte) error {
+	var probe messageProbe
+	json.Unmarshal(message, &probe)
+	key := probe.requestKey()
+
+	t.mu.Lock()
+	legacy := t.legacyPostURL != ""
+	firstMessage := t.sessionID == "" && t.initKey == ""
+	if probe.Method == "initialize" {
+		t.initKey = key
+	}
+	t.mu.Unlock()
+	if legacy {
+		return t.sendLegacy(ctx, message)
+	}
+
+	resp, detach, err := t.do(ctx, http.MethodPost, message, "")
+	if err != nil {
+		return err
+	}
+	t.captureSession(resp)
+
+	switch {
+	case resp.StatusCode == http.StatusAccepted:
+		detach()
+		resp.Body.Close()
+		return nil
+	case resp.StatusCode == http.StatusNotFound && t.SessionID() != "":
+		detach()
+		resp.Body.Close()
+		return ErrSessionExpired
+	case firstMessage && probe.Method == "initialize" &&
+		(resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusMethodNotAllowed):
+		// Servers implementing the 2024-11-05 HTTP+SSE transport reject the
+		// POST; fall back to the endpoint discovery of that transport.
+		detach()
+		resp.Body.Close()
+		if err := t.startLegacy(ctx); err != nil {
+			return err
+		}
+		return t.sendLegacy(ctx, message)
+	}
+
+	if isEventStream(resp) && resp.StatusCode < 300 {
+		if !detach() {
+			resp.Body.Close()
+			return ctx.Err()
+		}
+		t.streams.Add(1)
+		go t.consumeStream(resp.Body, key, probe.ID)
+		return nil
+	}
+
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	detach()
+	if err != nil {
+		return fmt.Errorf("failed to read http response: %w", err)
+	}
+	body = bytes.TrimSpace(body)
+	if resp.StatusCode >= 300 && !looksLikeJSON(body) {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	if len(body) == 0 {
+		return nil
+	}
+	return t.deliver(body)
+}
+
+// OpenStream opens the standalone GET event stream over which the server may
+// send requests and notifications unrelated to any client request. It returns
+// ErrNoEventStream when the server does not support it.
+func (t *StreamableHTTPTransport) OpenStream(ctx context.Context) error {
+	resp, err := t.openEventStream(ctx, "")
+	if err != nil {
+		return err
+	}
+	t.streams.Add(1)
+	go t.consumeStream(resp.Body, "", nil)
+	return nil
+}
+
+// openEventStream issues a GET for an event stream, resuming after
+// lastEventID when it is set.
+func (t *StreamableHTTPTransport) openEventStream(ctx context.Context, lastEventID string) (*http.Response, error) {
+	resp, detach, err := t.do(ctx, http.MethodGet, nil, lastEventID)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusMethodNotAllowed {
+		detach()
+		resp.Body.Close()
+		return nil, ErrNoEventStream
+	}
+	if resp.StatusCode == http.StatusNotFound && t.SessionID() != "" {
+		detach()
+		resp.Body.Close()
+		return nil, ErrSessionExpired
+	}
+	if resp.StatusCode >= 300 || !isEventStream(resp) {
+		detach()
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to open event stream: server returned %s", resp.Status)
+	}
+	if !detach() {
+		resp.Body.Close()
+		return nil, ctx.Err()
+	}
+	return resp, nil
+}
+
+// do sends an HTTP request whose lifetime is bound to ctx until the returned
+// detach function is called, after which only Close interrupts it. detach
+// reports false when ctx was already done. The response body must be closed.
+func (t *StreamableHTTPTransport) do(ctx context.Context, method string, body []byte, lastEventID string) (*http.Response, func() bool, error) {
+	reqCtx, cancel := context.WithCancel(t.streamCtx)
+	stop := context.AfterFunc(ctx, cancel)
+	req, err := http.NewRequestWithContext(reqCtx, method, t.endpoint, bytes.NewReader(body))
+	if err != nil {
+		stop()
+		cancel()
+		return nil, nil, fmt.Errorf("failed to create http request: %w", err)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+	} else {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	if lastEventID != "" {
+		req.Header.Set(LastEventIDHeader, lastEventID)
+	}
+	t.setSessionHeaders(req)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		stop()
+		cancel()
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, fmt.Errorf("http request failed: %w", err)
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, stop, nil
+}
+
+// cancelOnClose releases the request context once the body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+func (t *StreamableHTTPTransport) setSessionHeaders(req *http.Request) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		req.Header.Set(SessionIDHeader, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		req.Header.Set(ProtocolVersionHeader, t.protocolVersion)
+	}
+}
+
+func (t *StreamableHTTPTransport) captureSession(resp *http.Response) {
+	if id := resp.Header.Get(SessionIDHeader); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+}
+
+func isEventStream(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	return mediaType == "text/event-stream"
+}
+
+// consumeStream delivers the messages of an SSE stream. When the stream ends
+// before the response to the request with ID key arrives it is resumed with
+// Last-Event-ID; if that is impossible an error response is delivered in its
+// place so the pending call does not wait forever.
+func (t *StreamableHTTPTransport) consumeStream(body io.ReadCloser, key string, rawID json.RawMessage) {
+	defer t.streams.Done()
+	lastEventID := ""
+	delay := t.ReconnectDelay
+	for attempt := 0; ; attempt++ {
+		answered, err := t.readEvents(body, key, &lastEventID, &delay)
+		body.Close()
+		if t.streamCtx.Err() != nil {
+			return
+		}
+		if answered || (key == "" && err == nil) {
+			return
+		}
+		if attempt >= maxStreamReconnects || (key != "" && lastEventID == "") {
+			if key != "" {
+				t.failRequest(rawID, "event stream closed before the response was received")
+			}
+			return
+		}
+		select {
+		case <-time.After(delay):
+		case <-t.streamCtx.Done():
+			return
+		}
+		resp, err := t.openEventStream(t.streamCtx, lastEventID)
+		if err != nil {
+			if key != "" {
+				t.failRequest(rawID, fmt.Sprintf("failed to resume event stream: %v", err))
+			}
+			return
+		}
+		body = resp.Body
+	}
+}
+
+// readEvents delivers events until the stream ends and reports whether the
+// response to the request with ID key was among them.
+func (t *StreamableHTTPTransport) readEvents(body io.Reader, key string, lastEventID *string, delay *time.Duration) (bool, error) {
+	reader := NewSSEReader(body)
+	answered := false
+	for {
+		ev, err := reader.Next()
+		if err == io.EOF {
+			return answered, nil
+		}
+		if err != nil {
+			return answered, err
+		}
+		if ev.ID != "" {
+			*lastEventID = ev.ID
+		}
+		if ev.Retry > 0 {
+			*delay = ev.Retry
+		}
+		if ev.Data == "" || (ev.Event != "" && ev.Event != "message") {
+			continue
+		}
+		data := []byte(ev.Data)
+		if key != "" && containsResponse(data, key) {
+			answered = true
+		}
+		if err := t.deliver(data); err != nil {
+			return answered, err
+		}
+	}
+}
+
+// failRequest delivers a JSON-RPC error response for a request whose stream
+// was lost.
+func (t *StreamableHTTPTransport) failRequest(rawID json.RawMessage, message string) {
+	var id interface{}
+	json.Unmarshal(rawID, &id)
+	data, err := json.Marshal(NewErrorResponse(id, InternalError, message, nil))
+	if err == nil {
+		t.deliver(data)
+	}
+}
+
+// containsResponse reports whether a message, or any member of a batch, is a
+// response to the request with the given ID key.
+func containsResponse(data []byte, key string) bool {
+	var probes []messageProbe
+	if len(data) > 0 && data[0] == '[' {
+		json.Unmarshal(data, &probes)
+	} else {
+		var p messageProbe
+		if json.Unmarshal(data, &p) == nil {
+			probes = append(probes, p)
+		}
+	}
+	for _, p := range probes {
+		if p.Method != "" || len(p.ID) == 0 {
+			continue
+		}
+		var id interface{}
+		if json.Unmarshal(p.ID, &id) == nil && IDKey(id) == key {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver queues a message for Receive, remembering the protocol version
+// negotiated by the initialize response.
+func (t *StreamableHTTPTransport) deliver(message []byte) error {
+	t.mu.Lock()
+	initKey := t.initKey
+	t.mu.Unlock()
+	if initKey != "" && containsResponse(message, initKey) {
+		var resp struct {
+			Result struct {
+				ProtocolVersion string `json:"protocolVersion"`
+			} `json:"result"`
+		}
+		if json.Unmarshal(message, &resp) == nil && resp.Result.ProtocolVersion != "" {
+			t.mu.Lock()
+			t.protocolVersion = resp.Result.ProtocolVersion
+			t.initKey = ""
+			t.mu.Unlock()
+		}
+	}
+	select {
+	case t.incoming <- message:
+		return nil
+	case <-t.closed:
+		return io.ErrClosedPipe
+	}
+}
+
+// startLegacy opens the event stream of the HTTP+SSE transport and waits for
+// the endpoint event announcing where messages must be POSTed.
+func (t *StreamableHTTPTransport) startLegacy(ctx context.Context) error {
+	resp, detach, err := t.do(ctx, http.MethodGet, nil, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 300 || !isEventStream(resp) {
+		detach()
+		resp.Body.Close()
+		return fmt.Errorf("server supports neither streamable HTTP nor HTTP+SSE: GET returned %s", resp.Status)
+	}
+	reader := NewSSEReader(resp.Body)
+	var postURL string
+	for postURL == "" {
+		ev, err := reader.Next()
+		if err != nil {
+			detach()
+			resp.Body.Close()
+			return fmt.Errorf("failed to read endpoint event: %w", err)
+		}
+		if ev.Event == "endpoint" {
+			postURL, err = resolveEndpoint(t.endpoint, ev.Data)
+			if err != nil {
+				detach()
+				resp.Body.Close()
+				return err
+			}
+		}
+	}
+	if !detach() {
+		resp.Body.Close()
+		return ctx.Err()
+	}
+	t.mu.Lock()
+	t.legacyPostURL = postURL
+	t.mu.Unlock()
+	t.streams.Add(1)
+	go func() {
+		defer t.streams.Done()
+		defer resp.Body.Close()
+		for {
+			ev, err := reader.Next()
+			if err != nil {
+				return
+			}
+			if ev.Data != "" && (ev.Event == "" || ev.Event == "message") {
+				if t.deliver([]byte(ev.Data)) != nil {
+					return
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+func resolveEndpoint(base, ref string) (string, error) {
+	b, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("invalid transport url: %w", err)
+	}
+	r, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("invalid endpoint event %q: %w", ref, err)
+	}
+	return b.ResolveReference(r).String(), nil
+}
+
+// sendLegacy POSTs a message to the HTTP+SSE message endpoint. Replies arrive
+// over the event stream.
+func (t *StreamableHTTPTransport) sendLegacy(ctx context.Context, message []byte) error {
+	t.mu.Lock()
+	postURL := t.legacyPostURL
+	t.mu.Unlock()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, postURL, bytes.NewReader(message))
+	if err != nil {
+		return fmt.Errorf("failed to create http request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("http request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("server returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Receive returns the next message received from any stream or response.
+func (t *StreamableHTTPTransport) Receive() ([]byte, error) {
+	select {
+	case msg := <-t.incoming:
+		return msg, nil
+	case <-t.closed:
+		return nil, io.EOF
+	}
+}
+
+// Close terminates the session with a DELETE request, as recommended by the
+// specification, and stops all open streams.
+func (t *StreamableHTTPTransport) Close() error {
+	t.closeOnce.Do(func() {
+		if t.SessionID() != "" {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			req, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.endpoint, nil)
+			if err == nil {
+				t.setSessionHeaders(req)
+				if resp, err := t.client.Do(req); err == nil {
+					resp.Body.Close()
+				}
+			}
+			cancel()
+		}
+		t.cancelStreams()
+		close(t.closed)
+		t.streams.Wait()
+	})
+	return nil
+}
@@ -0,0 +1,253 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSSEReader(t *testing.T) {
+	stream := ": comment\nid: 1\nevent: message\ndata: {\"a\":\ndata: 1}\n\nretry: 250\n\ndata: last\n\n"
+	r := NewSSEReader(strings.NewReader(stream))
+	ev, err := r.Next()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ev.ID != "1" || ev.Event != "message" || ev.Data != "{\"a\":\n1}" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+	ev, err = r.Next()
+	if err != nil || ev.Retry != 250*time.Millisecond || ev.Data != "" {
+		t.Errorf("unexpected retry event %+v %v", ev, err)
+	}
+	ev, err = r.Next()
+	if err != nil || ev.Data != "last" {
+		t.Errorf("unexpected event %+v %v", ev, err)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestWriteSSEEvent(t *testing.T) {
+	var buf bytes.Buffer
+	WriteSSEEvent(&buf, "7", "", "a\nb")
+	if buf.String() != "id: 7\ndata: a\ndata: b\n\n" {
+		t.Errorf("unexpected encoding %q", buf.String())
+	}
+	ev, err := NewSSEReader(&buf).Next()
+	if err != nil || ev.ID != "7" || ev.Data != "a\nb" {
+		t.Errorf("round trip failed: %+v %v", ev, err)
+	}
+}
+
+// fakeStreamableServer is a minimal Streamable HTTP server. Requests to
+// "stream" are answered over SSE, "interrupted" streams close before the
+// response and must be resumed, everything else is answered with JSON.
+type fakeStreamableServer struct {
+	mu       sync.Mutex
+	versions []string
+	deleted  bool
+	resumeID interface{}
+}
+
+func (s *fakeStreamableServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodDelete:
+		s.mu.Lock()
+		s.deleted = r.Header.Get(SessionIDHeader) == "session-1"
+		s.mu.Unlock()
+		return
+	case http.MethodGet:
+		if r.Header.Get(LastEventIDHeader) != "e1" {
+			http.Error(w, "no stream", http.StatusMethodNotAllowed)
+			return
+		}
+		s.mu.Lock()
+		id := s.resumeID
+		s.mu.Unlock()
+		data, _ := json.Marshal(&Response{JSONRPC: JSONRPCVersion, ID: id, Result: "resumed"})
+		w.Header().Set("Content-Type", "text/event-stream")
+		WriteSSEEvent(w, "e2", "", string(data))
+		return
+	}
+	var req Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if req.Method == "initialize" {
+		w.Header().Set(SessionIDHeader, "session-1")
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: InitializeResult{
+			ProtocolVersion: LatestProtocolVersion,
+			ServerInfo:      Implementation{Name: "fake", Version: "1.0.0"},
+		}})
+		return
+	}
+	if r.Header.Get(SessionIDHeader) != "session-1" {
+		http.Error(w, "missing session", http.StatusBadRequest)
+		return
+	}
+	s.mu.Lock()
+	s.versions = append(s.versions, r.Header.Get(ProtocolVersionHeader))
+	s.mu.Unlock()
+	switch {
+	case req.IsNotification():
+		w.WriteHeader(http.StatusAccepted)
+	case req.Method == "expired":
+		http.Error(w, "unknown session", http.StatusNotFound)
+	case req.Method == "stream":
+		w.Header().Set("Content-Type", "text/event-stream")
+		note, _ := json.Marshal(NewNotification("notifications/message", map[string]interface{}{"data": "working"}))
+		WriteSSEEvent(w, "", "", string(note))
+		data, _ := json.Marshal(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: "streamed"})
+		WriteSSEEvent(w, "", "message", string(data))
+	case req.Method == "interrupted":
+		s.mu.Lock()
+		s.resumeID = req.ID
+		s.mu.Unlock()
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Write([]byte("retry: 10\n\n"))
+		WriteSSEEvent(w, "e1", "", `{"jsonrpc":"2.0","method":"notifications/progress"}`)
+	default:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: req.Method})
+	}
+}
+
+func TestStreamableHTTPTransport(t *testing.T) {
+	fake := &fakeStreamableServer{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	tr := NewStreamableHTTPTransport(srv.URL, nil)
+	c := NewMCPClientWithTransport(tr, io.Discard)
+
+	notes := make(chan string, 4)
+	c.OnNotification("*", func(n *Request) { notes <- n.Method })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := c.InitializeContext(ctx, LatestProtocolVersion, Implementation{Name: "test"}, ClientCapabilities{}, nil); err != nil {
+		t.Fatalf("initialize failed: %v", err)
+	}
+	if tr.SessionID() != "session-1" {
+		t.Errorf("expected session id to be captured, got %q", tr.SessionID())
+	}
+
+	resp, err := c.CallContext(ctx, "plain", nil, nil)
+	if err != nil || resp.Result != "plain" {
+		t.Fatalf("json call failed: %v %+v", err, resp)
+	}
+	resp, err = c.CallContext(ctx, "stream", nil, nil)
+	if err != nil || resp.Result != "streamed" {
+		t.Fatalf("sse call failed: %v %+v", err, resp)
+	}
+	select {
+	case method := <-notes:
+		if method != "notifications/message" {
+			t.Errorf("unexpected notification %s", method)
+		}
+	case <-ctx.Done():
+		t.Fatal("notification from the SSE stream was not delivered")
+	}
+
+	tr.ReconnectDelay = time.Millisecond
+	resp, err = c.CallContext(ctx, "interrupted", nil, nil)
+	if err != nil || resp.Result != "resumed" {
+		t.Fatalf("resumed call failed: %v %+v", err, resp)
+	}
+
+	if err := tr.Send(ctx, []byte(`{"jsonrpc":"2.0","method":"expired","id":"x"}`)); !errors.Is(err, ErrSessionExpired) {
+		t.Errorf("expected ErrSessionExpired, got %v", err)
+	}
+	if err := tr.OpenStream(ctx); !errors.Is(err, ErrNoEventStream) {
+		t.Errorf("expected ErrNoEventStream, got %v", err)
+	}
+
+	c.Close()
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	if !fake.deleted {
+		t.Error("expected session to be terminated with DELETE")
+	}
+	for _, v := range fake.versions {
+		if v != LatestProtocolVersion {
+			t.Errorf("expected %s header %s, got %q", ProtocolVersionHeader, LatestProtocolVersion, v)
+		}
+	}
+}
+
+func TestStreamableHTTPTransport_LostStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+	}))
+	defer srv.Close()
+	c := NewMCPClientWithTransport(NewStreamableHTTPTransport(srv.URL, nil), io.Discard)
+	defer c.Close()
+	resp, err := c.Call("ping", nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error == nil || resp.Error.Code != InternalError {
+		t.Errorf("expected error response for lost stream, got %+v", resp)
+	}
+}
+
+func TestStreamableHTTPTransport_LegacyFallback(t *testing.T) {
+	messages := make(chan []byte, 4)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		WriteSSEEvent(w, "", "endpoint", "/messages?session=abc")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case msg := <-messages:
+				WriteSSEEvent(w, "", "message", string(msg))
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	})
+	mux.HandleFunc("/messages", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("session") != "abc" {
+			http.Error(w, "unknown session", http.StatusBadRequest)
+			return
+		}
+		var req Request
+		json.NewDecoder(r.Body).Decode(&req)
+		if !req.IsNotification() {
+			data, _ := json.Marshal(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: InitializeResult{ProtocolVersion: "2024-11-05"}})
+			messages <- data
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	c := NewMCPClientWithTransport(NewStreamableHTTPTransport(srv.URL+"/mcp", nil), io.Discard)
+	defer c.Close()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := c.InitializeContext(ctx, "2024-11-05", Implementation{Name: "test"}, ClientCapabilities{}, nil)
+	if err != nil {
+		t.Fatalf("initialize over HTTP+SSE failed: %v", err)
+	}
+	if result.ProtocolVersion != "2024-11-05" {
+		t.Errorf("unexpected protocol version %s", result.ProtocolVersion)
+	}
+}
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// Notifications sent by servers about their resources.
+const (
+	ResourceUpdatedNotification     = "notifications/resources/updated"
+	ResourceListChangedNotification = "notifications/resources/list_changed"
+)
+
+func (c *MCPClient) SubscribeResource(uri string, id interface{}) (*Response, error) {
+	return c.SubscribeResourceContext(context.Background(), uri, id)
+}
+
+// SubscribeResourceContext calls resources/subscribe and honors the context
+// deadline. Updates are delivered to the handlers registered with
+// OnResourceUpdated.
+func (c *MCPClient) SubscribeResourceContext(ctx context.Context, uri string, id interface{}) (*Response, error) {
+	sanitized, err := sanitizeURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return c.CallContext(ctx, "resources/subscribe", map[string]interface{}{"uri": sanitized}, id)
+}
+
+func (c *MCPClient) UnsubscribeResource(uri string, id interface{}) (*Response, error) {
+	return c.UnsubscribeResourceContext(context.Background(), uri, id)
+}
+
+// UnsubscribeResourceContext calls resources/unsubscribe and honors the
+// context deadline.
+func (c *MCPClient) UnsubscribeResourceContext(ctx context.Context, uri string, id interface{}) (*Response, error) {
+	sanitized, err := sanitizeURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	return c.CallContext(ctx, "resources/unsubscribe", map[string]interface{}{"uri": sanitized}, id)
+}
+
+// OnResourceUpdated registers a handler called with the URI of each
+// notifications/resources/updated notification about uri, or about any
+// resource when uri is empty. The returned function removes the handler.
+func (c *MCPClient) OnResourceUpdated(uri string, handler func(uri string)) func() {
+	return c.OnNotification(ResourceUpdatedNotification, func(n *Request) {
+		updated, _ := n.Params["uri"].(string)
+		if updated != "" && (uri == "" || updated == uri) {
+			handler(updated)
+		}
+	})
+}
+
+// OnResourceListChanged registers a handler called for each
+// notifications/resources/list_changed notification. The returned function
+// removes the handler.
+func (c *MCPClient) OnResourceListChanged(handler func()) func() {
+	return c.OnNotification(ResourceListChangedNotification, func(*Request) {
+		handler()
+	})
+}
+
+// ResourceSubscription is a subscription to the updates of a resource,
+// created by WatchResource.
+type ResourceSubscription struct {
+	URI string
+
+	client *MCPClient
+	remove func()
+	once   sync.Once
+}
+
+// WatchResource subscribes to a resource and calls handler with its URI each
+// time the server reports it updated, until the subscription is closed. An
+// error response to resources/subscribe is returned as an error.
+func (c *MCPClient) WatchResource(ctx context.Context, uri string, handler func(uri string)) (*ResourceSubscription, error) {
+	remove := c.OnResourceUpdated(uri, handler)
+	resp, err := c.SubscribeResourceContext(ctx, uri, nil)
+	if err == nil && resp.Error != nil {
+		err = fmt.Errorf("resources/subscribe failed: %s (code %d)", resp.Error.Message, resp.Error.Code)
+	}
+	if err != nil {
+		remove()
+		return nil, err
+	}
+	return &ResourceSubscription{URI: uri, client: c, remove: remove}, nil
+}
+
+// Close stops the delivery of updates and unsubscribes from the resource.
+// Closing a subscription more than once does nothing.
+func (s *ResourceSubscription) Close(ctx context.Context) error {
+	var err error
+	s.once.Do(func() {
+		s.remove()
+		var resp *Response
+		resp, err = s.client.UnsubscribeResourceContext(ctx, s.URI, nil)
+		if err == nil && resp.Error != nil {
+			err = fmt.Errorf("resources/unsubscribe failed: %s (code %d)", resp.Error.Message, resp.Error.Code)
+		}
+	})
+	return err
+}
@@ -0,0 +1,91 @@
+package core
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatchResource(t *testing.T) {
+	var mu sync.Mutex
+	subscribed := map[string]bool{}
+	c, _ := startFakeServer(t, func(req *Request, send func(interface{})) *Response {
+		uri, _ := req.Params["uri"].(string)
+		mu.Lock()
+		defer mu.Unlock()
+		switch req.Method {
+		case "resources/subscribe":
+			if uri == "file:///forbidden" {
+				return NewErrorResponse(req.ID, InvalidParams, "not allowed", nil)
+			}
+			subscribed[uri] = true
+		case "resources/unsubscribe":
+			delete(subscribed, uri)
+		case "touch":
+			// Updates every resource, subscribed or not, and the list.
+			for _, u := range []string{"file:///a", "file:///b"} {
+				if subscribed[u] || u == "file:///b" {
+					send(NewNotification(ResourceUpdatedNotification, map[string]interface{}{"uri": u}))
+				}
+			}
+			send(NewNotification(ResourceListChangedNotification, nil))
+		}
+		return &Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: map[string]interface{}{}}
+	})
+	ctx := context.Background()
+
+	updates := make(chan string, 10)
+	sub, err := c.WatchResource(ctx, "file:///a", func(uri string) { updates <- uri })
+	if err != nil {
+		t.Fatal(err)
+	}
+	listChanged := make(chan struct{}, 10)
+	c.OnResourceListChanged(func() { listChanged <- struct{}{} })
+
+	if _, err := c.Call("touch", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case uri := <-updates:
+		if uri != "file:///a" {
+			t.Errorf("unexpected update for %s", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no update delivered")
+	}
+	select {
+	case <-listChanged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no list change delivered")
+	}
+
+	if err := sub.Close(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := sub.Close(ctx); err != nil {
+		t.Errorf("closing twice: %v", err)
+	}
+	mu.Lock()
+	if subscribed["file:///a"] {
+		t.Error("Close should unsubscribe")
+	}
+	mu.Unlock()
+	if _, err := c.Call("touch", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	<-listChanged
+	select {
+	case uri := <-updates:
+		t.Errorf("update for %s delivered after Close", uri)
+	default:
+	}
+
+	if _, err := c.WatchResource(ctx, "file:///forbidden", func(string) {}); err == nil || !strings.Contains(err.Error(), "not allowed (code -32602)") {
+		t.Errorf("expected a subscribe error, got %v", err)
+	}
+	if _, err := c.SubscribeResource(" ", nil); err == nil {
+		t.Error("expected an error for an empty uri")
+	}
+}
@@ -0,0 +1,255 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines around the changes of a
+// unified diff hunk.
+const diffContext = 3
+
+// diffLine is one line of an edit script: ' ' keeps it, '-' deletes it and
+// '+' inserts it.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// splitLines splits text into lines, each keeping its trailing newline. The
+// last line has none when text does not end with a newline.
+func splitLines(text []byte) []string {
+	var lines []string
+	for len(text) > 0 {
+		i := bytes.IndexByte(text, '\n')
+		if i < 0 {
+			lines = append(lines, string(text))
+			break
+		}
+		lines = append(lines, string(text[:i+1]))
+		text = text[i+1:]
+	}
+	return lines
+}
+
+// matchLines pairs the lines of a and b along a longest common subsequence.
+// It returns, for each line of a, the index of its line in b, or -1 when the
+// line is not part of b.
+func matchLines(a, b []string) []int {
+	match := make([]int, len(a))
+	for i := range match {
+		match[i] = -1
+	}
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		match[prefix] = prefix
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		match[len(a)-1-suffix] = len(b) - 1 - suffix
+		suffix++
+	}
+
+	a, b = a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	// lengths[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lengths := make([][]int32, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			switch {
+			case a[i] == b[j]:
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			case lengths[i+1][j] >= lengths[i][j+1]:
+				lengths[i][j] = lengths[i+1][j]
+			default:
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			match[prefix+i] = prefix + j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return match
+}
+
+// diffLines returns the edit script turning a into b, deletions before
+// insertions.
+func diffLines(a, b []string) []diffLine {
+	match := matchLines(a, b)
+	var script []diffLine
+	j := 0
+	for i, line := range a {
+		if match[i] < 0 {
+			script = append(script, diffLine{'-', line})
+			continue
+		}
+		for ; j < match[i]; j++ {
+			script = append(script, diffLine{'+', b[j]})
+		}
+		script = append(script, diffLine{' ', line})
+		j++
+	}
+	for ; j < len(b); j++ {
+		script = append(script, diffLine{'+', b[j]})
+	}
+	return script
+}
+
+// UnifiedDiff returns the unified diff turning a into b, with fromName and
+// toName in its header, or "" when they are equal.
+func UnifiedDiff(a, b []byte, fromName, toName string) string {
+	if bytes.Equal(a, b) {
+		return ""
+	}
+	script := diffLines(splitLines(a), splitLines(b))
+	// aLine[k] and bLine[k] count the lines of a and b before script[k].
+	aLine, bLine := make([]int, len(script)+1), make([]int, len(script)+1)
+	for k, l := range script {
+		aLine[k+1], bLine[k+1] = aLine[k], bLine[k]
+		if l.op != '+' {
+			aLine[k+1]++
+		}
+		if l.op != '-' {
+			bLine[k+1]++
+		}
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", fromName, toName)
+	for k := 0; k < len(script); {
+		for k < len(script) && script[k].op == ' ' {
+			k++
+		}
+		if k == len(script) {
+			break
+		}
+		start := k - diffContext
+		if start < 0 {
+			start = 0
+		}
+		end := k
+		for end < len(script) {
+			if script[end].op != ' ' {
+				end++
+				continue
+			}
+			run := end
+			for run < len(script) && script[run].op == ' ' {
+				run++
+			}
+			if run == len(script) || run-end > 2*diffContext {
+				end += diffContext
+				if end > len(script) {
+					end = len(script)
+				}
+				break
+			}
+			end = run
+		}
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(aLine[start], aLine[end]-aLine[start]), hunkRange(bLine[start], bLine[end]-bLine[start]))
+		for _, l := range script[start:end] {
+			out.WriteByte(l.op)
+			out.WriteString(l.text)
+			if !strings.HasSuffix(l.text, "\n") {
+				out.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		k = end
+	}
+	return out.String()
+}
+
+// hunkRange formats the range of a hunk starting after line start.
+func hunkRange(start, count int) string {
+	switch count {
+	case 0:
+		return fmt.Sprintf("%d,0", start)
+	case 1:
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// Merge3 merges the changes made to base by ours and by theirs, line by
+// line. Where both changed the same lines differently, the merged text holds
+// both versions between conflict markers labelled oursLabel and theirsLabel.
+// It returns the merged text and the number of conflicts.
+func Merge3(base, ours, theirs []byte, oursLabel, theirsLabel string) ([]byte, int) {
+	baseLines, ourLines, theirLines := splitLines(base), splitLines(ours), splitLines(theirs)
+	ourMatch, theirMatch := matchLines(baseLines, ourLines), matchLines(baseLines, theirLines)
+
+	var out bytes.Buffer
+	conflicts := 0
+	for i, o, t := 0, 0, 0; ; {
+		// Copy the lines unchanged on both sides.
+		for i < len(baseLines) && ourMatch[i] == o && theirMatch[i] == t {
+			out.WriteString(baseLines[i])
+			i, o, t = i+1, o+1, t+1
+		}
+		// The changed chunk ends at the next base line kept on both sides.
+		j := i
+		for j < len(baseLines) && (ourMatch[j] < 0 || theirMatch[j] < 0) {
+			j++
+		}
+		oEnd, tEnd := len(ourLines), len(theirLines)
+		if j < len(baseLines) {
+			oEnd, tEnd = ourMatch[j], theirMatch[j]
+		}
+		b, x, y := baseLines[i:j], ourLines[o:oEnd], theirLines[t:tEnd]
+		switch {
+		case equalLines(x, b):
+			writeLines(&out, y, false)
+		case equalLines(y, b), equalLines(x, y):
+			writeLines(&out, x, false)
+		default:
+			conflicts++
+			out.WriteString("<<<<<<< " + oursLabel + "\n")
+			writeLines(&out, x, true)
+			out.WriteString("=======\n")
+			writeLines(&out, y, true)
+			out.WriteString(">>>>>>> " + theirsLabel + "\n")
+		}
+		if j == len(baseLines) {
+			break
+		}
+		i, o, t = j, oEnd, tEnd
+	}
+	return out.Bytes(), conflicts
+}
+
+func equalLines(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// writeLines writes lines to out, ending the last one with a newline when
+// terminate is set.
+func writeLines(out *bytes.Buffer, lines []string, terminate bool) {
+	for _, l := range lines {
+		out.WriteString(l)
+	}
+	if terminate && len(lines) > 0 && !strings.HasSuffix(lines[len(lines)-1], "\n") {
+		out.WriteByte('\n')
+	}
+}
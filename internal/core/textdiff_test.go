@@ -0,0 +1,61 @@
+package core
+
+import "testing"
+
+func TestMerge3(t *testing.T) {
+	base := "a\nb\nc\nd\ne\n"
+	for _, c := range []struct {
+		name, ours, theirs, want string
+		conflicts                int
+	}{
+		{"unchanged", base, base, base, 0},
+		{"ours only", "a\nB\nc\nd\ne\n", base, "a\nB\nc\nd\ne\n", 0},
+		{"theirs only", base, "a\nb\nc\nd\nE\n", "a\nb\nc\nd\nE\n", 0},
+		{"both, apart", "a\nB\nc\nd\ne\n", "a\nb\nc\nd\nE\n", "a\nB\nc\nd\nE\n", 0},
+		{"same change", "a\nX\nc\nd\ne\n", "a\nX\nc\nd\ne\n", "a\nX\nc\nd\ne\n", 0},
+		{"insert and delete", "a\nb\nnew\nc\nd\ne\n", "a\nb\nc\ne\n", "a\nb\nnew\nc\ne\n", 0},
+		{"appended", base + "ours\n", base + "theirs\n", base + "<<<<<<< ours\nours\n=======\ntheirs\n>>>>>>> theirs\n", 1},
+		{"conflict", "a\nb\nOURS\nd\ne\n", "a\nb\nTHEIRS\nd\ne\n", "a\nb\n<<<<<<< ours\nOURS\n=======\nTHEIRS\n>>>>>>> theirs\nd\ne\n", 1},
+		{"no final newline", "a\nb\nc\nd\ne", "A\nb\nc\nd\ne\n", "A\nb\nc\nd\ne", 0},
+		{"conflict without final newline", "a\nb\nc\nd\nx", "a\nb\nc\nd\ny", "a\nb\nc\nd\n<<<<<<< ours\nx\n=======\ny\n>>>>>>> theirs\n", 1},
+	} {
+		got, conflicts := Merge3([]byte(base), []byte(c.ours), []byte(c.theirs), "ours", "theirs")
+		if string(got) != c.want || conflicts != c.conflicts {
+			t.Errorf("%s: got %d conflict(s)\n%s\nwant %d\n%s", c.name, conflicts, got, c.conflicts, c.want)
+		}
+	}
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n15\nsixteen"
+	want := `--- a/f
++++ b/f
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -11,5 +11,5 @@
+ 11
+ 12
+ 13
+-14
+ 15
++sixteen
+\ No newline at end of file
+`
+	if got := UnifiedDiff([]byte(a), []byte(b), "a/f", "b/f"); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := UnifiedDiff([]byte(a), []byte(a), "a/f", "b/f"); got != "" {
+		t.Errorf("expected no diff for equal texts, got\n%s", got)
+	}
+	want = "--- a/f\n+++ b/f\n@@ -0,0 +1,2 @@\n+x\n+y\n"
+	if got := UnifiedDiff(nil, []byte("x\ny\n"), "a/f", "b/f"); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+}
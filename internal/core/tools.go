@@ -0,0 +1,129 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+)
+
+// ToolDefinition is a tool advertised by a server in its tools/list result.
+type ToolDefinition struct {
+	Name         string                 `json:"name"`
+	Title        string                 `json:"title,omitempty"`
+	Description  string                 `json:"description,omitempty"`
+	InputSchema  json.RawMessage        `json:"inputSchema,omitempty"`
+	OutputSchema json.RawMessage        `json:"outputSchema,omitempty"`
+	Annotations  map[string]interface{} `json:"annotations,omitempty"`
+}
+
+// ListToolsResult is the result of the tools/list request.
+type ListToolsResult struct {
+	Tools      []ToolDefinition `json:"tools"`
+	NextCursor string           `json:"nextCursor,omitempty"`
+}
+
+// DecodeListToolsResult decodes the result of a tools/list response.
+func DecodeListToolsResult(resp *Response) (*ListToolsResult, error) {
+	var result ListToolsResult
+	if err := decodeResult(resp, &result); err != nil {
+		return nil, fmt.Errorf("invalid tools/list result: %w", err)
+	}
+	return &result, nil
+}
+
+// CompileInputSchema compiles the tool's inputSchema, which the specification
+// requires to be a JSON Schema for an object.
+func (t ToolDefinition) CompileInputSchema() (*Schema, error) {
+	if len(t.InputSchema) == 0 {
+		return nil, fmt.Errorf("inputSchema is missing")
+	}
+	s, err := ParseSchema(t.InputSchema)
+	if err != nil {
+		return nil, err
+	}
+	if !s.IsType("object") {
+		return nil, fmt.Errorf("inputSchema must have type \"object\"")
+	}
+	return s, nil
+}
+
+// CheckToolResult verifies that the result of a tools/call request is well
+// formed: content must be an array of text, image, audio, resource_link or
+// embedded resource items carrying the fields their type requires. It
+// returns one error per problem found.
+func CheckToolResult(result interface{}) []error {
+	obj, ok := normalizeJSON(result).(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("result must be an object, got %s", jsonType(result))}
+	}
+	var errs []error
+	if v, ok := obj["isError"]; ok {
+		if _, ok := v.(bool); !ok {
+			errs = append(errs, fmt.Errorf("isError must be a boolean, got %s", jsonType(v)))
+		}
+	}
+	if v, ok := obj["structuredContent"]; ok {
+		if _, ok := v.(map[string]interface{}); !ok {
+			errs = append(errs, fmt.Errorf("structuredContent must be an object, got %s", jsonType(v)))
+		}
+	}
+	content, ok := obj["content"].([]interface{})
+	if !ok {
+		if _, present := obj["content"]; !present {
+			return append(errs, fmt.Errorf("content is missing"))
+		}
+		return append(errs, fmt.Errorf("content must be an array, got %s", jsonType(obj["content"])))
+	}
+	for i, item := range content {
+		for _, err := range checkContentItem(item) {
+			errs = append(errs, fmt.Errorf("content[%d]: %w", i, err))
+		}
+	}
+	return errs
+}
+
+// checkContentItem verifies a single content block.
+func checkContentItem(item interface{}) []error {
+	obj, ok := item.(map[string]interface{})
+	if !ok {
+		return []error{fmt.Errorf("must be an object, got %s", jsonType(item))}
+	}
+	var errs []error
+	requireString := func(m map[string]interface{}, field string) string {
+		s, ok := m[field].(string)
+		if !ok {
+			errs = append(errs, fmt.Errorf("%s must be a string", field))
+		}
+		return s
+	}
+	requireBase64 := func(m map[string]interface{}, field string) {
+		if s := requireString(m, field); s != "" {
+			if _, err := base64.StdEncoding.DecodeString(s); err != nil {
+				errs = append(errs, fmt.Errorf("%s is not valid base64", field))
+			}
+		}
+	}
+	switch t, _ := obj["type"].(string); t {
+	case "text":
+		requireString(obj, "text")
+	case "image", "audio":
+		requireBase64(obj, "data")
+		if mimeType, _ := obj["mimeType"].(string); mimeType == "" {
+			errs = append(errs, fmt.Errorf("mimeType must be a non-empty string"))
+		}
+	case "resource_link":
+		requireString(obj, "uri")
+		requireString(obj, "name")
+	case "resource":
+		resource, ok := obj["resource"].(map[string]interface{})
+		if !ok {
+			return []error{fmt.Errorf("resource must be an object")}
+		}
+		errs = append(errs, checkResourceContents(resource)...)
+	case "":
+		errs = append(errs, fmt.Errorf("type is missing"))
+	default:
+		errs = append(errs, fmt.Errorf("unknown content type %q", t))
+	}
+	return errs
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCheckToolResult(t *testing.T) {
+	valid := map[string]interface{}{
+		"content": []interface{}{
+			map[string]interface{}{"type": "text", "text": "hi"},
+			map[string]interface{}{"type": "image", "data": "aGk=", "mimeType": "image/png"},
+			map[string]interface{}{"type": "resource_link", "uri": "file:///a", "name": "a"},
+			map[string]interface{}{"type": "resource", "resource": map[string]interface{}{"uri": "file:///a", "blob": "aGk="}},
+		},
+		"isError":           false,
+		"structuredContent": map[string]interface{}{"ok": true},
+	}
+	if errs := CheckToolResult(valid); len(errs) != 0 {
+		t.Fatalf("unexpected errors: %v", errs)
+	}
+
+	cases := []struct {
+		result interface{}
+		want   string
+	}{
+		{"text", "result must be an object"},
+		{map[string]interface{}{}, "content is missing"},
+		{map[string]interface{}{"content": "hi"}, "content must be an array"},
+		{map[string]interface{}{"content": []interface{}{"hi"}}, "content[0]: must be an object"},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"text": "hi"}}}, "content[0]: type is missing"},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "video"}}}, `unknown content type "video"`},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": 1}}}, "content[0]: text must be a string"},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "audio", "data": "!!", "mimeType": "audio/wav"}}}, "data is not valid base64"},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "image", "data": "aGk="}}}, "mimeType must be a non-empty string"},
+		{map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "resource", "resource": map[string]interface{}{"uri": "x"}}}}, "resource must have text or blob"},
+		{map[string]interface{}{"content": []interface{}{}, "isError": "yes"}, "isError must be a boolean"},
+	}
+	for _, c := range cases {
+		errs := CheckToolResult(c.result)
+		if len(errs) == 0 || !strings.Contains(errs[0].Error(), c.want) {
+			t.Errorf("%v: expected %q, got %v", c.result, c.want, errs)
+		}
+	}
+}
+
+func TestToolDefinition_CompileInputSchema(t *testing.T) {
+	if _, err := (ToolDefinition{Name: "a"}).CompileInputSchema(); err == nil || !strings.Contains(err.Error(), "missing") {
+		t.Errorf("expected missing schema error, got %v", err)
+	}
+	if _, err := (ToolDefinition{Name: "a", InputSchema: []byte(`{"type":"string"}`)}).CompileInputSchema(); err == nil {
+		t.Error("expected error for non-object schema")
+	}
+	if _, err := (ToolDefinition{Name: "a", InputSchema: []byte(`{"type":"object"}`)}).CompileInputSchema(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,323 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// ToolParameterTypes are the JSON types a tool parameter may declare.
+var ToolParameterTypes = []string{"string", "number", "integer", "boolean", "array", "object"}
+
+// Validate checks that the tool's parameters have unique names, known types
+// and enum and default values of their type, and that its output schema is a
+// JSON Schema for an object.
+func (t Tool) Validate() error {
+	seen := map[string]bool{}
+	for i, p := range t.Parameters {
+		if err := p.Validate(); err != nil {
+			if p.Name == "" {
+				return fmt.Errorf("parameter %d: %w", i, err)
+			}
+			return fmt.Errorf("parameter %s: %w", p.Name, err)
+		}
+		if seen[p.Name] {
+			return fmt.Errorf("parameter %s: duplicate name", p.Name)
+		}
+		seen[p.Name] = true
+	}
+	if len(t.OutputSchema) > 0 {
+		s, err := ParseSchema(t.OutputSchema)
+		if err != nil {
+			return fmt.Errorf("invalid outputSchema: %w", err)
+		}
+		if !s.IsType("object") {
+			return fmt.Errorf("outputSchema must have type \"object\"")
+		}
+	}
+	return nil
+}
+
+// ValidateUpdates checks that the tool only updates resources declared in
+// resources, and no resource template, whose URIs are not known in advance.
+func (t Tool) ValidateUpdates(resources []Resource) error {
+	for _, name := range t.Updates {
+		found := false
+		for _, r := range resources {
+			if r.Name != name {
+				continue
+			}
+			if r.URITemplate != "" {
+				return fmt.Errorf("updates resource template %s, only resources with a fixed URI can be updated", name)
+			}
+			found = true
+		}
+		if !found {
+			return fmt.Errorf("updates unknown resource %s", name)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the parameter has a name and a known type, and that
+// its enum and default values are of that type.
+func (p ToolParameter) Validate() error {
+	if p.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if !containsString(ToolParameterTypes, p.Type) {
+		return fmt.Errorf("invalid type %q, valid types are: %v", p.Type, ToolParameterTypes)
+	}
+	typeOnly, err := CompileSchema(map[string]interface{}{"type": p.Type})
+	if err != nil {
+		return err
+	}
+	for _, v := range p.Enum {
+		if !typeOnly.Accepts(v) {
+			return fmt.Errorf("enum value %s is not of type %s", compactJSON(v), p.Type)
+		}
+	}
+	if p.Default != nil {
+		if !typeOnly.Accepts(p.Default) {
+			return fmt.Errorf("default %s is not of type %s", compactJSON(p.Default), p.Type)
+		}
+		if len(p.Enum) > 0 && !containsValue(normalizeJSON(p.Enum).([]interface{}), normalizeJSON(p.Default)) {
+			return fmt.Errorf("default %s is not one of the enum values", compactJSON(p.Default))
+		}
+	}
+	return nil
+}
+
+// InputSchema builds the JSON Schema of the tool's arguments, as advertised
+// in the inputSchema of tools/list.
+func (t Tool) InputSchema() map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, p := range t.Parameters {
+		prop := map[string]interface{}{"type": p.Type}
+		if p.Description != "" {
+			prop["description"] = p.Description
+		}
+		if len(p.Enum) > 0 {
+			prop["enum"] = p.Enum
+		}
+		if p.Default != nil {
+			prop["default"] = p.Default
+		}
+		properties[p.Name] = prop
+		if p.Required {
+			required = append(required, p.Name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// InputSchemaJSON returns the tool's input schema encoded as compact JSON for
+// embedding in generated code.
+func (t Tool) InputSchemaJSON() string {
+	data, err := json.Marshal(t.InputSchema())
+	if err != nil {
+		return `{"type":"object"}`
+	}
+	return string(data)
+}
+
+// OutputSchemaJSON returns the tool's output schema as compact JSON, or an
+// empty string when the tool has none.
+func (t Tool) OutputSchemaJSON() string {
+	var b bytes.Buffer
+	if err := json.Compact(&b, t.OutputSchema); err != nil {
+		return ""
+	}
+	return b.String()
+}
+
+// UpdatesJSON returns the names of the resources the tool updates encoded
+// as a JSON array.
+func (t Tool) UpdatesJSON() string {
+	if len(t.Updates) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(t.Updates)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// ParametersJSON returns the tool's parameters encoded as JSON.
+func (t Tool) ParametersJSON() string {
+	if len(t.Parameters) == 0 {
+		return "[]"
+	}
+	data, err := json.Marshal(t.Parameters)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+// reservedWords are the keywords of the generated languages that cannot name
+// a field or variable.
+var reservedWords = map[string]bool{
+	"abstract": true, "and": true, "as": true, "assert": true, "async": true, "await": true,
+	"boolean": true, "break": true, "byte": true, "case": true, "catch": true, "char": true,
+	"class": true, "const": true, "continue": true, "def": true, "default": true, "del": true,
+	"delete": true, "do": true, "double": true, "elif": true, "else": true, "enum": true,
+	"except": true, "export": true, "extends": true, "false": true, "final": true,
+	"finally": true, "float": true, "for": true, "from": true, "function": true, "global": true,
+	"goto": true, "if": true, "implements": true, "import": true, "in": true, "instanceof": true,
+	"int": true, "interface": true, "is": true, "lambda": true, "let": true, "long": true,
+	"native": true, "new": true, "none": true, "nonlocal": true, "not": true, "null": true,
+	"or": true, "package": true, "pass": true, "private": true, "protected": true,
+	"public": true, "raise": true, "return": true, "short": true, "static": true,
+	"super": true, "switch": true, "synchronized": true, "this": true, "throw": true,
+	"throws": true, "transient": true, "true": true, "try": true, "typeof": true, "var": true,
+	"void": true, "volatile": true, "while": true, "with": true, "yield": true,
+}
+
+// nameWords splits a parameter name into words at non-alphanumeric
+// characters and lower-to-upper case changes.
+func nameWords(name string) []string {
+	var words []string
+	var word []rune
+	var prev rune
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = nil
+		case unicode.IsUpper(r) && len(word) > 0 && (unicode.IsLower(prev) || unicode.IsDigit(prev)):
+			words = append(words, string(word))
+			word = []rune{r}
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+	if len(words) == 0 {
+		return []string{"arg"}
+	}
+	if unicode.IsDigit([]rune(words[0])[0]) {
+		words = append([]string{"arg"}, words...)
+	}
+	return words
+}
+
+// FieldName returns the parameter name as an exported Go identifier, such as
+// FilePath for file_path.
+func (p ToolParameter) FieldName() string {
+	var b strings.Builder
+	for _, w := range nameWords(p.Name) {
+		r := []rune(w)
+		b.WriteString(string(unicode.ToUpper(r[0])) + string(r[1:]))
+	}
+	return b.String()
+}
+
+// VarName returns the parameter name as a lower camel case Java and
+// JavaScript identifier, such as filePath for file_path.
+func (p ToolParameter) VarName() string {
+	r := []rune(p.FieldName())
+	name := string(unicode.ToLower(r[0])) + string(r[1:])
+	if reservedWords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// PythonName returns the parameter name as a snake case Python identifier,
+// such as file_path for filePath.
+func (p ToolParameter) PythonName() string {
+	name := strings.ToLower(strings.Join(nameWords(p.Name), "_"))
+	if reservedWords[name] {
+		name += "_"
+	}
+	return name
+}
+
+// GoType returns the Go type holding the parameter's values.
+func (p ToolParameter) GoType() string {
+	switch p.Type {
+	case "string":
+		return "string"
+	case "number":
+		return "float64"
+	case "integer":
+		return "int64"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "[]interface{}"
+	default:
+		return "map[string]interface{}"
+	}
+}
+
+// JavaType returns the boxed Java type holding the parameter's values, null
+// when the argument is absent.
+func (p ToolParameter) JavaType() string {
+	switch p.Type {
+	case "string":
+		return "String"
+	case "number":
+		return "Double"
+	case "integer":
+		return "Long"
+	case "boolean":
+		return "Boolean"
+	case "array":
+		return "JSONArray"
+	default:
+		return "JSONObject"
+	}
+}
+
+// JavaGetter returns the org.json JSONObject method reading the parameter's
+// values.
+func (p ToolParameter) JavaGetter() string {
+	return "get" + p.JavaType()
+}
+
+// PythonType returns the Python type holding the parameter's values.
+func (p ToolParameter) PythonType() string {
+	switch p.Type {
+	case "string":
+		return "str"
+	case "number":
+		return "float"
+	case "integer":
+		return "int"
+	case "boolean":
+		return "bool"
+	case "array":
+		return "list"
+	default:
+		return "dict"
+	}
+}
+
+// JSType returns the JSDoc type of the parameter's values.
+func (p ToolParameter) JSType() string {
+	switch p.Type {
+	case "string", "number", "boolean":
+		return p.Type
+	case "integer":
+		return "number"
+	case "array":
+		return "Array"
+	default:
+		return "Object"
+	}
+}
@@ -0,0 +1,131 @@
+package core
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestTool_Validate(t *testing.T) {
+	valid := Tool{
+		Name: "search",
+		Parameters: []ToolParameter{
+			{Name: "query", Type: "string", Required: true},
+			{Name: "limit", Type: "integer", Default: 10},
+			{Name: "mode", Type: "string", Enum: []interface{}{"fast", "full"}, Default: "fast"},
+		},
+		OutputSchema: json.RawMessage(`{"type": "object"}`),
+	}
+	if err := valid.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cases := []struct {
+		tool Tool
+		want string
+	}{
+		{Tool{Parameters: []ToolParameter{{Type: "string"}}}, "parameter 0: name is required"},
+		{Tool{Parameters: []ToolParameter{{Name: "a", Type: "string"}, {Name: "a", Type: "number"}}}, "parameter a: duplicate name"},
+		{Tool{Parameters: []ToolParameter{{Name: "a", Type: "text"}}}, `parameter a: invalid type "text"`},
+		{Tool{Parameters: []ToolParameter{{Name: "a", Type: "integer", Enum: []interface{}{1, 1.5}}}}, "enum value 1.5 is not of type integer"},
+		{Tool{Parameters: []ToolParameter{{Name: "a", Type: "boolean", Default: "yes"}}}, `default "yes" is not of type boolean`},
+		{Tool{Parameters: []ToolParameter{{Name: "a", Type: "string", Enum: []interface{}{"x"}, Default: "y"}}}, "is not one of the enum values"},
+		{Tool{OutputSchema: json.RawMessage(`{"type": "array"}`)}, `outputSchema must have type "object"`},
+		{Tool{OutputSchema: json.RawMessage(`{"type": 1}`)}, "invalid outputSchema"},
+	}
+	for _, c := range cases {
+		err := c.tool.Validate()
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("Validate() = %v, want error containing %q", err, c.want)
+		}
+	}
+}
+
+func TestTool_InputSchema(t *testing.T) {
+	tool := Tool{Parameters: []ToolParameter{
+		{Name: "query", Type: "string", Description: "What to find", Required: true},
+		{Name: "mode", Type: "string", Enum: []interface{}{"fast", "full"}, Default: "fast"},
+	}}
+	want := `{"properties":{"mode":{"default":"fast","enum":["fast","full"],"type":"string"},"query":{"description":"What to find","type":"string"}},"required":["query"],"type":"object"}`
+	if got := tool.InputSchemaJSON(); got != want {
+		t.Errorf("InputSchemaJSON() = %s, want %s", got, want)
+	}
+	if got := (Tool{}).InputSchemaJSON(); got != `{"properties":{},"type":"object"}` {
+		t.Errorf("InputSchemaJSON() without parameters = %s", got)
+	}
+
+	def := ToolDefinition{InputSchema: json.RawMessage(tool.InputSchemaJSON())}
+	s, err := def.CompileInputSchema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Accepts(map[string]interface{}{"query": "x", "mode": "full"}) || s.Accepts(map[string]interface{}{"mode": "slow"}) {
+		t.Error("input schema does not check the parameters")
+	}
+}
+
+func TestTool_OutputSchemaJSON(t *testing.T) {
+	tool := Tool{OutputSchema: json.RawMessage("{\n  \"type\": \"object\"\n}")}
+	if got := tool.OutputSchemaJSON(); got != `{"type":"object"}` {
+		t.Errorf("OutputSchemaJSON() = %s", got)
+	}
+	if got := (Tool{}).OutputSchemaJSON(); got != "" {
+		t.Errorf("OutputSchemaJSON() without schema = %q", got)
+	}
+}
+
+func TestTool_ValidateUpdates(t *testing.T) {
+	resources := []Resource{{Name: "notes", Type: "filesystem"}, {Name: "files", Type: "filesystem", URITemplate: "file:///{path}"}}
+	if err := (Tool{Updates: []string{"notes"}}).ValidateUpdates(resources); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	for update, want := range map[string]string{"files": "resource template files", "missing": "unknown resource missing"} {
+		err := (Tool{Updates: []string{"notes", update}}).ValidateUpdates(resources)
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("updating %s: expected %q, got %v", update, want, err)
+		}
+	}
+	if got := (Tool{Updates: []string{"notes"}}).UpdatesJSON(); got != `["notes"]` {
+		t.Errorf("UpdatesJSON() = %s", got)
+	}
+	if got := (Tool{}).UpdatesJSON(); got != "[]" {
+		t.Errorf("UpdatesJSON() without updates = %s", got)
+	}
+}
+
+func TestToolParameter_Names(t *testing.T) {
+	cases := []struct {
+		name, field, variable, python string
+	}{
+		{"query", "Query", "query", "query"},
+		{"max_results", "MaxResults", "maxResults", "max_results"},
+		{"filePath", "FilePath", "filePath", "file_path"},
+		{"file-path", "FilePath", "filePath", "file_path"},
+		{"class", "Class", "class_", "class_"},
+		{"2fa", "Arg2fa", "arg2fa", "arg_2fa"},
+		{"-", "Arg", "arg", "arg"},
+	}
+	for _, c := range cases {
+		p := ToolParameter{Name: c.name}
+		if got := p.FieldName(); got != c.field {
+			t.Errorf("FieldName(%q) = %q, want %q", c.name, got, c.field)
+		}
+		if got := p.VarName(); got != c.variable {
+			t.Errorf("VarName(%q) = %q, want %q", c.name, got, c.variable)
+		}
+		if got := p.PythonName(); got != c.python {
+			t.Errorf("PythonName(%q) = %q, want %q", c.name, got, c.python)
+		}
+	}
+}
+
+func TestToolParameter_Types(t *testing.T) {
+	p := ToolParameter{Type: "integer"}
+	if p.GoType() != "int64" || p.JavaType() != "Long" || p.JavaGetter() != "getLong" || p.PythonType() != "int" || p.JSType() != "number" {
+		t.Errorf("unexpected integer types %s %s %s %s", p.GoType(), p.JavaType(), p.PythonType(), p.JSType())
+	}
+	p = ToolParameter{Type: "object"}
+	if p.GoType() != "map[string]interface{}" || p.JavaType() != "JSONObject" || p.PythonType() != "dict" || p.JSType() != "Object" {
+		t.Errorf("unexpected object types %s %s %s %s", p.GoType(), p.JavaType(), p.PythonType(), p.JSType())
+	}
+}
@@ -1,13 +1,53 @@
 package core
 
+import "encoding/json"
+
 type Tool struct {
 	Name        string
 	Description string
+	// Parameters are the arguments the tool accepts, from which its
+	// inputSchema is built.
+	Parameters []ToolParameter `json:"parameters,omitempty"`
+	// OutputSchema, when set, is the JSON Schema for an object describing
+	// the tool's structured content.
+	OutputSchema json.RawMessage `json:"outputSchema,omitempty"`
+	// Updates names the resources the tool changes. Generated servers
+	// notify the clients subscribed to them after each successful call and
+	// only advertise resource subscriptions when a tool updates a resource.
+	Updates []string `json:"updates,omitempty"`
+}
+
+// ToolParameter is an argument accepted by a tool. Type is one of the JSON
+// types in ToolParameterTypes.
+type ToolParameter struct {
+	Name        string        `json:"name"`
+	Type        string        `json:"type"`
+	Description string        `json:"description,omitempty"`
+	Required    bool          `json:"required,omitempty"`
+	Enum        []interface{} `json:"enum,omitempty"`
+	Default     interface{}   `json:"default,omitempty"`
 }
 
 type Resource struct {
 	Name string
 	Type string
+	// URITemplate, when set, makes the resource a template: an RFC 6570 URI
+	// template such as file:///{path} matching the URIs it serves.
+	URITemplate string `json:",omitempty"`
+}
+
+// Prompt is a prompt template offered by a generated server.
+type Prompt struct {
+	Name        string
+	Description string
+	Arguments   []PromptArgument
+}
+
+// PromptArgument is an argument filled in when a prompt is retrieved.
+type PromptArgument struct {
+	Name        string
+	Description string
+	Required    bool
 }
 
 type Capability struct {
@@ -44,6 +84,11 @@ func (r Resource) GetName() string {
 	return r.Name
 }
 
+// GetName returns the name of the prompt
+func (p Prompt) GetName() string {
+	return p.Name
+}
+
 // GetName returns the name of the capability
 func (c Capability) GetName() string {
 	return c.Name
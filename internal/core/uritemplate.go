@@ -0,0 +1,355 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// URITemplate is a parsed RFC 6570 URI template, such as file:///{path} or
+// https://api.example.com/users{/id}{?fields*}. All four levels of the RFC
+// are supported.
+type URITemplate struct {
+	raw   string
+	parts []uriTemplatePart
+
+	match     *regexp.Regexp
+	matchVars []string
+	matchErr  error
+}
+
+// uriTemplatePart is either a literal or an expression.
+type uriTemplatePart struct {
+	literal string
+	op      *uriTemplateOp
+	vars    []uriTemplateVar
+}
+
+type uriTemplateVar struct {
+	name    string
+	prefix  int
+	explode bool
+}
+
+// uriTemplateOp describes how an expression operator expands, following the
+// table of RFC 6570 appendix A.
+type uriTemplateOp struct {
+	first    string
+	sep      string
+	named    bool
+	ifEmpty  string
+	reserved bool
+}
+
+var uriTemplateOps = map[byte]*uriTemplateOp{
+	0:   {first: "", sep: ","},
+	'+': {first: "", sep: ",", reserved: true},
+	'#': {first: "#", sep: ",", reserved: true},
+	'.': {first: ".", sep: "."},
+	'/': {first: "/", sep: "/"},
+	';': {first: ";", sep: ";", named: true},
+	'?': {first: "?", sep: "&", named: true, ifEmpty: "="},
+	'&': {first: "&", sep: "&", named: true, ifEmpty: "="},
+}
+
+// ParseURITemplate parses an RFC 6570 URI template.
+func ParseURITemplate(s string) (*URITemplate, error) {
+	t := &URITemplate{raw: s}
+	rest := s
+	for rest != "" {
+		open := strings.IndexByte(rest, '{')
+		if open < 0 {
+			open = len(rest)
+		}
+		if open > 0 {
+			literal := rest[:open]
+			if i := strings.IndexAny(literal, " \"'<>\\^`|}"); i >= 0 {
+				return nil, fmt.Errorf("invalid URI template %q: character %q not allowed in a literal", s, literal[i])
+			}
+			for _, r := range literal {
+				if r < ' ' || r == 0x7f {
+					return nil, fmt.Errorf("invalid URI template %q: control character in a literal", s)
+				}
+			}
+			t.parts = append(t.parts, uriTemplatePart{literal: literal})
+			rest = rest[open:]
+			continue
+		}
+		end := strings.IndexByte(rest, '}')
+		if end < 0 {
+			return nil, fmt.Errorf("invalid URI template %q: unclosed expression", s)
+		}
+		part, err := parseURITemplateExpression(rest[1:end])
+		if err != nil {
+			return nil, fmt.Errorf("invalid URI template %q: %w", s, err)
+		}
+		t.parts = append(t.parts, part)
+		rest = rest[end+1:]
+	}
+	return t, nil
+}
+
+func parseURITemplateExpression(expr string) (uriTemplatePart, error) {
+	if expr == "" {
+		return uriTemplatePart{}, fmt.Errorf("empty expression")
+	}
+	var opChar byte
+	if strings.IndexByte("+#./;?&", expr[0]) >= 0 {
+		opChar = expr[0]
+		expr = expr[1:]
+	} else if strings.IndexByte("=,!@|", expr[0]) >= 0 {
+		return uriTemplatePart{}, fmt.Errorf("operator %q is reserved", expr[0])
+	}
+	part := uriTemplatePart{op: uriTemplateOps[opChar]}
+	for _, spec := range strings.Split(expr, ",") {
+		v := uriTemplateVar{name: spec}
+		if strings.HasSuffix(spec, "*") {
+			v.name, v.explode = strings.TrimSuffix(spec, "*"), true
+		} else if name, prefix, ok := strings.Cut(spec, ":"); ok {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n < 1 || n > 9999 || prefix[0] == '0' {
+				return uriTemplatePart{}, fmt.Errorf("invalid prefix %q in {%s}", prefix, expr)
+			}
+			v.name, v.prefix = name, n
+		}
+		if !validURITemplateVarName(v.name) {
+			return uriTemplatePart{}, fmt.Errorf("invalid variable name %q", v.name)
+		}
+		part.vars = append(part.vars, v)
+	}
+	return part, nil
+}
+
+// validURITemplateVarName checks the varname production: letters, digits,
+// underscores and percent-encoded triplets, separated by single dots.
+func validURITemplateVarName(name string) bool {
+	if name == "" || name[0] == '.' || name[len(name)-1] == '.' || strings.Contains(name, "..") {
+		return false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '_', c == '.':
+		case c == '%' && i+2 < len(name) && isHex(name[i+1]) && isHex(name[i+2]):
+			i += 2
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// String returns the template as written.
+func (t *URITemplate) String() string {
+	return t.raw
+}
+
+// Variables returns the names of the template variables in the order they
+// first appear.
+func (t *URITemplate) Variables() []string {
+	var names []string
+	seen := map[string]bool{}
+	for _, p := range t.parts {
+		for _, v := range p.vars {
+			if !seen[v.name] {
+				seen[v.name] = true
+				names = append(names, v.name)
+			}
+		}
+	}
+	return names
+}
+
+// Expand substitutes values into the template. A value is a string, a
+// []string list or a map[string]string of associative pairs; variables
+// without a value, or with an empty list or map, are left out as the RFC
+// specifies.
+func (t *URITemplate) Expand(values map[string]interface{}) string {
+	var b strings.Builder
+	for _, p := range t.parts {
+		if p.op == nil {
+			b.WriteString(p.literal)
+			continue
+		}
+		first := true
+		for _, v := range p.vars {
+			expanded, ok := expandURITemplateVar(p.op, v, values[v.name])
+			if !ok {
+				continue
+			}
+			if first {
+				b.WriteString(p.op.first)
+				first = false
+			} else {
+				b.WriteString(p.op.sep)
+			}
+			b.WriteString(expanded)
+		}
+	}
+	return b.String()
+}
+
+func expandURITemplateVar(op *uriTemplateOp, v uriTemplateVar, value interface{}) (string, bool) {
+	encode := func(s string) string { return encodeURITemplateValue(s, op.reserved) }
+	named := func(name, s string) string {
+		if !op.named {
+			return s
+		}
+		if s == "" {
+			return name + op.ifEmpty
+		}
+		return name + "=" + s
+	}
+	switch value := value.(type) {
+	case string:
+		if v.prefix > 0 && utf8.RuneCountInString(value) > v.prefix {
+			value = string([]rune(value)[:v.prefix])
+		}
+		return named(v.name, encode(value)), true
+	case []string:
+		if len(value) == 0 {
+			return "", false
+		}
+		items := make([]string, len(value))
+		for i, item := range value {
+			items[i] = encode(item)
+			if v.explode {
+				items[i] = named(v.name, items[i])
+			}
+		}
+		if v.explode {
+			return strings.Join(items, op.sep), true
+		}
+		return named(v.name, strings.Join(items, ",")), true
+	case map[string]string:
+		if len(value) == 0 {
+			return "", false
+		}
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var items []string
+		for _, k := range keys {
+			if v.explode {
+				items = append(items, encode(k)+"="+encode(value[k]))
+			} else {
+				items = append(items, encode(k), encode(value[k]))
+			}
+		}
+		if v.explode {
+			return strings.Join(items, op.sep), true
+		}
+		return named(v.name, strings.Join(items, ",")), true
+	}
+	return "", false
+}
+
+// encodeURITemplateValue percent-encodes the characters a value may not
+// contain: everything but unreserved characters, and also reserved ones and
+// existing percent-encoded triplets when reserved is set.
+func encodeURITemplateValue(s string, reserved bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', strings.IndexByte("-._~", c) >= 0:
+			b.WriteByte(c)
+		case reserved && strings.IndexByte(":/?#[]@!$&'()*+,;=", c) >= 0:
+			b.WriteByte(c)
+		case reserved && c == '%' && i+2 < len(s) && isHex(s[i+1]) && isHex(s[i+2]):
+			b.WriteString(s[i : i+3])
+			i += 2
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// Match reports whether uri is an expansion of the template and returns the
+// decoded value of each variable found in it. Exploded variables are
+// returned as the raw text they expanded to.
+func (t *URITemplate) Match(uri string) (map[string]string, bool) {
+	if t.match == nil && t.matchErr == nil {
+		t.match, t.matchVars, t.matchErr = t.compileMatch()
+	}
+	if t.matchErr != nil {
+		return nil, false
+	}
+	m := t.match.FindStringSubmatch(uri)
+	if m == nil {
+		return nil, false
+	}
+	values := map[string]string{}
+	for i, name := range t.matchVars {
+		if m[i+1] == "" {
+			continue
+		}
+		if decoded, err := url.PathUnescape(m[i+1]); err == nil {
+			values[name] = decoded
+		} else {
+			values[name] = m[i+1]
+		}
+	}
+	return values, true
+}
+
+// compileMatch builds a regular expression matching the expansions of the
+// template, with one group per variable, and returns the variable names of
+// the groups.
+func (t *URITemplate) compileMatch() (*regexp.Regexp, []string, error) {
+	var b strings.Builder
+	var names []string
+	b.WriteString("^")
+	for _, p := range t.parts {
+		if p.op == nil {
+			b.WriteString(regexp.QuoteMeta(p.literal))
+			continue
+		}
+		// Characters a single value may span, by operator.
+		value := `[^/?#&,;=.]*`
+		switch {
+		case p.op.reserved && p.op.first == "#":
+			value = `.*`
+		case p.op.reserved:
+			value = `[^?#]*`
+		case p.op.first == "/":
+			value = `[^/?#]*`
+		case p.op.first == "":
+			value = `[^/?#,]*`
+		}
+		for i, v := range p.vars {
+			names = append(names, v.name)
+			lead := regexp.QuoteMeta(p.op.sep)
+			if i == 0 {
+				lead = regexp.QuoteMeta(p.op.first)
+			}
+			switch {
+			case p.op.named && v.explode:
+				b.WriteString(`((?:[?&;][^#]*)?)`)
+			case p.op.named:
+				if p.op.first == "?" || p.op.first == "&" {
+					lead = `[?&]`
+				}
+				fmt.Fprintf(&b, `(?:%s%s(?:=([^&#;/]*))?)?`, lead, regexp.QuoteMeta(v.name))
+			case v.explode:
+				fmt.Fprintf(&b, `(?:%s(%s(?:%s%s)*))?`, lead, value, regexp.QuoteMeta(p.op.sep), value)
+			default:
+				fmt.Fprintf(&b, `(?:%s(%s))?`, lead, value)
+			}
+		}
+	}
+	b.WriteString("$")
+	re, err := regexp.Compile(b.String())
+	return re, names, err
+}
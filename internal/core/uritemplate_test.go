@@ -0,0 +1,116 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestURITemplate_Expand(t *testing.T) {
+	values := map[string]interface{}{
+		"var":   "value",
+		"hello": "Hello World!",
+		"path":  "/foo/bar",
+		"x":     "1024",
+		"y":     "768",
+		"empty": "",
+		"list":  []string{"red", "green", "blue"},
+		"keys":  map[string]string{"semi": ";", "dot": ".", "comma": ","},
+	}
+	// Examples from RFC 6570 section 3.2.
+	cases := []struct{ template, want string }{
+		{"{var}", "value"},
+		{"{hello}", "Hello%20World%21"},
+		{"{var:3}", "val"},
+		{"{+path}/here", "/foo/bar/here"},
+		{"{+hello}", "Hello%20World!"},
+		{"{#path:6}/here", "#/foo/b/here"},
+		{"map?{x,y}", "map?1024,768"},
+		{"{list}", "red,green,blue"},
+		{"{list*}", "red,green,blue"},
+		{"{keys}", "comma,%2C,dot,.,semi,%3B"},
+		{"{keys*}", "comma=%2C,dot=.,semi=%3B"},
+		{"X{.var}", "X.value"},
+		{"X{.list*}", "X.red.green.blue"},
+		{"{/var,x}/here", "/value/1024/here"},
+		{"{/list*}", "/red/green/blue"},
+		{"{;x,y,empty}", ";x=1024;y=768;empty"},
+		{"{?x,y,empty}", "?x=1024&y=768&empty="},
+		{"{?list*}", "?list=red&list=green&list=blue"},
+		{"{?keys*}", "?comma=%2C&dot=.&semi=%3B"},
+		{"?fixed=yes{&x}", "?fixed=yes&x=1024"},
+		{"{?undef}", ""},
+		{"file:///{path}", "file:///%2Ffoo%2Fbar"},
+	}
+	for _, c := range cases {
+		tmpl, err := ParseURITemplate(c.template)
+		if err != nil {
+			t.Errorf("%s: %v", c.template, err)
+			continue
+		}
+		if got := tmpl.Expand(values); got != c.want {
+			t.Errorf("%s: got %q, want %q", c.template, got, c.want)
+		}
+	}
+}
+
+func TestParseURITemplate_Errors(t *testing.T) {
+	for _, s := range []string{"file:///{path", "{}", "{=x}", "{a b}", "{x:0}", "{x:abc}", "a b{x}", "{x..y}"} {
+		if _, err := ParseURITemplate(s); err == nil {
+			t.Errorf("%q: expected an error", s)
+		}
+	}
+}
+
+func TestURITemplate_Variables(t *testing.T) {
+	tmpl, err := ParseURITemplate("db://{table}/{id}{?fields,id}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := tmpl.Variables(); !reflect.DeepEqual(got, []string{"table", "id", "fields"}) {
+		t.Errorf("unexpected variables %v", got)
+	}
+}
+
+func TestURITemplate_Match(t *testing.T) {
+	cases := []struct {
+		template, uri string
+		want          map[string]string
+	}{
+		{"file:///{path}", "file:///notes.txt", map[string]string{"path": "notes.txt"}},
+		{"file:///{path}", "file:///a%20b", map[string]string{"path": "a b"}},
+		{"file:///{+path}", "file:///docs/a/b.md", map[string]string{"path": "docs/a/b.md"}},
+		{"db://{table}/{id}", "db://users/42", map[string]string{"table": "users", "id": "42"}},
+		{"api://users{/id}", "api://users", map[string]string{}},
+		{"search://{?q,limit}", "search://?q=go&limit=5", map[string]string{"q": "go", "limit": "5"}},
+	}
+	for _, c := range cases {
+		tmpl, err := ParseURITemplate(c.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, ok := tmpl.Match(c.uri)
+		if !ok || !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s matching %s: got %v %v, want %v", c.template, c.uri, got, ok, c.want)
+		}
+	}
+
+	for _, c := range []struct{ template, uri string }{
+		{"file:///{path}", "file:///docs/a.md"},
+		{"db://{table}/{id}", "db://users"},
+		{"file:///{path}", "http://example.com/x"},
+	} {
+		tmpl, _ := ParseURITemplate(c.template)
+		if _, ok := tmpl.Match(c.uri); ok {
+			t.Errorf("%s should not match %s", c.template, c.uri)
+		}
+	}
+}
+
+func TestURITemplate_ExpandThenMatch(t *testing.T) {
+	tmpl, _ := ParseURITemplate("notes://{folder}/{name}")
+	uri := tmpl.Expand(map[string]interface{}{"folder": "work", "name": "todo list"})
+	got, ok := tmpl.Match(uri)
+	if !ok || got["folder"] != "work" || got["name"] != "todo list" {
+		t.Errorf("round trip of %s: got %v %v", uri, got, ok)
+	}
+}
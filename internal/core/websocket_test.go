@@ -0,0 +1,164 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// startWebSocketServer upgrades incoming connections and hands them to handle.
+func startWebSocketServer(t *testing.T, handle func(ws *wsConn)) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			http.Error(w, "upgrade required", http.StatusUpgradeRequired)
+			return
+		}
+		conn, rw, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			t.Errorf("hijack failed: %v", err)
+			return
+		}
+		defer conn.Close()
+		rw.WriteString("HTTP/1.1 101 Switching Protocols\r\nUpgrade: websocket\r\nConnection: Upgrade\r\n")
+		rw.WriteString("Sec-WebSocket-Accept: " + websocketAccept(r.Header.Get("Sec-WebSocket-Key")) + "\r\n\r\n")
+		rw.Flush()
+		handle(&wsConn{conn: conn, reader: rw.Reader})
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http")
+}
+
+func TestWebSocketTransport_Call(t *testing.T) {
+	url := startWebSocketServer(t, func(ws *wsConn) {
+		for {
+			msg, err := ws.readMessage()
+			if err != nil {
+				return
+			}
+			var req Request
+			if err := json.Unmarshal(msg, &req); err != nil || req.Method == "" {
+				continue
+			}
+			// Ping the client first to check that control frames are handled
+			// between messages.
+			ws.writeFrame(wsPing, []byte("hi"))
+			data, _ := json.Marshal(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: req.Method})
+			// Send the response in two fragments.
+			half := len(data) / 2
+			ws.conn.Write(append([]byte{wsText, byte(half)}, data[:half]...))
+			rest := data[half:]
+			ws.conn.Write(append([]byte{0x80 | wsContinuation, byte(len(rest))}, rest...))
+		}
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tr, err := DialWebSocket(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	c := NewMCPClientWithTransport(tr, io.Discard)
+	defer c.Close()
+
+	resp, err := c.CallContext(ctx, "tools/list", nil, nil)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if resp.Result != "tools/list" {
+		t.Errorf("unexpected result %v", resp.Result)
+	}
+}
+
+func TestWebSocketTransport_ServerClose(t *testing.T) {
+	url := startWebSocketServer(t, func(ws *wsConn) {
+		ws.writeFrame(wsClose, []byte{0x03, 0xE8})
+		ws.readMessage()
+	})
+	tr, err := DialWebSocket(context.Background(), url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer tr.Close()
+	if _, err := tr.Receive(); err != io.EOF {
+		t.Errorf("expected EOF, got %v", err)
+	}
+}
+
+func TestWebSocketTransport_EmptyFrame(t *testing.T) {
+	url := startWebSocketServer(t, func(ws *wsConn) {
+		msg, err := ws.readMessage()
+		if err != nil {
+			return
+		}
+		var req Request
+		json.Unmarshal(msg, &req)
+		// An empty text frame must neither crash the client nor be taken
+		// for the response.
+		ws.writeFrame(wsText, nil)
+		data, _ := json.Marshal(&Response{JSONRPC: JSONRPCVersion, ID: req.ID, Result: "ok"})
+		ws.writeFrame(wsText, data)
+		ws.readMessage()
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tr, err := DialWebSocket(ctx, url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	c := NewMCPClientWithTransport(tr, io.Discard)
+	defer c.Close()
+
+	resp, err := c.CallContext(ctx, "ping", nil, nil)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if resp.Result != "ok" {
+		t.Errorf("unexpected result %v", resp.Result)
+	}
+}
+
+func TestDialWebSocket_RejectsPlainHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+	url := "ws" + strings.TrimPrefix(srv.URL, "http")
+	if _, err := DialWebSocket(context.Background(), url, nil); err == nil {
+		t.Error("expected handshake error")
+	}
+	if _, err := DialWebSocket(context.Background(), srv.URL, nil); err == nil {
+		t.Error("expected scheme error")
+	}
+}
+
+func TestWebSocketFrameRoundTrip(t *testing.T) {
+	payload := []byte(strings.Repeat("x", 70000))
+	url := startWebSocketServer(t, func(ws *wsConn) {
+		msg, err := ws.readMessage()
+		if err != nil {
+			return
+		}
+		ws.writeFrame(wsText, msg)
+		ws.readMessage()
+	})
+	tr, err := DialWebSocket(context.Background(), url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer tr.Close()
+	if err := tr.Send(context.Background(), payload); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	got, err := tr.Receive()
+	if err != nil {
+		t.Fatalf("receive failed: %v", err)
+	}
+	if string(got) != string(payload) {
+		t.Errorf("payload mismatch: got %d bytes", len(got))
+	}
+}
@@ -0,0 +1,188 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// yamlReserved are the plain scalars YAML resolves to something other than a
+// string, compared in lower case.
+var yamlReserved = map[string]bool{
+	"": true, "~": true, "null": true, "true": true, "false": true,
+	"yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+	".inf": true, "-.inf": true, "+.inf": true, ".nan": true,
+}
+
+var yamlNumber = regexp.MustCompile(`^[-+]?(\.[0-9]|[0-9])[0-9_]*(\.[0-9_]*)?([eE][-+]?[0-9]+)?$|^0[xXoObB]`)
+
+// MarshalYAML encodes v, any value encodable as JSON, as a block-style YAML
+// document. Object keys are sorted and multi-line strings are written as
+// literal blocks.
+func MarshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var doc interface{}
+	if err := dec.Decode(&doc); err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	switch doc.(type) {
+	case map[string]interface{}, []interface{}:
+		if isEmptyYAMLCollection(doc) {
+			b.WriteString(yamlScalar(doc, 0) + "\n")
+		} else {
+			writeYAMLNode(&b, doc, 0)
+		}
+	default:
+		// Literal blocks must be indented even at the top level.
+		b.WriteString(yamlScalar(doc, 2) + "\n")
+	}
+	return []byte(b.String()), nil
+}
+
+// writeYAMLNode writes a non-empty object or array whose lines are indented
+// by indent spaces.
+func writeYAMLNode(b *strings.Builder, v interface{}, indent int) {
+	pad := strings.Repeat(" ", indent)
+	switch t := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			b.WriteString(pad + yamlKey(k) + ":")
+			writeYAMLValue(b, t[k], indent+2)
+		}
+	case []interface{}:
+		for _, item := range t {
+			b.WriteString(pad + "-")
+			if m, ok := item.(map[string]interface{}); ok && len(m) > 0 {
+				// The first member follows the dash, the others align with it.
+				var inner strings.Builder
+				writeYAMLNode(&inner, m, indent+2)
+				b.WriteString(" " + strings.TrimPrefix(inner.String(), pad+"  "))
+				continue
+			}
+			writeYAMLValue(b, item, indent+2)
+		}
+	}
+}
+
+// writeYAMLValue writes the value of a member or item, after its key or dash.
+func writeYAMLValue(b *strings.Builder, v interface{}, indent int) {
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if !isEmptyYAMLCollection(v) {
+			b.WriteString("\n")
+			writeYAMLNode(b, v, indent)
+			return
+		}
+	}
+	b.WriteString(" " + yamlScalar(v, indent) + "\n")
+}
+
+func isEmptyYAMLCollection(v interface{}) bool {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		return len(t) == 0
+	case []interface{}:
+		return len(t) == 0
+	}
+	return false
+}
+
+// yamlScalar formats a scalar or an empty collection.
+func yamlScalar(v interface{}, indent int) string {
+	switch t := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return fmt.Sprint(t)
+	case json.Number:
+		return t.String()
+	case string:
+		return yamlString(t, indent)
+	case map[string]interface{}:
+		return "{}"
+	case []interface{}:
+		return "[]"
+	}
+	return fmt.Sprint(v)
+}
+
+// yamlString formats a string as a plain scalar when YAML reads it back
+// unchanged, as a literal block when it spans lines and is quoted otherwise.
+func yamlString(s string, indent int) string {
+	if strings.Contains(s, "\n") && literalBlockSafe(s) {
+		chomp := "-"
+		body := s
+		if strings.HasSuffix(s, "\n") {
+			chomp = ""
+			body = strings.TrimSuffix(s, "\n")
+		}
+		pad := strings.Repeat(" ", indent)
+		lines := strings.Split(body, "\n")
+		for i, line := range lines {
+			if line != "" {
+				lines[i] = pad + line
+			}
+		}
+		return "|" + chomp + "\n" + strings.Join(lines, "\n")
+	}
+	if plainSafe(s) {
+		return s
+	}
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// yamlKey formats an object key, quoting it unless it is a plain scalar.
+func yamlKey(k string) string {
+	if plainSafe(k) {
+		return k
+	}
+	data, _ := json.Marshal(k)
+	return string(data)
+}
+
+// literalBlockSafe reports whether s survives a literal block: its
+// indentation must not be ambiguous and it must not end with blank lines.
+func literalBlockSafe(s string) bool {
+	if strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") || strings.ContainsAny(s, "\r\t") {
+		return false
+	}
+	for _, r := range s {
+		if r < ' ' && r != '\n' {
+			return false
+		}
+	}
+	return true
+}
+
+func plainSafe(s string) bool {
+	if yamlReserved[strings.ToLower(s)] || yamlNumber.MatchString(s) {
+		return false
+	}
+	if strings.TrimSpace(s) != s || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return false
+	}
+	if strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return false
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f || r == '\uFEFF' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,77 @@
+package core
+
+import "testing"
+
+func TestMarshalYAML(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   interface{}
+		want string
+	}{
+		{"scalar", "hello", "hello\n"},
+		{"empty object", map[string]interface{}{}, "{}\n"},
+		{"multi-line scalar", "a\nb", "|-\n  a\n  b\n"},
+		{
+			"nested",
+			map[string]interface{}{
+				"tools": []interface{}{
+					map[string]interface{}{"name": "echo", "inputSchema": map[string]interface{}{"type": "object", "required": []interface{}{"message"}}},
+					map[string]interface{}{"name": "noop", "tags": []interface{}{}},
+				},
+				"count": 2,
+				"ratio": 0.5,
+				"ok":    true,
+				"none":  nil,
+			},
+			`count: 2
+none: null
+ok: true
+ratio: 0.5
+tools:
+  - inputSchema:
+      required:
+        - message
+      type: object
+    name: echo
+  - name: noop
+    tags: []
+`,
+		},
+		{
+			"quoting",
+			map[string]interface{}{"s": []interface{}{"true", "42", "1.5e3", "", " padded", "key: value", "# comment", "-dash", "plain text", "tab\there", "0x1F", "yes"}},
+			`s:
+  - "true"
+  - "42"
+  - "1.5e3"
+  - ""
+  - " padded"
+  - "key: value"
+  - "# comment"
+  - "-dash"
+  - plain text
+  - "tab\there"
+  - "0x1F"
+  - "yes"
+`,
+		},
+		{
+			"literal blocks",
+			map[string]interface{}{"text": "line 1\n\nline 3\n", "strip": "a\nb", "unsafe": " lead\nx"},
+			"strip: |-\n  a\n  b\nt" + "ext: |\n  line 1\n\n  line 3\nunsafe: \" lead\\nx\"\n",
+		},
+		{
+			"nested lists",
+			[]interface{}{[]interface{}{1, 2}, []interface{}{}},
+			"-\n  - 1\n  - 2\n- []\n",
+		},
+	} {
+		got, err := MarshalYAML(tc.in)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		if string(got) != tc.want {
+			t.Errorf("%s:\ngot:\n%s\nwant:\n%s", tc.name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,748 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// YAMLError reports a YAML document that cannot be parsed, with the 1-based
+// line and column of the problem.
+type YAMLError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *YAMLError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// textPosition is the 1-based line and column of a value in a document.
+type textPosition struct {
+	line, column int
+}
+
+// UnmarshalYAML decodes a YAML document into the values encoding/json would
+// produce for the equivalent JSON: maps, slices, strings, float64 numbers,
+// booleans and nil. The supported subset covers block and flow collections,
+// plain, quoted and block scalars and comments; anchors, aliases, tags,
+// complex keys and multiple documents are rejected.
+func UnmarshalYAML(data []byte) (interface{}, error) {
+	v, _, err := decodeYAML(data)
+	return v, err
+}
+
+// decodeYAML decodes a YAML document and returns, along with its value, the
+// position of every value keyed by its JSONPath.
+func decodeYAML(data []byte) (interface{}, map[string]textPosition, error) {
+	p, err := newYAMLParser(string(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	v, err := p.parseNode(0, "$")
+	if err != nil {
+		return nil, nil, err
+	}
+	if ln, ok := p.next(); ok {
+		return nil, nil, p.errorAt(ln, ln.indent, "unexpected content")
+	}
+	return v, p.positions, nil
+}
+
+// yamlLine is a line of a YAML document with its indentation removed.
+type yamlLine struct {
+	num    int
+	indent int
+	text   string
+	// parent is the indentation the content of a block scalar starting on
+	// this line must exceed.
+	parent int
+	// tab is set when the indentation contains a tab.
+	tab bool
+}
+
+type yamlParser struct {
+	lines     []yamlLine
+	i         int
+	positions map[string]textPosition
+}
+
+func newYAMLParser(doc string) (*yamlParser, error) {
+	p := &yamlParser{positions: map[string]textPosition{}}
+	raw := strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n")
+	started := false
+	for n, s := range raw {
+		trimmed := strings.TrimLeft(s, " ")
+		indent := len(s) - len(trimmed)
+		ln := yamlLine{num: n + 1, indent: indent, parent: indent, text: strings.TrimRight(trimmed, " \t")}
+		if strings.HasPrefix(trimmed, "\t") {
+			ln.tab = true
+			ln.text = strings.TrimSpace(trimmed)
+		}
+		if indent == 0 && significant(ln.text) {
+			switch {
+			case strings.HasPrefix(ln.text, "%"):
+				if started {
+					return nil, p.errorAt(ln, 0, "directives must precede the document")
+				}
+				continue
+			case ln.text == "---" || strings.HasPrefix(ln.text, "--- "):
+				if started {
+					return nil, p.errorAt(ln, 0, "multiple documents are not supported")
+				}
+				started = true
+				rest := strings.TrimLeft(ln.text[3:], " ")
+				if rest == "" || strings.HasPrefix(rest, "#") {
+					continue
+				}
+				ln.indent = len(ln.text) - len(rest)
+				ln.parent = -1
+				ln.text = rest
+			case ln.text == "...":
+				p.lines = append(p.lines, yamlLine{num: ln.num})
+				return p, p.checkEnd(raw[n+1:], n+1)
+			}
+			started = true
+		}
+		p.lines = append(p.lines, ln)
+	}
+	return p, nil
+}
+
+// checkEnd verifies that only comments follow the document end marker.
+func (p *yamlParser) checkEnd(rest []string, offset int) error {
+	for n, s := range rest {
+		if text := strings.TrimSpace(s); significant(text) {
+			return p.errorAt(yamlLine{num: offset + n + 1}, 0, "multiple documents are not supported")
+		}
+	}
+	return nil
+}
+
+// significant reports whether a line holds more than a comment.
+func significant(text string) bool {
+	return text != "" && !strings.HasPrefix(text, "#")
+}
+
+func (p *yamlParser) errorAt(ln yamlLine, col int, format string, args ...interface{}) error {
+	return &YAMLError{Line: ln.num, Column: col + 1, Message: fmt.Sprintf(format, args...)}
+}
+
+// next skips blank and comment lines and returns the next line holding
+// content, without consuming it.
+func (p *yamlParser) next() (yamlLine, bool) {
+	for p.i < len(p.lines) {
+		if significant(p.lines[p.i].text) {
+			return p.lines[p.i], true
+		}
+		p.i++
+	}
+	return yamlLine{}, false
+}
+
+// isSequenceItem reports whether a line starts a block sequence item.
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// parseNode parses the block node starting on the next line indented by at
+// least minIndent; it is null when there is none.
+func (p *yamlParser) parseNode(minIndent int, path string) (interface{}, error) {
+	ln, ok := p.next()
+	if !ok || ln.indent < minIndent {
+		return nil, nil
+	}
+	if ln.tab {
+		return nil, p.errorAt(ln, ln.indent, "tabs are not allowed in indentation")
+	}
+	if isSequenceItem(ln.text) {
+		return p.parseSequence(ln.indent, path)
+	}
+	if _, _, ok, err := p.splitKey(ln); err != nil {
+		return nil, err
+	} else if ok {
+		return p.parseMapping(ln.indent, path)
+	}
+	return p.parseValue(ln, ln.indent, ln.parent, path)
+}
+
+// parseSequence parses the block sequence whose items start at indent.
+func (p *yamlParser) parseSequence(indent int, path string) (interface{}, error) {
+	items := []interface{}{}
+	for idx := 0; ; idx++ {
+		ln, ok := p.next()
+		if !ok || ln.indent < indent {
+			break
+		}
+		if ln.indent > indent {
+			return nil, p.errorAt(ln, ln.indent, "unexpected indentation")
+		}
+		if ln.tab {
+			return nil, p.errorAt(ln, ln.indent, "tabs are not allowed in indentation")
+		}
+		if !isSequenceItem(ln.text) {
+			break
+		}
+		if _, ok := p.positions[path]; !ok {
+			p.positions[path] = textPosition{ln.num, ln.indent + 1}
+		}
+		itemPath := fmt.Sprintf("%s[%d]", path, idx)
+		rest := strings.TrimLeft(ln.text[1:], " ")
+		if !significant(rest) {
+			p.i++
+			p.positions[itemPath] = textPosition{ln.num, ln.indent + 1}
+			item, err := p.parseNode(indent+1, itemPath)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+		// Parse the rest of the line as a node indented to its column, so that
+		// the keys of a mapping started on it line up with the first one.
+		offset := len(ln.text) - len(rest)
+		p.lines[p.i] = yamlLine{num: ln.num, indent: ln.indent + offset, text: rest, parent: indent}
+		item, err := p.parseNode(ln.indent+offset, itemPath)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// parseMapping parses the block mapping whose keys start at indent.
+func (p *yamlParser) parseMapping(indent int, path string) (interface{}, error) {
+	m := map[string]interface{}{}
+	first := true
+	for {
+		ln, ok := p.next()
+		if !ok || ln.indent < indent {
+			break
+		}
+		if ln.indent > indent {
+			return nil, p.errorAt(ln, ln.indent, "unexpected indentation")
+		}
+		if ln.tab {
+			return nil, p.errorAt(ln, ln.indent, "tabs are not allowed in indentation")
+		}
+		if isSequenceItem(ln.text) {
+			if first {
+				break
+			}
+			return nil, p.errorAt(ln, ln.indent, "expected a mapping key, got a sequence item")
+		}
+		key, rest, ok, err := p.splitKey(ln)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, p.errorAt(ln, ln.indent, "expected a mapping key")
+		}
+		if _, ok := p.positions[path]; !ok {
+			p.positions[path] = textPosition{ln.num, ln.indent + 1}
+		}
+		first = false
+		if _, dup := m[key]; dup {
+			return nil, p.errorAt(ln, ln.indent, "duplicate key %q", key)
+		}
+		childPath := memberPath(path, key)
+		var v interface{}
+		if !significant(rest) {
+			p.i++
+			p.positions[childPath] = textPosition{ln.num, ln.indent + 1}
+			if n, ok := p.next(); ok && n.indent == indent && isSequenceItem(n.text) {
+				v, err = p.parseSequence(indent, childPath)
+			} else {
+				v, err = p.parseNode(indent+1, childPath)
+			}
+		} else {
+			col := ln.indent + len(ln.text) - len(rest)
+			v, err = p.parseValue(yamlLine{num: ln.num, indent: col, text: rest}, col, indent, childPath)
+		}
+		if err != nil {
+			return nil, err
+		}
+		m[key] = v
+	}
+	return m, nil
+}
+
+// splitKey splits a mapping entry into its key and the text of its value. ok
+// is false when the line is not a mapping entry.
+func (p *yamlParser) splitKey(ln yamlLine) (key, rest string, ok bool, err error) {
+	text := ln.text
+	switch text[0] {
+	case '"', '\'':
+		s, n, err := p.quoted(ln, ln.indent, text)
+		if err != nil {
+			return "", "", false, err
+		}
+		after := strings.TrimLeft(text[n:], " ")
+		if after == ":" || strings.HasPrefix(after, ": ") {
+			return s, strings.TrimLeft(after[1:], " "), true, nil
+		}
+		return "", "", false, nil
+	case '[', '{', '#', '|', '>', '&', '*', '!', '%', '@', '`':
+		return "", "", false, nil
+	case '?':
+		if text == "?" || strings.HasPrefix(text, "? ") {
+			return "", "", false, p.errorAt(ln, ln.indent, "complex mapping keys are not supported")
+		}
+	}
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '#' && i > 0 && text[i-1] == ' ':
+			return "", "", false, nil
+		case text[i] == ':' && (i+1 == len(text) || text[i+1] == ' '):
+			return strings.TrimRight(text[:i], " "), strings.TrimLeft(text[i+1:], " "), true, nil
+		}
+	}
+	return "", "", false, nil
+}
+
+// parseValue parses the value starting at column col of a line: a block
+// scalar whose content is indented deeper than parent, a flow collection, a
+// quoted or a plain scalar.
+func (p *yamlParser) parseValue(ln yamlLine, col, parent int, path string) (interface{}, error) {
+	p.positions[path] = textPosition{ln.num, col + 1}
+	text := ln.text
+	switch text[0] {
+	case '&', '*':
+		return nil, p.errorAt(ln, col, "anchors and aliases are not supported")
+	case '!':
+		return nil, p.errorAt(ln, col, "tags are not supported")
+	case '|', '>':
+		return p.blockScalar(ln, col, parent)
+	case '[', '{':
+		return p.flow(ln, col, path)
+	case '"', '\'':
+		s, n, err := p.quoted(ln, col, text)
+		if err != nil {
+			return nil, err
+		}
+		if rest := strings.TrimLeft(text[n:], " "); significant(rest) {
+			return nil, p.errorAt(ln, col+len(text)-len(rest), "unexpected content after quoted string")
+		}
+		p.i++
+		return s, nil
+	}
+	p.i++
+	return resolveYAMLScalar(stripComment(text)), nil
+}
+
+// stripComment removes a trailing comment from a plain scalar.
+func stripComment(text string) string {
+	for i := 1; i < len(text); i++ {
+		if text[i] == '#' && (text[i-1] == ' ' || text[i-1] == '\t') {
+			return strings.TrimRight(text[:i], " \t")
+		}
+	}
+	return text
+}
+
+var (
+	yamlInt   = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloat = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolveYAMLScalar resolves a plain scalar following the YAML 1.2 core
+// schema.
+func resolveYAMLScalar(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if yamlInt.MatchString(s) || yamlFloat.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+	if len(s) > 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'o') {
+		base := 16
+		if s[1] == 'o' {
+			base = 8
+		}
+		if n, err := strconv.ParseInt(s[2:], base, 64); err == nil {
+			return float64(n)
+		}
+	}
+	return s
+}
+
+// quoted decodes the single- or double-quoted string at the start of text
+// and returns it with the number of bytes it spans.
+func (p *yamlParser) quoted(ln yamlLine, col int, text string) (string, int, error) {
+	quote := text[0]
+	var b strings.Builder
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == quote && quote == '\'':
+			if i+1 < len(text) && text[i+1] == '\'' {
+				b.WriteByte('\'')
+				i++
+				continue
+			}
+			return b.String(), i + 1, nil
+		case c == quote:
+			return b.String(), i + 1, nil
+		case c == '\\' && quote == '"':
+			if i+1 == len(text) {
+				return "", 0, p.errorAt(ln, col+i, "unterminated escape sequence")
+			}
+			i++
+			r, n, ok := yamlEscape(text[i:])
+			if !ok {
+				return "", 0, p.errorAt(ln, col+i-1, "invalid escape sequence \\%c", text[i])
+			}
+			b.WriteString(r)
+			i += n - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, p.errorAt(ln, col, "unterminated quoted string")
+}
+
+// yamlEscape decodes the escape sequence following a backslash and returns
+// its value and the number of bytes it spans.
+func yamlEscape(s string) (string, int, bool) {
+	simple := map[byte]string{
+		'0': "\x00", 'a': "\a", 'b': "\b", 't': "\t", '\t': "\t", 'n': "\n", 'v': "\v",
+		'f': "\f", 'r': "\r", 'e': "\x1b", ' ': " ", '"': "\"", '/': "/", '\\': "\\",
+		'N': "\u0085", '_': " ", 'L': " ", 'P': " ",
+	}
+	if r, ok := simple[s[0]]; ok {
+		return r, 1, true
+	}
+	width := map[byte]int{'x': 2, 'u': 4, 'U': 8}[s[0]]
+	if width == 0 || len(s) < width+1 {
+		return "", 0, false
+	}
+	n, err := strconv.ParseUint(s[1:width+1], 16, 32)
+	if err != nil || !utf8.ValidRune(rune(n)) {
+		return "", 0, false
+	}
+	return string(rune(n)), width + 1, true
+}
+
+// blockScalar parses a literal (|) or folded (>) block scalar whose header
+// starts at column col.
+func (p *yamlParser) blockScalar(ln yamlLine, col, parent int) (interface{}, error) {
+	header := stripComment(ln.text)
+	literal := header[0] == '|'
+	chomp := byte(0)
+	indent := 0
+	for i := 1; i < len(header); i++ {
+		switch c := header[i]; {
+		case (c == '-' || c == '+') && chomp == 0:
+			chomp = c
+		case c >= '1' && c <= '9' && indent == 0:
+			indent = parent + 1 + int(c-'1')
+			if parent < 0 {
+				indent = int(c - '0')
+			}
+		default:
+			return nil, p.errorAt(ln, col+i, "invalid block scalar header %q", header)
+		}
+	}
+	p.i++
+	var lines []string
+	for ; p.i < len(p.lines); p.i++ {
+		l := p.lines[p.i]
+		if l.text == "" {
+			lines = append(lines, "")
+			continue
+		}
+		if indent == 0 {
+			if l.indent <= parent {
+				break
+			}
+			indent = l.indent
+		}
+		if l.indent < indent {
+			break
+		}
+		if l.tab {
+			return nil, p.errorAt(l, l.indent, "tabs are not allowed in indentation")
+		}
+		lines = append(lines, strings.Repeat(" ", l.indent-indent)+l.text)
+	}
+	// Trailing blank lines belong to the scalar only when kept.
+	content := len(lines)
+	for content > 0 && lines[content-1] == "" {
+		content--
+	}
+	var b strings.Builder
+	for i, l := range lines[:content] {
+		if i > 0 {
+			prev := lines[i-1]
+			switch {
+			case literal || prev == "" || l == "" || strings.HasPrefix(l, " ") || strings.HasPrefix(prev, " "):
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+		}
+		b.WriteString(l)
+	}
+	s := b.String()
+	if !literal {
+		// A folded blank line stands for the line break it separates.
+		s = strings.ReplaceAll(s, "\n\n", "\n")
+	}
+	switch chomp {
+	case '-':
+	case '+':
+		s += "\n" + strings.Repeat("\n", len(lines)-content)
+	default:
+		if content > 0 {
+			s += "\n"
+		}
+	}
+	return s, nil
+}
+
+// flow parses a flow collection starting at column col, which may continue
+// on the following lines.
+func (p *yamlParser) flow(ln yamlLine, col int, path string) (interface{}, error) {
+	f := &yamlFlow{parser: p}
+	f.add(ln, col, ln.text)
+	depth := flowDepth(ln.text)
+	last := p.i
+	for depth > 0 {
+		last++
+		if last >= len(p.lines) {
+			return nil, p.errorAt(ln, col, "unterminated flow collection")
+		}
+		l := p.lines[last]
+		f.add(l, l.indent, "\n"+l.text)
+		depth += flowDepth(l.text)
+	}
+	v, err := f.value(path)
+	if err != nil {
+		return nil, err
+	}
+	f.space()
+	if f.pos < len(f.text) {
+		rest := f.text[f.pos:]
+		if significant(strings.TrimSpace(rest)) {
+			l, c := f.position(f.pos)
+			return nil, &YAMLError{Line: l, Column: c, Message: "unexpected content after flow collection"}
+		}
+	}
+	p.i = last + 1
+	return v, nil
+}
+
+// flowDepth returns the change in flow collection nesting over a line,
+// ignoring quoted strings and comments.
+func flowDepth(text string) int {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else if c == '\\' && quote == '"' {
+				i++
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' '):
+			return depth
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth
+}
+
+// yamlFlow parses flow collections, mapping offsets in the joined text of
+// their lines back to line and column.
+type yamlFlow struct {
+	parser   *yamlParser
+	text     string
+	pos      int
+	segments []flowSegment
+}
+
+type flowSegment struct {
+	offset, line, column int
+}
+
+func (f *yamlFlow) add(ln yamlLine, col int, text string) {
+	if strings.HasPrefix(text, "\n") {
+		f.segments = append(f.segments, flowSegment{len(f.text) + 1, ln.num, col})
+	} else {
+		f.segments = append(f.segments, flowSegment{len(f.text), ln.num, col})
+	}
+	f.text += text
+}
+
+// position returns the 1-based line and column of an offset.
+func (f *yamlFlow) position(offset int) (int, int) {
+	seg := f.segments[0]
+	for _, s := range f.segments {
+		if s.offset <= offset {
+			seg = s
+		}
+	}
+	return seg.line, seg.column + offset - seg.offset + 1
+}
+
+func (f *yamlFlow) fail(format string, args ...interface{}) error {
+	l, c := f.position(f.pos)
+	return &YAMLError{Line: l, Column: c, Message: fmt.Sprintf(format, args...)}
+}
+
+// space skips whitespace, line breaks and comments.
+func (f *yamlFlow) space() {
+	for f.pos < len(f.text) {
+		switch c := f.text[f.pos]; {
+		case c == ' ' || c == '\t' || c == '\n':
+			f.pos++
+		case c == '#' && (f.pos == 0 || strings.ContainsRune(" \t\n", rune(f.text[f.pos-1]))):
+			for f.pos < len(f.text) && f.text[f.pos] != '\n' {
+				f.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (f *yamlFlow) value(path string) (interface{}, error) {
+	f.space()
+	if f.pos >= len(f.text) {
+		return nil, f.fail("unexpected end of flow collection")
+	}
+	l, c := f.position(f.pos)
+	f.parser.positions[path] = textPosition{l, c}
+	switch ch := f.text[f.pos]; ch {
+	case '[':
+		f.pos++
+		items := []interface{}{}
+		for {
+			f.space()
+			if f.pos < len(f.text) && f.text[f.pos] == ']' {
+				f.pos++
+				return items, nil
+			}
+			item, err := f.value(fmt.Sprintf("%s[%d]", path, len(items)))
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			if err := f.separator(']'); err != nil {
+				return nil, err
+			}
+		}
+	case '{':
+		f.pos++
+		m := map[string]interface{}{}
+		for {
+			f.space()
+			if f.pos < len(f.text) && f.text[f.pos] == '}' {
+				f.pos++
+				return m, nil
+			}
+			keyStart := f.pos
+			key, err := f.scalar()
+			if err != nil {
+				return nil, err
+			}
+			ks, ok := key.(string)
+			if !ok {
+				ks = strings.TrimSpace(f.text[keyStart:f.pos])
+			}
+			if _, dup := m[ks]; dup {
+				f.pos = keyStart
+				return nil, f.fail("duplicate key %q", ks)
+			}
+			f.space()
+			var v interface{}
+			if f.pos < len(f.text) && f.text[f.pos] == ':' {
+				f.pos++
+				if v, err = f.value(memberPath(path, ks)); err != nil {
+					return nil, err
+				}
+			}
+			m[ks] = v
+			if err := f.separator('}'); err != nil {
+				return nil, err
+			}
+		}
+	case '&', '*':
+		return nil, f.fail("anchors and aliases are not supported")
+	case '!':
+		return nil, f.fail("tags are not supported")
+	case ']', '}', ',':
+		return nil, f.fail("unexpected %q", ch)
+	default:
+		return f.scalar()
+	}
+}
+
+// separator consumes the comma between flow entries, leaving the closing
+// character for the caller.
+func (f *yamlFlow) separator(closing byte) error {
+	f.space()
+	if f.pos >= len(f.text) {
+		return f.fail("expected %q", closing)
+	}
+	switch f.text[f.pos] {
+	case ',':
+		f.pos++
+		return nil
+	case closing:
+		return nil
+	default:
+		return f.fail("expected ',' or %q", closing)
+	}
+}
+
+// scalar parses a quoted or plain scalar inside a flow collection.
+func (f *yamlFlow) scalar() (interface{}, error) {
+	if c := f.text[f.pos]; c == '"' || c == '\'' {
+		l, col := f.position(f.pos)
+		end := strings.IndexByte(f.text[f.pos:], '\n')
+		if end < 0 {
+			end = len(f.text) - f.pos
+		}
+		s, n, err := f.parser.quoted(yamlLine{num: l}, col-1, f.text[f.pos:f.pos+end])
+		if err != nil {
+			return nil, err
+		}
+		f.pos += n
+		return s, nil
+	}
+	start := f.pos
+	for f.pos < len(f.text) {
+		c := f.text[f.pos]
+		if c == ',' || c == ']' || c == '}' || c == '\n' || c == '[' || c == '{' {
+			break
+		}
+		if c == ':' && (f.pos+1 == len(f.text) || strings.ContainsRune(" \n,]}", rune(f.text[f.pos+1]))) {
+			break
+		}
+		if c == '#' && f.pos > start && f.text[f.pos-1] == ' ' {
+			break
+		}
+		f.pos++
+	}
+	return resolveYAMLScalar(strings.TrimSpace(f.text[start:f.pos])), nil
+}
@@ -0,0 +1,159 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnmarshalYAML(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"empty", "", `null`},
+		{"comment only", "# nothing\n", `null`},
+		{"scalar", "hello\n", `"hello"`},
+		{"scalars", `
+s: plain text # comment
+n: 42
+f: -1.5e3
+hex: 0x1F
+t: true
+z: ~
+e:
+yes: yes
+q1: "tab\tand \u00e9"
+q2: 'it''s'
+num: "42"
+url: http://example.com/a#b
+`, `{"s":"plain text","n":42,"f":-1500,"hex":31,"t":true,"z":null,"e":null,"yes":"yes","q1":"tab\tand é","q2":"it's","num":"42","url":"http://example.com/a#b"}`},
+		{"nested", `
+---
+name: demo
+tools:
+  - name: echo
+    parameters:
+      - name: message
+        type: string
+        required: true
+  -
+    name: noop
+resources:
+- name: readme
+  uri: file:///README.md
+...
+`, `{"name":"demo","tools":[{"name":"echo","parameters":[{"name":"message","type":"string","required":true}]},{"name":"noop"}],"resources":[{"name":"readme","uri":"file:///README.md"}]}`},
+		{"nested sequences", "- - a\n  - b\n- c\n", `[["a","b"],"c"]`},
+		{"flow", `
+enum: [a, "b, c", 3]
+schema: {type: object, properties: {n: {type: integer}},
+  required: [n]}  # trailing
+empty: {}
+`, `{"enum":["a","b, c",3],"schema":{"type":"object","properties":{"n":{"type":"integer"}},"required":["n"]},"empty":{}}`},
+		{"block scalars", `
+literal: |
+  line 1
+
+  line 3
+strip: |-
+  a
+    b
+keep: |+
+  a
+
+folded: >
+  one
+  two
+
+  three
+after: x
+`, `{"literal":"line 1\n\nline 3\n","strip":"a\n  b","keep":"a\n\n","folded":"one two\nthree\n","after":"x"}`},
+		{"quoted keys", `"a: b": 1` + "\n'c': 2\n", `{"a: b":1,"c":2}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := UnmarshalYAML([]byte(tc.in))
+			if err != nil {
+				t.Fatalf("UnmarshalYAML: %v", err)
+			}
+			var want interface{}
+			if err := json.Unmarshal([]byte(tc.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %#v, want %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalYAML_RoundTrip(t *testing.T) {
+	var doc interface{}
+	if err := json.Unmarshal([]byte(`{"tools":[{"name":"echo","inputSchema":{"type":"object","required":["message"]}},{"name":"noop","tags":[]}],
+		"s":["true","42","1.5e3",""," padded","key: value","# comment","-dash","tab\there","0x1F"],
+		"text":"line 1\n\nline 3\n","strip":"a\nb","unsafe":" lead\nx","count":2,"none":null,"empty":{}}`), &doc); err != nil {
+		t.Fatal(err)
+	}
+	data, err := MarshalYAML(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := UnmarshalYAML(data)
+	if err != nil {
+		t.Fatalf("UnmarshalYAML: %v\n%s", err, data)
+	}
+	if !reflect.DeepEqual(got, doc) {
+		t.Errorf("round trip changed the document:\n%s\ngot %#v", data, got)
+	}
+}
+
+func TestUnmarshalYAML_Errors(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		in           string
+		line, column int
+		message      string
+	}{
+		{"bad indentation", "a:\n  b: 1\n    c: 2\n", 3, 5, "unexpected indentation"},
+		{"duplicate key", "a: 1\na: 2\n", 2, 1, `duplicate key "a"`},
+		{"tab", "a:\n\tb: 1\n", 2, 1, "tabs are not allowed in indentation"},
+		{"alias", "a: &x 1\n", 1, 4, "anchors and aliases are not supported"},
+		{"tag", "a: !!str 1\n", 1, 4, "tags are not supported"},
+		{"unterminated string", "a: \"open\n", 1, 4, "unterminated quoted string"},
+		{"unterminated flow", "a: [1, 2\nb: 3\n", 1, 4, "unterminated flow collection"},
+		{"flow separator", "a: {x: 1]}\n", 1, 9, `expected ',' or '}'`},
+		{"multiple documents", "a: 1\n---\nb: 2\n", 2, 1, "multiple documents are not supported"},
+		{"sequence in mapping", "a: 1\n- b\n", 2, 1, "expected a mapping key, got a sequence item"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := UnmarshalYAML([]byte(tc.in))
+			var yerr *YAMLError
+			if !errors.As(err, &yerr) {
+				t.Fatalf("expected a YAMLError, got %v", err)
+			}
+			if yerr.Line != tc.line || yerr.Column != tc.column || yerr.Message != tc.message {
+				t.Errorf("got %v, want line %d, column %d: %s", yerr, tc.line, tc.column, tc.message)
+			}
+		})
+	}
+}
+
+func TestDecodeYAML_Positions(t *testing.T) {
+	_, positions, err := decodeYAML([]byte("name: demo\ntools:\n  - name: echo\n    parameters: [{name: m, type: string}]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, want := range map[string]textPosition{
+		"$.name":                        {1, 7},
+		"$.tools":                       {2, 1},
+		"$.tools[0]":                    {3, 5},
+		"$.tools[0].name":               {3, 11},
+		"$.tools[0].parameters[0].type": {4, 34},
+	} {
+		if got, ok := positions[path]; !ok || got != want {
+			t.Errorf("position of %s = %v, want %v", path, got, want)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package generators
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	tmp "github.com/aawadall/mcpcli/internal/generators/templates"
+)
+
+// File is a rendered file, with its path relative to the project directory.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+// RenderTemplate renders an embedded template with the given data.
+func RenderTemplate(templatePath string, data interface{}) ([]byte, error) {
+	content, err := TemplatesFS.ReadFile(templatePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template %s: %w", templatePath, err)
+	}
+	tmpl, err := template.New(filepath.Base(templatePath)).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template %s: %w", templatePath, err)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, data); err != nil {
+		return nil, fmt.Errorf("failed to execute template %s: %w", templatePath, err)
+	}
+	return b.Bytes(), nil
+}
+
+// EntityFile renders the file holding the code of the named tool, resource or
+// capability of a project, as the generator for lang does. Prompts have no
+// file of their own and give nil.
+func EntityFile(lang string, data *core.TemplateData, kind, name string) (*File, error) {
+	var dir, ext string
+	switch lang {
+	case "go":
+		dir, ext = "internal", ".go"
+	case "javascript":
+		dir, ext = "src", ".js"
+	case "python":
+		dir, ext = "src", ".py"
+	case "java":
+		dir, ext = filepath.Join("src", "main", "java", filepath.Join(strings.Split(data.PackageName, ".")...)), ".java"
+	default:
+		return nil, fmt.Errorf("unsupported language: %s", lang)
+	}
+
+	var templatePath, subDir string
+	var entity interface{}
+	switch kind {
+	case "tool":
+		templatePath, subDir = tmp.ToolTemplate(lang), "tools"
+		for _, t := range data.Config.Tools {
+			if t.Name == name {
+				entity = t
+			}
+		}
+	case "resource":
+		templatePath, subDir = tmp.ResourceTemplate(lang), "resources"
+		for _, r := range data.Config.Resources {
+			if r.Name == name {
+				entity = r
+			}
+		}
+	case "capability":
+		templatePath, subDir = tmp.CapabilityTemplate(lang), "capabilities"
+		for _, c := range data.Config.Capabilities {
+			if c.Name == name {
+				entity = c
+			}
+		}
+	case "prompt":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown kind: %s", kind)
+	}
+	if entity == nil {
+		return nil, fmt.Errorf("%s %s is not part of the project", kind, name)
+	}
+
+	content, err := RenderTemplate(templatePath, entityData(lang, data, entity))
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: filepath.Join(dir, subDir, name+ext), Content: content}, nil
+}
+
+// entityData wraps an entity in the data its template expects, which differs
+// between languages.
+func entityData(lang string, data *core.TemplateData, entity interface{}) interface{} {
+	switch e := entity.(type) {
+	case core.Tool:
+		switch lang {
+		case "go":
+			return struct {
+				ModuleName string
+				Tool       core.Tool
+			}{data.ModuleName, e}
+		case "java":
+			return struct {
+				PackageName string
+				Tool        core.Tool
+			}{data.PackageName, e}
+		}
+		return struct{ Tool core.Tool }{e}
+	case core.Resource:
+		switch lang {
+		case "go":
+			return struct {
+				ModuleName string
+				Resource   core.Resource
+			}{data.ModuleName, e}
+		case "java":
+			return struct {
+				PackageName string
+				Resource    core.Resource
+			}{data.PackageName, e}
+		case "javascript":
+			return struct{ Item core.Resource }{e}
+		}
+		return struct{ Resource core.Resource }{e}
+	case core.Capability:
+		switch lang {
+		case "go":
+			return struct {
+				ModuleName string
+				Capability core.Capability
+			}{data.ModuleName, e}
+		case "java":
+			return struct {
+				PackageName string
+				Capability  core.Capability
+			}{data.PackageName, e}
+		}
+		return struct{ Capability core.Capability }{e}
+	}
+	return entity
+}
+
+// RegistryFile renders the registry listing the tools, resources or prompts
+// of a project. Capabilities have no registry and give nil.
+func RegistryFile(lang string, data *core.TemplateData, kind string) (*File, error) {
+	if kind == "capability" {
+		return nil, nil
+	}
+	templatePath := tmp.RegistryTemplate(lang, kind)
+	if templatePath == "" {
+		return nil, fmt.Errorf("no %s registry for language %s", kind, lang)
+	}
+	templates, err := tmp.BaseTemplateMap(lang, data)
+	if err != nil {
+		return nil, err
+	}
+	content, err := RenderTemplate(templatePath, data)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Path: templates[templatePath], Content: content}, nil
+}
@@ -4,7 +4,9 @@ import "github.com/aawadall/mcpcli/internal/core"
 
 // Generator interface for different language generators
 type Generator interface {
-	Generate(config *core.ProjectConfig) error
+	// Render renders in memory every file of the project described by
+	// config, with paths relative to the project directory, sorted by path.
+	Render(config *core.ProjectConfig) ([]File, error)
 	GetLanguage() string
 	GetSupportedTransports() []string
 }
@@ -1,8 +1,13 @@
 package generators
 
 import (
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"testing"
+
+	"github.com/aawadall/mcpcli/internal/core"
 )
 
 // TestGenerators verifies common behavior for all language generators using
@@ -14,10 +19,10 @@ func TestGenerators(t *testing.T) {
 		language   string
 		transports []string
 	}{
-		{"go", NewGolangGenerator(), "go", []string{"stdio", "rest", "websocket"}},
-		{"java", NewJavaGenerator(), "java", []string{"stdio", "rest", "websocket"}},
-		{"javascript", NewNodeGenerator(), "javascript", []string{"stdio", "rest", "websocket"}},
-		{"python", NewPythonGenerator(), "python", []string{"stdio", "rest", "websocket"}},
+		{"go", NewGolangGenerator(), "go", []string{"stdio", "rest", "streamable-http", "websocket"}},
+		{"java", NewJavaGenerator(), "java", []string{"stdio", "rest", "streamable-http", "websocket"}},
+		{"javascript", NewNodeGenerator(), "javascript", []string{"stdio", "rest", "streamable-http", "websocket"}},
+		{"python", NewPythonGenerator(), "python", []string{"stdio", "rest", "streamable-http", "websocket"}},
 	}
 
 	for _, tt := range tests {
@@ -41,3 +46,338 @@ func TestGenerators(t *testing.T) {
 		})
 	}
 }
+
+// TestGenerators_StreamableHTTP checks that every generator scaffolds a
+// Streamable HTTP entrypoint with session management.
+func TestGenerators_StreamableHTTP(t *testing.T) {
+	tests := []struct {
+		name       string
+		gen        Generator
+		entrypoint string
+	}{
+		{"go", NewGolangGenerator(), "main.go"},
+		{"java", NewJavaGenerator(), "Main.java"},
+		{"javascript", NewNodeGenerator(), "index.js"},
+		{"python", NewPythonGenerator(), "main.py"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &core.ProjectConfig{Name: "streamer", Language: tt.name, Transport: "streamable-http", Output: tmpDir}
+			generate(t, tt.gen, cfg)
+			var content string
+			filepath.Walk(tmpDir, func(path string, info os.FileInfo, err error) error {
+				if err == nil && info.Name() == tt.entrypoint {
+					data, _ := os.ReadFile(path)
+					content = string(data)
+				}
+				return nil
+			})
+			if content == "" {
+				t.Fatalf("entrypoint %s not generated", tt.entrypoint)
+			}
+			for _, want := range []string{"Mcp-Session-Id", "DELETE", "text/event-stream", "streamable-http mode"} {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s missing %q", tt.entrypoint, want)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerators_Prompts checks that every generator scaffolds a prompt
+// registry holding the configured prompts and handlers serving them.
+func TestGenerators_Prompts(t *testing.T) {
+	tests := []struct {
+		name     string
+		gen      Generator
+		registry string
+		handler  string
+	}{
+		{"go", NewGolangGenerator(), "registry.go", "mcp.go"},
+		{"java", NewJavaGenerator(), "PromptRegistry.java", "MCPHandler.java"},
+		{"javascript", NewNodeGenerator(), "registry.js", "mcp.js"},
+		{"python", NewPythonGenerator(), "registry.py", "mcp.py"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &core.ProjectConfig{Name: "prompter", Language: tt.name, Transport: "stdio", Output: tmpDir, Prompts: []core.Prompt{{
+				Name:        "review",
+				Description: "Reviews code",
+				Arguments:   []core.PromptArgument{{Name: "code", Description: "The code to review", Required: true}},
+			}}}
+			generate(t, tt.gen, cfg)
+			files := map[string]string{}
+			filepath.Walk(tmpDir, func(path string, info os.FileInfo, err error) error {
+				if err == nil && (info.Name() == tt.handler || info.Name() == tt.registry && strings.Contains(path, "prompts")) {
+					data, _ := os.ReadFile(path)
+					files[info.Name()] = string(data)
+				}
+				return nil
+			})
+			for _, want := range []string{`"review"`, `"Reviews code"`, `"code"`, `"The code to review"`} {
+				if !strings.Contains(files[tt.registry], want) {
+					t.Errorf("%s missing %q", tt.registry, want)
+				}
+			}
+			for _, want := range []string{"prompts/list", "prompts/get"} {
+				if !strings.Contains(files[tt.handler], want) {
+					t.Errorf("%s missing %q", tt.handler, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerators_ResourceTemplates(t *testing.T) {
+	tests := []struct {
+		name     string
+		gen      Generator
+		registry string
+		handler  string
+	}{
+		{"go", NewGolangGenerator(), "registry.go", "mcp.go"},
+		{"java", NewJavaGenerator(), "Registry.java", "MCPHandler.java"},
+		{"javascript", NewNodeGenerator(), "registry.js", "mcp.js"},
+		{"python", NewPythonGenerator(), "registry.py", "mcp.py"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &core.ProjectConfig{Name: "templated", Language: tt.name, Transport: "stdio", Output: tmpDir, Resources: []core.Resource{
+				{Name: "readme", Type: "filesystem"},
+				{Name: "notes", Type: "filesystem", URITemplate: "notes:///{folder}/{name}"},
+			}}
+			generate(t, tt.gen, cfg)
+			files := map[string]string{}
+			filepath.Walk(tmpDir, func(path string, info os.FileInfo, err error) error {
+				if err == nil && (info.Name() == tt.handler || info.Name() == tt.registry && strings.Contains(path, "resources")) {
+					// The Go server dispatches in pkg/mcp/mcp.go and handles in
+					// internal/handlers/mcp.go; check them together.
+					data, _ := os.ReadFile(path)
+					files[info.Name()] += string(data)
+				}
+				return nil
+			})
+			for _, want := range []string{`"readme"`, `"notes:///{folder}/{name}"`} {
+				if !strings.Contains(files[tt.registry], want) {
+					t.Errorf("%s missing %q", tt.registry, want)
+				}
+			}
+			for _, want := range []string{"resources/templates/list", "-32002"} {
+				if !strings.Contains(files[tt.handler], want) {
+					t.Errorf("%s missing %q", tt.handler, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGenerators_Subscriptions(t *testing.T) {
+	tests := []struct {
+		name          string
+		gen           Generator
+		subscriptions string
+		handler       string
+		notifies      string
+		registry      string
+		updates       string
+	}{
+		{"go", NewGolangGenerator(), filepath.Join("pkg", "mcp", "mcp.go"), filepath.Join("internal", "handlers", "mcp.go"), `h.server.NotifyResourceUpdated(uri)`,
+			filepath.Join("internal", "tools", "registry.go"), `Updates:     []string{"notes"},`},
+		{"java", NewJavaGenerator(), filepath.Join("src", "main", "java", "subscribed", "handlers", "Subscriptions.java"), filepath.Join("src", "main", "java", "subscribed", "handlers", "MCPHandler.java"), `Subscriptions.notifyResourceUpdated(uri)`,
+			filepath.Join("src", "main", "java", "subscribed", "tools", "ToolRegistry.java"), `return Arrays.asList("notes");`},
+		{"javascript", NewNodeGenerator(), filepath.Join("src", "handlers", "subscriptions.js"), filepath.Join("src", "handlers", "mcp.js"), "notifyResourceUpdated(uri)",
+			filepath.Join("src", "tools", "registry.js"), `updates: ["notes"],`},
+		{"python", NewPythonGenerator(), filepath.Join("src", "handlers", "subscriptions.py"), filepath.Join("src", "handlers", "mcp.py"), "notify_resource_updated(uri)",
+			filepath.Join("src", "tools", "registry.py"), `"updates": ["notes"],`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &core.ProjectConfig{Name: "subscribed", Language: tt.name, Transport: "streamable-http", Output: tmpDir,
+				Tools:     []core.Tool{{Name: "save", Updates: []string{"notes"}}},
+				Resources: []core.Resource{{Name: "notes", Type: "filesystem"}}}
+			generate(t, tt.gen, cfg)
+			data, err := os.ReadFile(filepath.Join(tmpDir, tt.subscriptions))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{"resources/subscribe", "notifications/resources/updated", "notifications/resources/list_changed"} {
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s missing %q", tt.subscriptions, want)
+				}
+			}
+			for path, want := range map[string]string{tt.handler: tt.notifies, tt.registry: tt.updates, filepath.Join("configs", "mcp-config.json"): `"updates": ["notes"]`} {
+				data, err := os.ReadFile(filepath.Join(tmpDir, path))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.Contains(string(data), want) {
+					t.Errorf("%s missing %q", path, want)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerators_ToolSchemas checks that every generator scaffolds typed
+// tool arguments, argument validation and a tool registry advertising the
+// input and output schemas built from the tool parameters.
+func TestGenerators_ToolSchemas(t *testing.T) {
+	tests := []struct {
+		name     string
+		gen      Generator
+		registry string
+		tool     string
+		typed    []string
+	}{
+		{"go", NewGolangGenerator(), filepath.Join("internal", "tools", "registry.go"), filepath.Join("internal", "tools", "search.go"),
+			[]string{"type searchArguments struct", "MaxResults int64 `json:\"max_results,omitempty\"`", "Query string `json:\"query\"`"}},
+		{"java", NewJavaGenerator(), filepath.Join("src", "main", "java", "searcher", "tools", "ToolRegistry.java"), filepath.Join("src", "main", "java", "searcher", "tools", "search.java"),
+			[]string{"public static class Arguments", "public final Long maxResults;", `args.getString("query")`}},
+		{"javascript", NewNodeGenerator(), filepath.Join("src", "tools", "registry.js"), filepath.Join("src", "tools", "search.js"),
+			[]string{"export class searchArguments", "/** @type {number|undefined} */", `this.maxResults = args["max_results"];`}},
+		{"python", NewPythonGenerator(), filepath.Join("src", "tools", "registry.py"), filepath.Join("src", "tools", "search.py"),
+			[]string{"class searchArguments:", "query: str  # What to find", "max_results: Optional[int] = None"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			cfg := &core.ProjectConfig{Name: "searcher", Language: tt.name, Transport: "stdio", Output: tmpDir, Tools: []core.Tool{{
+				Name:        "search",
+				Description: "Searches documents",
+				Parameters: []core.ToolParameter{
+					{Name: "query", Type: "string", Description: "What to find", Required: true},
+					{Name: "max_results", Type: "integer", Default: 10},
+				},
+				OutputSchema: []byte(`{"type":"object"}`),
+			}}}
+			generate(t, tt.gen, cfg)
+			registry, err := os.ReadFile(filepath.Join(tmpDir, tt.registry))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range []string{`"search"`, `"Searches documents"`, `\"max_results\":{\"default\":10,\"type\":\"integer\"}`, `\"required\":[\"query\"]`, `{\"type\":\"object\"}`} {
+				if tt.name == "javascript" {
+					want = strings.ReplaceAll(want, `\"`, `"`)
+				}
+				if !strings.Contains(string(registry), want) {
+					t.Errorf("%s missing %q", tt.registry, want)
+				}
+			}
+			tool, err := os.ReadFile(filepath.Join(tmpDir, tt.tool))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.typed {
+				if !strings.Contains(string(tool), want) {
+					t.Errorf("%s missing %q", tt.tool, want)
+				}
+			}
+		})
+	}
+}
+
+// TestRenderProject checks that every generator renders each file once,
+// sorted by path, including a file for each tool, resource and capability.
+func TestRenderProject(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  Generator
+	}{
+		{"go", NewGolangGenerator()},
+		{"java", NewJavaGenerator()},
+		{"javascript", NewNodeGenerator()},
+		{"python", NewPythonGenerator()},
+	}
+
+	for _, tt := range tests {
+		for _, transport := range tt.gen.GetSupportedTransports() {
+			t.Run(tt.name+"/"+transport, func(t *testing.T) {
+				cfg := &core.ProjectConfig{Name: "render", Language: tt.name, Transport: transport, Output: "render", Docker: true,
+					Tools:        []core.Tool{{Name: "search", Parameters: []core.ToolParameter{{Name: "q", Type: "string", Required: true}}}},
+					Resources:    []core.Resource{{Name: "notes", Type: "filesystem"}},
+					Prompts:      []core.Prompt{{Name: "summarize"}},
+					Capabilities: []core.Capability{{Name: "logging", Enabled: true}},
+				}
+				files, err := tt.gen.Render(cfg)
+				if err != nil {
+					t.Fatalf("render: %v", err)
+				}
+				rendered := map[string]string{}
+				for i, f := range files {
+					if i > 0 && files[i-1].Path >= f.Path {
+						t.Errorf("%s is not sorted after %s", f.Path, files[i-1].Path)
+					}
+					rendered[f.Path] = string(f.Content)
+				}
+				data := cfg.GetTemplateData()
+				for _, entity := range [][2]string{{"tool", "search"}, {"resource", "notes"}, {"capability", "logging"}} {
+					file, err := EntityFile(tt.gen.GetLanguage(), data, entity[0], entity[1])
+					if err != nil {
+						t.Fatal(err)
+					}
+					if content, ok := rendered[file.Path]; !ok || content != string(file.Content) {
+						t.Errorf("%s file %s was not rendered", entity[0], file.Path)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestRenderProject_Collisions checks that an entity named after a file of the
+// project is rejected instead of replacing that file.
+func TestRenderProject_Collisions(t *testing.T) {
+	tests := []struct {
+		name string
+		gen  Generator
+		cfg  core.ProjectConfig
+		want string
+	}{
+		{"go", NewGolangGenerator(), core.ProjectConfig{Resources: []core.Resource{{Name: "registry", Type: "filesystem"}}},
+			filepath.Join("internal", "resources", "registry.go")},
+		{"java", NewJavaGenerator(), core.ProjectConfig{Resources: []core.Resource{{Name: "Registry", Type: "filesystem"}}},
+			filepath.Join("src", "main", "java", "render", "resources", "Registry.java")},
+		{"javascript", NewNodeGenerator(), core.ProjectConfig{Tools: []core.Tool{{Name: "registry"}}},
+			filepath.Join("src", "tools", "registry.js")},
+		{"python", NewPythonGenerator(), core.ProjectConfig{Tools: []core.Tool{{Name: "registry"}}},
+			filepath.Join("src", "tools", "registry.py")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := tt.cfg
+			cfg.Name, cfg.Language, cfg.Transport, cfg.Output = "render", tt.name, "stdio", "render"
+			_, err := tt.gen.Render(&cfg)
+			if err == nil || !strings.Contains(err.Error(), "would overwrite "+tt.want) {
+				t.Errorf("Render() = %v, want an error about overwriting %s", err, tt.want)
+			}
+		})
+	}
+}
+
+// generate renders a project and writes it to cfg.Output.
+func generate(t *testing.T, gen Generator, cfg *core.ProjectConfig) {
+	t.Helper()
+	files, err := gen.Render(cfg)
+	if err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	for _, f := range files {
+		path := filepath.Join(cfg.Output, f.Path)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, f.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package generators
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/aawadall/mcpcli/internal/core"
@@ -23,40 +24,6 @@ func TestGoGenerator_GetSupportedTransports(t *testing.T) {
 	}
 }
 
-func TestGoGenerator_createDirectoryStructure(t *testing.T) {
-	tmpDir := t.TempDir()
-	g := NewGolangGenerator()
-	err := g.createDirectoryStructure(tmpDir)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	dirs := []string{
-		"cmd/server",
-		"internal/handlers",
-		"internal/resources",
-		"internal/tools",
-		"pkg/mcp",
-		"examples",
-		"configs",
-	}
-	for _, dir := range dirs {
-		path := filepath.Join(tmpDir, dir)
-		if stat, err := os.Stat(path); err != nil || !stat.IsDir() {
-			t.Errorf("expected directory %s to exist", path)
-		}
-	}
-}
-
-func TestGoGenerator_generateTemplate_invalidPath(t *testing.T) {
-	tmpDir := t.TempDir()
-	g := NewGolangGenerator()
-	data := &core.TemplateData{}
-	err := g.generateTemplate("nonexistent.tmpl", filepath.Join(tmpDir, "out.go"), data)
-	if err == nil {
-		t.Error("expected error for nonexistent template path")
-	}
-}
-
 func TestGoGenerator_GenerateWithExtras(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := &core.ProjectConfig{
@@ -70,9 +37,7 @@ func TestGoGenerator_GenerateWithExtras(t *testing.T) {
 	}
 
 	g := NewGolangGenerator()
-	if err := g.Generate(cfg); err != nil {
-		t.Fatalf("unexpected error generating project: %v", err)
-	}
+	generate(t, g, cfg)
 
 	expected := []string{
 		filepath.Join(tmpDir, "internal", "tools", "Hammer.go"),
@@ -85,3 +50,18 @@ func TestGoGenerator_GenerateWithExtras(t *testing.T) {
 		}
 	}
 }
+
+func TestGoGenerator_JSONRPCEnvelope(t *testing.T) {
+	tmpDir := t.TempDir()
+	cfg := &core.ProjectConfig{Name: "rpc", Language: "go", Transport: "stdio", Output: tmpDir}
+	generate(t, NewGolangGenerator(), cfg)
+	data, err := os.ReadFile(filepath.Join(tmpDir, "pkg", "mcp", "mcp.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{`json:"jsonrpc"`, `json:"data,omitempty"`, "func (r Request) IsNotification() bool"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("generated mcp.go missing %q", want)
+		}
+	}
+}
@@ -36,9 +36,7 @@ func TestJavaGenerator_GenerateBasic(t *testing.T) {
 	}
 
 	g := NewJavaGenerator()
-	if err := g.Generate(cfg); err != nil {
-		t.Fatalf("unexpected error generating project: %v", err)
-	}
+	generate(t, g, cfg)
 
 	pkg := cfg.GetTemplateData().PackageName
 	expected := []string{
@@ -71,9 +69,7 @@ func TestJavaGenerator_GenerateWithExtras(t *testing.T) {
 	}
 
 	g := NewJavaGenerator()
-	if err := g.Generate(cfg); err != nil {
-		t.Fatalf("unexpected error generating project: %v", err)
-	}
+	generate(t, g, cfg)
 
 	pkg := cfg.GetTemplateData().PackageName
 	expected := []string{
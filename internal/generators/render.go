@@ -0,0 +1,62 @@
+package generators
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	tmp "github.com/aawadall/mcpcli/internal/generators/templates"
+)
+
+// RenderProject renders in memory every file of a project in lang, sorted by
+// path: the base templates, then a file for each tool, resource and
+// capability. It fails when an entity file would replace another file of the
+// project, such as a tool named registry.
+func RenderProject(lang string, data *core.TemplateData) ([]File, error) {
+	templates, err := tmp.BaseTemplateMap(lang, data)
+	if err != nil {
+		return nil, err
+	}
+	contents := map[string][]byte{}
+	for templatePath, outputPath := range templates {
+		content, err := RenderTemplate(templatePath, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate %s: %w", outputPath, err)
+		}
+		contents[outputPath] = content
+	}
+
+	entity := func(kind, name string) error {
+		file, err := EntityFile(lang, data, kind, name)
+		if err != nil {
+			return fmt.Errorf("failed to generate %s file for %s: %w", kind, name, err)
+		}
+		if _, ok := contents[file.Path]; ok {
+			return fmt.Errorf("%s %s would overwrite %s, choose another name", kind, name, file.Path)
+		}
+		contents[file.Path] = file.Content
+		return nil
+	}
+	for _, t := range data.Config.Tools {
+		if err := entity("tool", t.Name); err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range data.Config.Resources {
+		if err := entity("resource", r.Name); err != nil {
+			return nil, err
+		}
+	}
+	for _, c := range data.Config.Capabilities {
+		if err := entity("capability", c.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]File, 0, len(contents))
+	for path, content := range contents {
+		files = append(files, File{Path: path, Content: content})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
@@ -36,7 +36,9 @@ func goTemplateMap(data *core.TemplateData) map[string]string {
 		"templates/go/stdio/internal/handlers/mcp.go.tmpl":                filepath.Join("internal", "handlers", "mcp.go"),
 		"templates/go/stdio/internal/resources/filesystem.go.tmpl":        filepath.Join("internal", "resources", "filesystem.go"),
 		"templates/go/stdio/internal/resources/registry.go.tmpl":          filepath.Join("internal", "resources", "registry.go"),
+		"templates/go/stdio/internal/prompts/registry.go.tmpl":            filepath.Join("internal", "prompts", "registry.go"),
 		"templates/go/stdio/internal/tools/calculator.go.tmpl":            filepath.Join("internal", "tools", "calculator.go"),
+		"templates/go/stdio/internal/tools/registry.go.tmpl":              filepath.Join("internal", "tools", "registry.go"),
 		"templates/go/stdio/pkg/mcp/client.go.tmpl":                       filepath.Join("pkg", "mcp", "client.go"),
 		"templates/go/stdio/pkg/mcp/mcp.go.tmpl":                          filepath.Join("pkg", "mcp", "mcp.go"),
 		"templates/go/stdio/README.md.tmpl":                               "README.md",
@@ -59,7 +61,10 @@ func nodeTemplateMap(data *core.TemplateData) map[string]string {
 		"templates/node/stdio/package.json.tmpl":                      "package.json",
 		fmt.Sprintf("templates/node/%s/src/index.js.tmpl", transport): filepath.Join("src", "index.js"),
 		"templates/node/stdio/src/handlers/mcp.js.tmpl":               filepath.Join("src", "handlers", "mcp.js"),
+		"templates/node/stdio/src/handlers/subscriptions.js.tmpl":     filepath.Join("src", "handlers", "subscriptions.js"),
 		"templates/node/stdio/src/resources/registry.js.tmpl":         filepath.Join("src", "resources", "registry.js"),
+		"templates/node/stdio/src/prompts/registry.js.tmpl":           filepath.Join("src", "prompts", "registry.js"),
+		"templates/node/stdio/src/tools/registry.js.tmpl":             filepath.Join("src", "tools", "registry.js"),
 		"templates/node/stdio/README.md.tmpl":                         "README.md",
 		"templates/node/stdio/configs/mcp-config.json.tmpl":           filepath.Join("configs", "mcp-config.json"),
 		"templates/node/stdio/examples/example.js.tmpl":               filepath.Join("examples", "example.js"),
@@ -79,7 +84,10 @@ func pythonTemplateMap(data *core.TemplateData) map[string]string {
 	m := map[string]string{
 		fmt.Sprintf("templates/python/%s/src/main.py.tmpl", transport): filepath.Join("src", "main.py"),
 		"templates/python/stdio/src/handlers/mcp.py.tmpl":              filepath.Join("src", "handlers", "mcp.py"),
+		"templates/python/stdio/src/handlers/subscriptions.py.tmpl":    filepath.Join("src", "handlers", "subscriptions.py"),
 		"templates/python/stdio/src/resources/registry.py.tmpl":        filepath.Join("src", "resources", "registry.py"),
+		"templates/python/stdio/src/prompts/registry.py.tmpl":          filepath.Join("src", "prompts", "registry.py"),
+		"templates/python/stdio/src/tools/registry.py.tmpl":            filepath.Join("src", "tools", "registry.py"),
 		"templates/python/stdio/README.md.tmpl":                        "README.md",
 		"templates/python/stdio/configs/mcp-config.json.tmpl":          filepath.Join("configs", "mcp-config.json"),
 		"templates/python/stdio/examples/example.py.tmpl":              filepath.Join("examples", "example.py"),
@@ -101,7 +109,10 @@ func javaTemplateMap(data *core.TemplateData) map[string]string {
 		"templates/java/stdio/pom.xml.tmpl":                                      "pom.xml",
 		fmt.Sprintf("templates/java/%s/src/main/java/Main.java.tmpl", transport): filepath.Join("src", "main", "java", pkgPath, "Main.java"),
 		"templates/java/stdio/src/main/java/handlers/MCPHandler.java.tmpl":       filepath.Join("src", "main", "java", pkgPath, "handlers", "MCPHandler.java"),
+		"templates/java/stdio/src/main/java/handlers/Subscriptions.java.tmpl":    filepath.Join("src", "main", "java", pkgPath, "handlers", "Subscriptions.java"),
 		"templates/java/stdio/src/main/java/resources/Registry.java.tmpl":        filepath.Join("src", "main", "java", pkgPath, "resources", "Registry.java"),
+		"templates/java/stdio/src/main/java/prompts/PromptRegistry.java.tmpl":    filepath.Join("src", "main", "java", pkgPath, "prompts", "PromptRegistry.java"),
+		"templates/java/stdio/src/main/java/tools/ToolRegistry.java.tmpl":        filepath.Join("src", "main", "java", pkgPath, "tools", "ToolRegistry.java"),
 		"templates/java/stdio/README.md.tmpl":                                    "README.md",
 		"templates/java/stdio/configs/mcp-config.json.tmpl":                      filepath.Join("configs", "mcp-config.json"),
 		"templates/java/stdio/examples/Example.java.tmpl":                        filepath.Join("examples", "Example.java"),
@@ -160,3 +171,32 @@ func CapabilityTemplate(lang string) string {
 		return ""
 	}
 }
+
+// RegistryTemplate returns the template path of the registry listing the
+// tools, resources or prompts of a project, given as "tool", "resource" or
+// "prompt".
+func RegistryTemplate(lang, kind string) string {
+	registries := map[string]map[string]string{
+		"go": {
+			"tool":     "templates/go/stdio/internal/tools/registry.go.tmpl",
+			"resource": "templates/go/stdio/internal/resources/registry.go.tmpl",
+			"prompt":   "templates/go/stdio/internal/prompts/registry.go.tmpl",
+		},
+		"javascript": {
+			"tool":     "templates/node/stdio/src/tools/registry.js.tmpl",
+			"resource": "templates/node/stdio/src/resources/registry.js.tmpl",
+			"prompt":   "templates/node/stdio/src/prompts/registry.js.tmpl",
+		},
+		"python": {
+			"tool":     "templates/python/stdio/src/tools/registry.py.tmpl",
+			"resource": "templates/python/stdio/src/resources/registry.py.tmpl",
+			"prompt":   "templates/python/stdio/src/prompts/registry.py.tmpl",
+		},
+		"java": {
+			"tool":     "templates/java/stdio/src/main/java/tools/ToolRegistry.java.tmpl",
+			"resource": "templates/java/stdio/src/main/java/resources/Registry.java.tmpl",
+			"prompt":   "templates/java/stdio/src/main/java/prompts/PromptRegistry.java.tmpl",
+		},
+	}
+	return registries[lang][kind]
+}
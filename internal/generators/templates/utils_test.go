@@ -33,6 +33,11 @@ func TestTemplateHelpers(t *testing.T) {
 		if CapabilityTemplate(lang) == "" {
 			t.Fatalf("capability template empty for %s", lang)
 		}
+		for _, kind := range []string{"tool", "resource", "prompt"} {
+			if _, ok := m[RegistryTemplate(lang, kind)]; !ok {
+				t.Fatalf("%s registry of %s is not a base template", kind, lang)
+			}
+		}
 	}
 }
 
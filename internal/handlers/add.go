@@ -0,0 +1,394 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/generators"
+)
+
+// AddKinds lists what `mcpcli add` can add to a project.
+var AddKinds = []string{"tool", "resource", "prompt", "capability"}
+
+// projectConfigPath is where generated projects keep their configuration,
+// relative to the project directory.
+var projectConfigPath = filepath.Join("configs", "mcp-config.json")
+
+// AddOptions contains the flags of `mcpcli add`. Only the entity matching
+// Kind is used.
+type AddOptions struct {
+	// Dir is the directory of the project.
+	Dir string
+	// Kind is one of AddKinds.
+	Kind       string
+	Tool       core.Tool
+	Resource   core.Resource
+	Prompt     core.Prompt
+	Capability core.Capability
+}
+
+// generatedConfig is the configs/mcp-config.json written by generate.
+type generatedConfig struct {
+	Name      string `json:"name"`
+	Language  string `json:"language"`
+	Transport struct {
+		Type string `json:"type"`
+	} `json:"transport"`
+	Docker       bool            `json:"docker"`
+	Examples     bool            `json:"examples"`
+	Tools        []core.Tool     `json:"tools"`
+	Resources    []core.Resource `json:"resources"`
+	Prompts      []core.Prompt   `json:"prompts"`
+	Capabilities map[string]struct {
+		Enabled bool `json:"enabled"`
+	} `json:"capabilities"`
+}
+
+// LoadProject reads the configuration of a project created by generate from
+// its configs/mcp-config.json.
+func LoadProject(dir string) (*core.ProjectConfig, error) {
+	path := filepath.Join(dir, projectConfigPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project config: %w", err)
+	}
+	var file generatedConfig
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, core.FormatJSONError(data, err, "failed to parse "+path)
+	}
+	if file.Language == "" {
+		return nil, fmt.Errorf("%s does not record the project language", path)
+	}
+	config := &core.ProjectConfig{
+		Name:      file.Name,
+		Language:  file.Language,
+		Transport: file.Transport.Type,
+		Docker:    file.Docker,
+		Examples:  file.Examples,
+		Output:    dir,
+		Tools:     file.Tools,
+		Resources: file.Resources,
+		Prompts:   file.Prompts,
+	}
+	names := make([]string, 0, len(file.Capabilities))
+	for name := range file.Capabilities {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		config.Capabilities = append(config.Capabilities, core.Capability{Name: name, Enabled: file.Capabilities[name].Enabled})
+	}
+	return config, nil
+}
+
+var entityNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// RunAdd adds a tool, resource, prompt or capability to an existing project.
+// It creates the entity's source file, regenerates the registry listing
+// entities of its kind and appends it to the project config. Existing files
+// are never overwritten: a registry with local changes is left as is and the
+// regenerated one is written next to it with a .new suffix. The files written
+// are recorded in the project's generation manifest.
+func RunAdd(opts *AddOptions, w io.Writer) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	config, err := LoadProject(opts.Dir)
+	if err != nil {
+		return err
+	}
+	generator, err := selectGenerator(config.Language)
+	if err != nil {
+		return err
+	}
+	lang := generator.GetLanguage()
+
+	updated := *config
+	name, entry, err := addEntity(&updated, opts)
+	if err != nil {
+		return err
+	}
+	oldData, newData := config.GetTemplateData(), updated.GetTemplateData()
+
+	entityFile, err := generators.EntityFile(lang, newData, opts.Kind, name)
+	if err != nil {
+		return err
+	}
+	if entityFile != nil {
+		if _, err := os.Stat(filepath.Join(opts.Dir, entityFile.Path)); err == nil {
+			return fmt.Errorf("%s already exists", entityFile.Path)
+		}
+	}
+	oldRegistry, err := generators.RegistryFile(lang, oldData, opts.Kind)
+	if err != nil {
+		return err
+	}
+	newRegistry, err := generators.RegistryFile(lang, newData, opts.Kind)
+	if err != nil {
+		return err
+	}
+	configData, err := os.ReadFile(filepath.Join(opts.Dir, projectConfigPath))
+	if err != nil {
+		return fmt.Errorf("failed to read project config: %w", err)
+	}
+	key, empty := opts.Kind+"s", "[]"
+	if opts.Kind == "capability" {
+		key, empty = "capabilities", "{}"
+	}
+	configData, err = appendToJSONCollection(configData, key, entry, empty)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", projectConfigPath, err)
+	}
+
+	var generated []generators.File
+	if entityFile != nil {
+		if err := writeProjectFile(opts.Dir, entityFile.Path, entityFile.Content); err != nil {
+			return err
+		}
+		fmt.Fprintf(w, "✅ Created %s\n", entityFile.Path)
+		generated = append(generated, *entityFile)
+	}
+	if newRegistry != nil {
+		updated, err := updateGeneratedFile(opts.Dir, oldRegistry, newRegistry, w)
+		if err != nil {
+			return err
+		}
+		if updated {
+			generated = append(generated, *newRegistry)
+		}
+	}
+	if err := writeProjectFile(opts.Dir, projectConfigPath, configData); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "✅ Updated %s\n", projectConfigPath)
+	generated = append(generated, generators.File{Path: projectConfigPath, Content: configData})
+	return recordGenerated(opts.Dir, generated...)
+}
+
+// addEntity validates the entity selected by opts, appends it to config and
+// returns its name and its entry in the project config.
+func addEntity(config *core.ProjectConfig, opts *AddOptions) (string, string, error) {
+	var name string
+	var entry interface{}
+	switch opts.Kind {
+	case "tool":
+		t := opts.Tool
+		name = t.Name
+		for _, existing := range config.Tools {
+			if existing.Name == name {
+				return "", "", fmt.Errorf("tool %s already exists", name)
+			}
+		}
+		if err := t.Validate(); err != nil {
+			return "", "", fmt.Errorf("invalid tool %s: %w", name, err)
+		}
+		if err := t.ValidateUpdates(config.Resources); err != nil {
+			return "", "", fmt.Errorf("invalid tool %s: %w", name, err)
+		}
+		config.Tools = append(append([]core.Tool{}, config.Tools...), t)
+		parameters := t.Parameters
+		if parameters == nil {
+			parameters = []core.ToolParameter{}
+		}
+		entry = struct {
+			Name         string               `json:"name"`
+			Description  string               `json:"description"`
+			Parameters   []core.ToolParameter `json:"parameters"`
+			OutputSchema json.RawMessage      `json:"outputSchema,omitempty"`
+			Updates      []string             `json:"updates,omitempty"`
+		}{t.Name, t.Description, parameters, t.OutputSchema, t.Updates}
+	case "resource":
+		r := opts.Resource
+		name = r.Name
+		for _, existing := range config.Resources {
+			if existing.Name == name {
+				return "", "", fmt.Errorf("resource %s already exists", name)
+			}
+		}
+		if !core.IsValidResourceType(r.Type) {
+			return "", "", fmt.Errorf("invalid resource type: %s, valid options are: %v", r.Type,
+				[]core.ResourceType{core.ResourceTypeDatabase, core.ResourceTypeFilesystem, core.ResourceTypeTime})
+		}
+		if r.URITemplate != "" {
+			if _, err := core.ParseURITemplate(r.URITemplate); err != nil {
+				return "", "", fmt.Errorf("invalid URI template: %w", err)
+			}
+		}
+		config.Resources = append(append([]core.Resource{}, config.Resources...), r)
+		entry = struct {
+			Name        string `json:"name"`
+			Type        string `json:"type"`
+			URITemplate string `json:"uriTemplate,omitempty"`
+		}{r.Name, r.Type, r.URITemplate}
+	case "prompt":
+		p := opts.Prompt
+		name = p.Name
+		for _, existing := range config.Prompts {
+			if existing.Name == name {
+				return "", "", fmt.Errorf("prompt %s already exists", name)
+			}
+		}
+		config.Prompts = append(append([]core.Prompt{}, config.Prompts...), p)
+		type argument struct {
+			Name        string `json:"name"`
+			Description string `json:"description"`
+			Required    bool   `json:"required"`
+		}
+		arguments := []argument{}
+		for _, a := range p.Arguments {
+			arguments = append(arguments, argument{a.Name, a.Description, a.Required})
+		}
+		entry = struct {
+			Name        string     `json:"name"`
+			Description string     `json:"description"`
+			Arguments   []argument `json:"arguments"`
+		}{p.Name, p.Description, arguments}
+	case "capability":
+		c := opts.Capability
+		name = c.Name
+		for _, existing := range config.Capabilities {
+			if existing.Name == name {
+				return "", "", fmt.Errorf("capability %s already exists", name)
+			}
+		}
+		config.Capabilities = append(append([]core.Capability{}, config.Capabilities...), c)
+		entry = c.Enabled
+	default:
+		return "", "", fmt.Errorf("invalid kind: %s, valid options are: %v", opts.Kind, AddKinds)
+	}
+	if name == "" {
+		return "", "", fmt.Errorf("%s name is required", opts.Kind)
+	}
+	if !entityNamePattern.MatchString(name) {
+		return "", "", fmt.Errorf("%s name %q must be a valid identifier", opts.Kind, name)
+	}
+	if opts.Kind == "capability" {
+		key, _ := json.Marshal(name)
+		return name, fmt.Sprintf(`%s: {"enabled": %t}`, key, entry), nil
+	}
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+	return name, string(data), nil
+}
+
+// updateGeneratedFile replaces a generated file with its regenerated content
+// when it still holds what was generated from the previous configuration.
+// Otherwise the file has local changes and the new content is written next
+// to it with a .new suffix. It reports whether the file holds the new
+// content.
+func updateGeneratedFile(dir string, previous, next *generators.File, w io.Writer) (bool, error) {
+	current, err := os.ReadFile(filepath.Join(dir, next.Path))
+	switch {
+	case os.IsNotExist(err) || (err == nil && bytes.Equal(current, previous.Content)):
+		if err := writeProjectFile(dir, next.Path, next.Content); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "✅ Updated %s\n", next.Path)
+	case err != nil:
+		return false, fmt.Errorf("failed to read %s: %w", next.Path, err)
+	case bytes.Equal(current, next.Content):
+	default:
+		if err := writeProjectFile(dir, next.Path+".new", next.Content); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "⚠️  %s has local changes and was left as is; merge %s.new into it\n", next.Path, next.Path)
+		return false, nil
+	}
+	return true, nil
+}
+
+func writeProjectFile(dir, path string, content []byte) error {
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		return fmt.Errorf("failed to create directory for %s: %w", path, err)
+	}
+	if err := os.WriteFile(full, content, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// appendToJSONCollection inserts item as the last element of the array, or
+// the last member of the object, held by a top-level key of a JSON object.
+// The key is created, holding empty, when missing or null. The rest of the
+// document, including its formatting, is left untouched.
+func appendToJSONCollection(data []byte, key, item, empty string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	lastEnd := int64(-1)
+	for dec.More() {
+		k, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		start := skipJSONSeparators(data, dec.InputOffset())
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		end := dec.InputOffset()
+		lastEnd = end
+		if k != key {
+			continue
+		}
+		indent := lineIndent(data, start) + "  "
+		entry := strings.ReplaceAll(item, "\n", "\n"+indent)
+		text := strings.TrimSpace(string(value))
+		switch {
+		case text == "null":
+			text = empty
+		case !strings.HasPrefix(text, empty[:1]):
+			return nil, fmt.Errorf("%s must be %s", key, map[string]string{"[]": "an array", "{}": "an object"}[empty])
+		}
+		inner := strings.TrimSpace(text[1 : len(text)-1])
+		var replaced string
+		if inner == "" {
+			replaced = text[:1] + "\n" + indent + entry + "\n" + indent[:len(indent)-2] + text[len(text)-1:]
+		} else {
+			last := strings.LastIndexFunc(text[:len(text)-1], func(r rune) bool { return !strings.ContainsRune(" \t\r\n", r) })
+			replaced = text[:last+1] + ",\n" + indent + entry + text[last+1:]
+		}
+		return append(append(append([]byte{}, data[:start]...), replaced...), data[end:]...), nil
+	}
+	// The key is missing: add it as the last member.
+	closing := bytes.LastIndexByte(data, '}')
+	if closing < 0 {
+		return nil, fmt.Errorf("expected a JSON object")
+	}
+	quoted, _ := json.Marshal(key)
+	member := fmt.Sprintf("  %s: %s\n    %s\n  %s\n", quoted, empty[:1], strings.ReplaceAll(item, "\n", "\n    "), empty[1:])
+	if lastEnd < 0 {
+		return append(append(append([]byte{}, data[:closing]...), "\n"+member...), data[closing:]...), nil
+	}
+	return append(append(append([]byte{}, data[:lastEnd]...), ",\n"+member...), bytes.TrimLeft(data[lastEnd:], " \t\r\n")...), nil
+}
+
+// skipJSONSeparators returns the offset of the next value after offset.
+func skipJSONSeparators(data []byte, offset int64) int64 {
+	for offset < int64(len(data)) && strings.IndexByte(" \t\r\n,:", data[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// lineIndent returns the leading whitespace of the line holding offset.
+func lineIndent(data []byte, offset int64) string {
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	end := start
+	for end < len(data) && (data[end] == ' ' || data[end] == '\t') {
+		end++
+	}
+	return string(data[start:end])
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// readTree returns the content of every file under dir by relative path,
+// leaving out the generation manifest.
+func readTree(t *testing.T, dir string) map[string]string {
+	t.Helper()
+	files := map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.IsDir() && info.Name() == manifestDir {
+			return filepath.SkipDir
+		}
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		rel, _ := filepath.Rel(dir, path)
+		files[rel] = string(data)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return files
+}
+
+func decodeConfig(t *testing.T, data string) interface{} {
+	t.Helper()
+	var v interface{}
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		t.Fatalf("invalid config: %v\n%s", err, data)
+	}
+	return v
+}
+
+func TestRunAdd_MatchesGenerate(t *testing.T) {
+	tool := core.Tool{Name: "search", Description: "Searches", Parameters: []core.ToolParameter{
+		{Name: "query", Type: "string", Required: true},
+		{Name: "limit", Type: "integer", Default: float64(10)},
+	}}
+	resource := core.Resource{Name: "notes", Type: "filesystem", URITemplate: "notes:///{name}"}
+	prompt := core.Prompt{Name: "summarize", Description: "Summarize", Arguments: []core.PromptArgument{{Name: "text", Required: true}}}
+	capability := core.Capability{Name: "logging", Enabled: true}
+
+	for _, lang := range []string{"golang", "javascript", "python", "java"} {
+		t.Run(lang, func(t *testing.T) {
+			dir := t.TempDir()
+			want := filepath.Join(dir, "want")
+			if err := GenerateProject(&GenerateOptions{Name: "proj", Language: lang, Transport: "stdio", Output: want,
+				Tools: []core.Tool{tool}, Resources: []core.Resource{resource}, Prompts: []core.Prompt{prompt}, Capabilities: []core.Capability{capability}}); err != nil {
+				t.Fatal(err)
+			}
+			got := filepath.Join(dir, "got")
+			if err := GenerateProject(&GenerateOptions{Name: "proj", Language: lang, Transport: "stdio", Output: got}); err != nil {
+				t.Fatal(err)
+			}
+			for _, opts := range []*AddOptions{
+				{Kind: "tool", Tool: tool},
+				{Kind: "resource", Resource: resource},
+				{Kind: "prompt", Prompt: prompt},
+				{Kind: "capability", Capability: capability},
+			} {
+				opts.Dir = got
+				var out bytes.Buffer
+				if err := RunAdd(opts, &out); err != nil {
+					t.Fatalf("add %s: %v", opts.Kind, err)
+				}
+				if strings.Contains(out.String(), "local changes") {
+					t.Errorf("add %s reported local changes:\n%s", opts.Kind, out.String())
+				}
+			}
+
+			wantFiles, gotFiles := readTree(t, want), readTree(t, got)
+			config := filepath.Join("configs", "mcp-config.json")
+			if !reflect.DeepEqual(decodeConfig(t, wantFiles[config]), decodeConfig(t, gotFiles[config])) {
+				t.Errorf("config differs:\nwant %s\ngot %s", wantFiles[config], gotFiles[config])
+			}
+			delete(wantFiles, config)
+			delete(gotFiles, config)
+			for path, content := range wantFiles {
+				if path == "README.md" || path == filepath.Join("examples", "example.go") {
+					// The README names the output directory and the example
+					// calls the first tool of the project.
+					continue
+				}
+				if gotFiles[path] != content {
+					t.Errorf("%s differs from a generated project:\nwant %s\ngot %s", path, content, gotFiles[path])
+				}
+			}
+			for path := range gotFiles {
+				if _, ok := wantFiles[path]; !ok {
+					t.Errorf("unexpected file %s", path)
+				}
+			}
+		})
+	}
+}
+
+func TestRunAdd_KeepsLocalChanges(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "golang", Transport: "stdio", Output: dir}); err != nil {
+		t.Fatal(err)
+	}
+	registry := filepath.Join(dir, "internal", "tools", "registry.go")
+	edited := []byte("// edited by hand\n")
+	if err := os.WriteFile(registry, edited, 0644); err != nil {
+		t.Fatal(err)
+	}
+	config := filepath.Join(dir, "configs", "mcp-config.json")
+	data, _ := os.ReadFile(config)
+	data = bytes.Replace(data, []byte(`"docker": false`), []byte(`"docker": false,
+  "custom": {"kept": true}`), 1)
+	if err := os.WriteFile(config, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := RunAdd(&AddOptions{Dir: dir, Kind: "tool", Tool: core.Tool{Name: "echo"}}, &out); err != nil {
+		t.Fatalf("RunAdd: %v", err)
+	}
+	if current, _ := os.ReadFile(registry); !bytes.Equal(current, edited) {
+		t.Errorf("edited registry was overwritten:\n%s", current)
+	}
+	if regenerated, err := os.ReadFile(registry + ".new"); err != nil || !strings.Contains(string(regenerated), `"echo"`) {
+		t.Errorf("expected the regenerated registry in registry.go.new, got %v", err)
+	}
+	if !strings.Contains(out.String(), "local changes") {
+		t.Errorf("expected a warning about local changes:\n%s", out.String())
+	}
+	updated, _ := os.ReadFile(config)
+	if !strings.Contains(string(updated), `"custom": {"kept": true}`) || !strings.Contains(string(updated), `"name": "echo"`) {
+		t.Errorf("unexpected config:\n%s", updated)
+	}
+
+	if err := RunAdd(&AddOptions{Dir: dir, Kind: "tool", Tool: core.Tool{Name: "echo"}}, &out); err == nil {
+		t.Error("expected an error adding a tool twice")
+	}
+}
+
+func TestRunAdd_Errors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "python", Transport: "stdio", Output: dir}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "src", "tools", "taken.py"), []byte("# mine\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		opts *AddOptions
+		want string
+	}{
+		{&AddOptions{Kind: "widget"}, "invalid kind"},
+		{&AddOptions{Kind: "tool"}, "name is required"},
+		{&AddOptions{Kind: "tool", Tool: core.Tool{Name: "my tool"}}, "must be a valid identifier"},
+		{&AddOptions{Kind: "prompt", Prompt: core.Prompt{Name: "code-review"}}, "must be a valid identifier"},
+		{&AddOptions{Kind: "tool", Tool: core.Tool{Name: "taken"}}, "taken.py already exists"},
+		{&AddOptions{Kind: "tool", Tool: core.Tool{Name: "t", Parameters: []core.ToolParameter{{Name: "a", Type: "text"}}}}, "invalid type"},
+		{&AddOptions{Kind: "tool", Tool: core.Tool{Name: "t", Updates: []string{"notes"}}}, "updates unknown resource notes"},
+		{&AddOptions{Kind: "resource", Resource: core.Resource{Name: "r", Type: "cloud"}}, "invalid resource type"},
+		{&AddOptions{Kind: "resource", Resource: core.Resource{Name: "r", Type: "time", URITemplate: "x://{"}}, "invalid URI template"},
+	} {
+		c.opts.Dir = dir
+		err := RunAdd(c.opts, &bytes.Buffer{})
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("RunAdd(%s) = %v, want error containing %q", c.opts.Kind, err, c.want)
+		}
+	}
+	if err := RunAdd(&AddOptions{Dir: t.TempDir(), Kind: "tool", Tool: core.Tool{Name: "t"}}, &bytes.Buffer{}); err == nil {
+		t.Error("expected an error outside a project")
+	}
+}
+
+func TestAppendToJSONCollection(t *testing.T) {
+	for _, c := range []struct {
+		name, in, key, item, empty, want string
+	}{
+		{"empty array", "{\n  \"tools\": []\n}", "tools", `"a"`, "[]", "{\n  \"tools\": [\n    \"a\"\n  ]\n}"},
+		{"array", "{\n  \"tools\": [\n    \"a\"\n  ],\n  \"x\": 1\n}", "tools", "{\n  \"b\": 2\n}", "[]", "{\n  \"tools\": [\n    \"a\",\n    {\n      \"b\": 2\n    }\n  ],\n  \"x\": 1\n}"},
+		{"object", "{\"caps\": {\"a\": 1}}", "caps", `"b": 2`, "{}", "{\"caps\": {\"a\": 1,\n  \"b\": 2}}"},
+		{"null", "{\n  \"tools\": null\n}", "tools", `"a"`, "[]", "{\n  \"tools\": [\n    \"a\"\n  ]\n}"},
+		{"missing", "{\n  \"x\": 1\n}\n", "tools", `"a"`, "[]", "{\n  \"x\": 1,\n  \"tools\": [\n    \"a\"\n  ]\n}\n"},
+	} {
+		got, err := appendToJSONCollection([]byte(c.in), c.key, c.item, c.empty)
+		if err != nil {
+			t.Errorf("%s: %v", c.name, err)
+			continue
+		}
+		if string(got) != c.want {
+			t.Errorf("%s: got\n%s\nwant\n%s", c.name, got, c.want)
+		}
+	}
+	if _, err := appendToJSONCollection([]byte(`{"tools": 1}`), "tools", `"a"`, "[]"); err == nil {
+		t.Error("expected an error when the key is not an array")
+	}
+}
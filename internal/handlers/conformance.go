@@ -0,0 +1,325 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// The conformance suite run by `mcpcli test --conformance`. Its version is
+// bumped whenever checks are added, removed or change their expectations, so
+// that reports from different mcpcli releases can be compared.
+const (
+	ConformanceSuiteName    = "mcp-conformance"
+	ConformanceSuiteVersion = "1.0.0"
+)
+
+// Specification sections referenced by the conformance checks.
+const (
+	specBase      = "https://modelcontextprotocol.io/specification/" + core.LatestProtocolVersion
+	specJSONRPC   = "https://www.jsonrpc.org/specification"
+	specLifecycle = specBase + "/basic/lifecycle#initialization"
+	specPing      = specBase + "/basic/utilities/ping"
+	specResources = specBase + "/server/resources#error-handling"
+	specTools     = specBase + "/server/tools#error-handling"
+)
+
+// notificationWait is how long the notification check waits for a reply
+// that must not come.
+const notificationWait = 500 * time.Millisecond
+
+// ConformanceCheck is one requirement of the MCP or JSON-RPC specifications
+// verified against a running server.
+type ConformanceCheck struct {
+	// ID names the check in reports, e.g. "jsonrpc/method-not-found".
+	ID string
+	// Description states the requirement.
+	Description string
+	// Spec links to the section of the specification defining it.
+	Spec string
+	// Requires is the server capability the check depends on, such as
+	// "resources" or "tools". The check is skipped when the server does not
+	// advertise it.
+	Requires string
+
+	run func(p *conformanceProbe) error
+}
+
+// conformanceChecks is the registry of checks, in execution order. Checks
+// waiting for a response without an ID must not run concurrently with other
+// such checks.
+var conformanceChecks = []ConformanceCheck{
+	{
+		ID:          "lifecycle/initialize",
+		Description: "initialize returns a supported protocolVersion and serverInfo",
+		Spec:        specLifecycle,
+		run:         checkInitialize,
+	},
+	{
+		ID:          "ping/empty-result",
+		Description: "ping is answered with an empty result",
+		Spec:        specPing,
+		run:         checkPing,
+	},
+	{
+		ID:          "jsonrpc/response-id",
+		Description: "responses echo the request ID, including string IDs",
+		Spec:        specJSONRPC + "#response_object",
+		run:         checkResponseID,
+	},
+	{
+		ID:          "jsonrpc/method-not-found",
+		Description: "unknown methods return error -32601",
+		Spec:        specJSONRPC + "#error_object",
+		run:         checkMethodNotFound,
+	},
+	{
+		ID:          "jsonrpc/notification-no-reply",
+		Description: "notifications are not answered",
+		Spec:        specJSONRPC + "#notification",
+		run:         checkNotificationNoReply,
+	},
+	{
+		ID:          "jsonrpc/parse-error",
+		Description: "malformed JSON returns error -32700 with a null ID",
+		Spec:        specJSONRPC + "#error_object",
+		run:         checkParseError,
+	},
+	{
+		ID:          "resources/read-missing-uri",
+		Description: "resources/read without uri returns error -32602",
+		Spec:        specResources,
+		Requires:    "resources",
+		run:         checkReadMissingURI,
+	},
+	{
+		ID:          "resources/read-unknown-uri",
+		Description: "resources/read of an unknown resource returns error -32002",
+		Spec:        specResources,
+		Requires:    "resources",
+		run:         checkReadUnknownURI,
+	},
+	{
+		ID:          "tools/call-unknown-tool",
+		Description: "tools/call of an unknown tool returns error -32602",
+		Spec:        specTools,
+		Requires:    "tools",
+		run:         checkCallUnknownTool,
+	},
+}
+
+// ConformanceChecks returns the checks of the conformance suite in the order
+// they run.
+func ConformanceChecks() []ConformanceCheck {
+	return append([]ConformanceCheck(nil), conformanceChecks...)
+}
+
+// conformanceProbe is handed to each check. It records the messages the check
+// exchanged so that they appear in the report when it fails.
+type conformanceProbe struct {
+	client   *core.MCPClient
+	opts     *TestOptions
+	id       *int
+	init     *core.InitializeResult
+	request  *core.Request
+	response *core.Response
+	sent     string
+}
+
+// call sends a request with the next numeric ID.
+func (p *conformanceProbe) call(method string, params map[string]interface{}) (*core.Response, error) {
+	id := *p.id
+	*p.id++
+	return p.callWithID(method, params, id)
+}
+
+func (p *conformanceProbe) callWithID(method string, params map[string]interface{}, id interface{}) (*core.Response, error) {
+	p.request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: method, Params: params, ID: id}
+	ctx, cancel := requestContext(p.opts)
+	defer cancel()
+	resp, err := p.client.CallContext(ctx, method, params, id)
+	p.response = resp
+	return resp, err
+}
+
+// raw sends message as is and waits up to wait for a response without an ID.
+func (p *conformanceProbe) raw(message string, wait time.Duration) (*core.Response, error) {
+	p.sent = message
+	ctx, cancel := context.WithTimeout(context.Background(), wait)
+	defer cancel()
+	resp, err := p.client.CallRaw(ctx, []byte(message), nil)
+	p.response = resp
+	return resp, err
+}
+
+// expectError checks that resp carries the given error code.
+func expectError(resp *core.Response, err error, code int) error {
+	if err != nil {
+		return err
+	}
+	if resp.Error == nil {
+		return fmt.Errorf("expected error %d, got a result", code)
+	}
+	if resp.Error.Code != code {
+		return fmt.Errorf("expected error %d, got %d (%s)", code, resp.Error.Code, resp.Error.Message)
+	}
+	return nil
+}
+
+// runConformanceChecks runs every registered check and records one case per
+// check. Checks are skipped when initialization failed.
+func runConformanceChecks(client *core.MCPClient, opts *TestOptions, init *core.InitializeResult, initErr error, id *int, suite *TestSuite) {
+	for _, check := range conformanceChecks {
+		tc := &TestCase{Name: check.ID, Spec: check.Spec, Message: check.Description}
+		switch {
+		case initErr != nil && check.ID == "lifecycle/initialize":
+			tc.Status = StatusFailed
+			tc.Failures = []string{initErr.Error()}
+		case initErr != nil:
+			tc.Status = StatusSkipped
+			tc.Message = fmt.Sprintf("%s (skipped: initialization failed)", check.Description)
+		case check.Requires != "" && !advertises(init, check.Requires):
+			tc.Status = StatusSkipped
+			tc.Message = fmt.Sprintf("%s (skipped: server does not advertise %s)", check.Description, check.Requires)
+		default:
+			runConformanceCheck(client, opts, init, id, check, tc)
+		}
+		suite.Add(tc)
+	}
+}
+
+func runConformanceCheck(client *core.MCPClient, opts *TestOptions, init *core.InitializeResult, id *int, check ConformanceCheck, tc *TestCase) {
+	p := &conformanceProbe{client: client, opts: opts, id: id, init: init}
+	start := time.Now()
+	err := check.run(p)
+	tc.Duration = time.Since(start)
+	if err == nil {
+		tc.Status = StatusPassed
+		return
+	}
+	tc.Status = StatusFailed
+	tc.Failures = []string{err.Error()}
+	if p.sent != "" {
+		tc.Failures = append(tc.Failures, "sent: "+p.sent)
+	}
+	tc.Request = p.request
+	tc.Response = p.response
+}
+
+// advertises reports whether the server declared a capability during
+// initialization.
+func advertises(init *core.InitializeResult, capability string) bool {
+	if init == nil {
+		return false
+	}
+	switch capability {
+	case "resources":
+		return init.Capabilities.Resources != nil
+	case "tools":
+		return init.Capabilities.Tools != nil
+	case "prompts":
+		return init.Capabilities.Prompts != nil
+	case "logging":
+		return init.Capabilities.Logging != nil
+	case "completions":
+		return init.Capabilities.Completions != nil
+	}
+	return false
+}
+
+func checkInitialize(p *conformanceProbe) error {
+	if !core.IsSupportedProtocolVersion(p.init.ProtocolVersion) {
+		return fmt.Errorf("unsupported protocolVersion %q", p.init.ProtocolVersion)
+	}
+	if p.init.ServerInfo.Name == "" {
+		return fmt.Errorf("serverInfo.name is missing")
+	}
+	return nil
+}
+
+func checkPing(p *conformanceProbe) error {
+	resp, err := p.call("ping", nil)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("ping failed: %s (code %d)", resp.Error.Message, resp.Error.Code)
+	}
+	if result, ok := resp.Result.(map[string]interface{}); !ok || len(result) != 0 {
+		return fmt.Errorf("expected an empty object, got %s", marshalCompact(resp.Result))
+	}
+	return nil
+}
+
+func checkResponseID(p *conformanceProbe) error {
+	id := fmt.Sprintf("mcpcli-conformance-%d", *p.id)
+	*p.id++
+	resp, err := p.callWithID("ping", nil, id)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("no response carrying the string id %q: %w", id, err)
+		}
+		return err
+	}
+	if got, ok := resp.ID.(string); !ok || got != id {
+		return fmt.Errorf("expected id %q, got %s", id, marshalCompact(resp.ID))
+	}
+	return nil
+}
+
+func checkMethodNotFound(p *conformanceProbe) error {
+	resp, err := p.call("mcpcli/conformance-unknown-method", nil)
+	return expectError(resp, err, core.MethodNotFound)
+}
+
+func checkNotificationNoReply(p *conformanceProbe) error {
+	wait := notificationWait
+	if p.opts.Timeout > 0 && p.opts.Timeout < wait {
+		wait = p.opts.Timeout
+	}
+	_, err := p.raw(`{"jsonrpc":"2.0","method":"ping"}`, wait)
+	switch {
+	case err == nil:
+		return fmt.Errorf("the ping notification was answered")
+	case !errors.Is(err, context.DeadlineExceeded):
+		return err
+	}
+	// The server must still answer requests afterwards.
+	if _, err := p.call("ping", nil); err != nil {
+		return fmt.Errorf("ping after the notification: %w", err)
+	}
+	return nil
+}
+
+func checkParseError(p *conformanceProbe) error {
+	timeout := p.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTestTimeout
+	}
+	resp, err := p.raw(`{"jsonrpc":"2.0","method":"ping","id":`, timeout)
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("no response with a null id: %w", err)
+		}
+		return err
+	}
+	return expectError(resp, nil, core.ParseError)
+}
+
+func checkReadMissingURI(p *conformanceProbe) error {
+	resp, err := p.call("resources/read", map[string]interface{}{})
+	return expectError(resp, err, core.InvalidParams)
+}
+
+func checkReadUnknownURI(p *conformanceProbe) error {
+	resp, err := p.call("resources/read", map[string]interface{}{"uri": "mcpcli-conformance:///missing"})
+	return expectError(resp, err, core.ResourceNotFound)
+}
+
+func checkCallUnknownTool(p *conformanceProbe) error {
+	resp, err := p.call("tools/call", map[string]interface{}{"name": "mcpcli-conformance-unknown-tool", "arguments": map[string]interface{}{}})
+	return expectError(resp, err, core.InvalidParams)
+}
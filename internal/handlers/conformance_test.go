@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestConformanceChecks_Registry(t *testing.T) {
+	seen := map[string]bool{}
+	for _, c := range ConformanceChecks() {
+		if c.ID == "" || c.Description == "" || c.run == nil {
+			t.Errorf("incomplete check %+v", c)
+		}
+		if !strings.HasPrefix(c.Spec, "https://") {
+			t.Errorf("%s: missing spec reference", c.ID)
+		}
+		if seen[c.ID] {
+			t.Errorf("duplicate check %s", c.ID)
+		}
+		seen[c.ID] = true
+	}
+	checks := ConformanceChecks()
+	checks[0].ID = "changed"
+	if ConformanceChecks()[0].ID == "changed" {
+		t.Error("ConformanceChecks must return a copy")
+	}
+}
+
+func TestCollectTests_Conformance(t *testing.T) {
+	opts := &TestOptions{Conformance: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	suite := report.Suite(ConformanceSuiteName + "@" + ConformanceSuiteVersion)
+	if len(suite.Cases) != len(conformanceChecks) {
+		t.Fatalf("expected one case per check, got %d", len(suite.Cases))
+	}
+	for _, c := range suite.Cases {
+		if !c.Passed() {
+			t.Errorf("%s: %s %v", c.Name, c.Status, c.Failures)
+		}
+	}
+}
+
+// newSloppyServer answers over HTTP while breaking most conformance checks.
+func newSloppyServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		if s, ok := req.ID.(string); ok {
+			resp.ID = len(s)
+		}
+		switch req.Method {
+		case "initialize":
+			resp.Result = core.InitializeResult{
+				ProtocolVersion: core.LatestProtocolVersion,
+				Capabilities:    core.ServerCapabilities{Tools: &core.ServerToolsCapability{}},
+				ServerInfo:      core.Implementation{Name: "sloppy", Version: "1.0.0"},
+			}
+		case "notifications/initialized":
+			w.WriteHeader(http.StatusAccepted)
+			return
+		case "ping":
+			resp.Result = map[string]interface{}{"ok": true}
+		case "tools/call":
+			resp = core.NewErrorResponse(req.ID, core.InvalidParams, "unknown tool", nil)
+		default:
+			resp = core.NewErrorResponse(req.ID, core.InternalError, "unsupported", nil)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectTests_ConformanceFailures(t *testing.T) {
+	srv := newSloppyServer(t)
+	opts := &TestOptions{Conformance: true, Timeout: 300 * time.Millisecond}
+	cfg := &core.MCPConfig{Name: "sloppy", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]TestStatus{
+		"lifecycle/initialize":          StatusPassed,
+		"ping/empty-result":             StatusFailed,
+		"jsonrpc/response-id":           StatusFailed,
+		"jsonrpc/method-not-found":      StatusFailed,
+		"jsonrpc/notification-no-reply": StatusFailed,
+		"jsonrpc/parse-error":           StatusFailed,
+		"resources/read-missing-uri":    StatusSkipped,
+		"resources/read-unknown-uri":    StatusSkipped,
+		"tools/call-unknown-tool":       StatusPassed,
+	}
+	cases := report.Suites[0].Cases
+	if len(cases) != len(want) {
+		t.Fatalf("expected %d cases, got %d", len(want), len(cases))
+	}
+	for _, c := range cases {
+		if c.Status != want[c.Name] {
+			t.Errorf("%s: expected %s, got %s %v", c.Name, want[c.Name], c.Status, c.Failures)
+		}
+		if c.Spec == "" {
+			t.Errorf("%s: missing spec reference", c.Name)
+		}
+	}
+	methodNotFound := cases[3]
+	if !strings.Contains(methodNotFound.Failures[0], "expected error -32601, got -32603") || methodNotFound.Response == nil {
+		t.Errorf("unexpected method-not-found case %+v", methodNotFound)
+	}
+	if parse := cases[5]; len(parse.Failures) != 2 || !strings.HasPrefix(parse.Failures[1], "sent: ") {
+		t.Errorf("expected the malformed message in the failures, got %v", parse.Failures)
+	}
+	if err := report.Err(); err == nil || !strings.Contains(err.Error(), "5 of 9") {
+		t.Errorf("unexpected report error %v", err)
+	}
+}
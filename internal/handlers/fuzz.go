@@ -0,0 +1,349 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// Defaults of the `mcpcli test --fuzz` flags.
+const (
+	DefaultFuzzIterations = 100
+	DefaultFuzzSeed       = 1
+	DefaultFuzzOutput     = "fuzz-reproducers"
+)
+
+const (
+	// fuzzShrinkAttempts bounds the calls spent shrinking one finding.
+	fuzzShrinkAttempts = 50
+	// fuzzMaxHangs stops fuzzing a tool that keeps hanging.
+	fuzzMaxHangs = 3
+)
+
+// Kinds of problems found by the fuzzer.
+const (
+	findingCrash     = "crash"
+	findingHang      = "hang"
+	findingProtocol  = "protocol-violation"
+	findingMalformed = "malformed-result"
+	findingTransport = "transport-error"
+)
+
+// fuzzFinding is a problem triggered by a fuzzed input.
+type fuzzFinding struct {
+	Kind    string
+	Message string
+	// Response is the response showing the problem, nil when the server
+	// did not answer.
+	Response *core.Response
+}
+
+func (f *fuzzFinding) String() string {
+	return fmt.Sprintf("%s: %s", f.Kind, f.Message)
+}
+
+// fuzzer sends fuzzed tools/call requests to a server.
+type fuzzer struct {
+	client     *core.MCPClient
+	opts       *TestOptions
+	id         *int
+	serverDown bool
+	written    int
+}
+
+// runFuzzCases fuzzes every tool listed in a tools/list response and records
+// one case per tool.
+func runFuzzCases(client *core.MCPClient, opts *TestOptions, listed *core.Response, id *int, suite *TestSuite) {
+	result, err := core.DecodeListToolsResult(listed)
+	if err != nil {
+		suite.Add(&TestCase{Name: "tools/list result", Status: StatusFailed, Message: err.Error(), Response: listed})
+		return
+	}
+	f := &fuzzer{client: client, opts: opts, id: id}
+	for _, tool := range result.Tools {
+		suite.Add(f.fuzzTool(tool))
+	}
+}
+
+func (f *fuzzer) fuzzTool(tool core.ToolDefinition) *TestCase {
+	tc := &TestCase{Name: "fuzz " + tool.Name, Status: StatusPassed}
+	if f.serverDown {
+		tc.Status = StatusSkipped
+		tc.Message = fmt.Sprintf("Tool %s: not fuzzed, the server is no longer running", tool.Name)
+		return tc
+	}
+	schema, err := tool.CompileInputSchema()
+	if err != nil {
+		tc.Status = StatusFailed
+		tc.Message = fmt.Sprintf("Tool %s: invalid inputSchema: %v", tool.Name, err)
+		return tc
+	}
+
+	seed := toolSeed(f.opts.FuzzSeed, tool.Name)
+	gen := core.NewSchemaFuzzer(seed)
+	inputs := gen.EdgeCases(schema)
+	iterations := f.opts.FuzzIterations
+	if iterations <= 0 {
+		iterations = DefaultFuzzIterations
+	}
+	for i := 0; i < iterations; i++ {
+		inputs = append(inputs, gen.Random(schema))
+	}
+
+	start := time.Now()
+	seen := map[string]bool{}
+	hangs := 0
+	sent := 0
+	for _, input := range inputs {
+		if f.serverDown || hangs >= fuzzMaxHangs {
+			break
+		}
+		sent++
+		finding := f.call(tool.Name, input.Value)
+		if finding == nil {
+			continue
+		}
+		key := finding.Kind + "\x00" + finding.Message
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		switch finding.Kind {
+		case findingHang:
+			hangs++
+		case findingCrash:
+			f.serverDown = true
+		}
+
+		args, shrunk := input.Value, finding
+		if finding.Kind != findingCrash && finding.Kind != findingHang {
+			args = core.ShrinkValue(args, func(v interface{}) bool {
+				again := f.call(tool.Name, v)
+				if again == nil || again.Kind != finding.Kind {
+					return false
+				}
+				shrunk = again
+				return true
+			}, fuzzShrinkAttempts)
+		}
+		line := fmt.Sprintf("%s (input: %s)", finding, input.Label)
+		if input.Valid {
+			line = fmt.Sprintf("%s (input: %s, conforming to the schema)", finding, input.Label)
+		}
+		if path, err := f.writeReproducer(tool.Name, seed, shrunk, input.Label, args); err != nil {
+			line += fmt.Sprintf(", reproducer not written: %v", err)
+		} else {
+			line += ", reproducer: " + path
+		}
+		tc.Failures = append(tc.Failures, line)
+		if finding.Kind == findingCrash || finding.Kind == findingHang {
+			tc.Status = StatusError
+		} else if tc.Status == StatusPassed {
+			tc.Status = StatusFailed
+		}
+	}
+	tc.Duration = time.Since(start)
+	if tc.Passed() {
+		tc.Message = fmt.Sprintf("Tool %s: %d inputs, no problems found (seed %d)", tool.Name, sent, seed)
+	} else {
+		tc.Message = fmt.Sprintf("Tool %s: %d problems found in %d inputs (seed %d)", tool.Name, len(tc.Failures), sent, seed)
+	}
+	return tc
+}
+
+// toolSeed derives the seed of a tool from the run seed, so that the inputs
+// of a tool do not change when other tools are added.
+func toolSeed(seed int64, tool string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte(tool))
+	return seed ^ int64(h.Sum64())
+}
+
+// call sends one fuzzed input and returns the problem it triggered, if any.
+// Error responses are fine: servers are expected to reject bad arguments.
+func (f *fuzzer) call(tool string, args interface{}) *fuzzFinding {
+	if f.serverDown {
+		return nil
+	}
+	params := map[string]interface{}{"name": tool, "arguments": args}
+	timeout := f.opts.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTestTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	resp, err := f.client.CallContext(ctx, "tools/call", params, *f.id)
+	cancel()
+	*f.id++
+
+	if err != nil {
+		switch {
+		case errors.Is(err, context.DeadlineExceeded):
+			return &fuzzFinding{Kind: findingHang, Message: fmt.Sprintf("no response within %s", timeout)}
+		case serverExited(f.client):
+			f.serverDown = true
+			return &fuzzFinding{Kind: findingCrash, Message: describeCallError(f.client, err)}
+		default:
+			return &fuzzFinding{Kind: findingTransport, Message: err.Error()}
+		}
+	}
+	if err := resp.CheckProtocol(); err != nil {
+		return &fuzzFinding{Kind: findingProtocol, Message: err.Error(), Response: resp}
+	}
+	if resp.Error != nil {
+		return nil
+	}
+	if problems := core.CheckToolResult(resp.Result); len(problems) > 0 {
+		return &fuzzFinding{Kind: findingMalformed, Message: problems[0].Error(), Response: resp}
+	}
+	return nil
+}
+
+// serverExited reports whether a stdio server process has terminated.
+func serverExited(client *core.MCPClient) bool {
+	cmd, ok := core.AsCommandTransport(client.Transport())
+	if !ok {
+		return false
+	}
+	select {
+	case <-cmd.Exited():
+		return true
+	case <-time.After(exitWait):
+		return false
+	}
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+// writeReproducer saves a finding as a scenario script that replays the
+// failing call with `mcpcli test --script`, asserting what the response of
+// the finding got wrong.
+func (f *fuzzer) writeReproducer(tool string, seed int64, finding *fuzzFinding, label string, args interface{}) (string, error) {
+	dir := f.opts.FuzzOutput
+	if dir == "" {
+		dir = DefaultFuzzOutput
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	f.written++
+	name := fmt.Sprintf("%s-%s-%d.json", unsafeFileChars.ReplaceAllString(tool, "_"), finding.Kind, f.written)
+	step := core.ScenarioStep{
+		Name:   finding.Kind,
+		Method: "tools/call",
+		Params: map[string]interface{}{"name": tool, "arguments": args},
+	}
+	expectFixed(&step, finding)
+	scenario := core.Scenario{
+		Name:  fmt.Sprintf("fuzz reproducer for %s (seed %d, input %s): %s", tool, seed, label, finding),
+		Steps: []core.ScenarioStep{step},
+	}
+	data, err := json.MarshalIndent(scenario, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// base64Pattern matches standard, padded base64.
+const base64Pattern = `^(?:[A-Za-z0-9+/]{4})*(?:[A-Za-z0-9+/]{2}==|[A-Za-z0-9+/]{3}=)?$`
+
+// expectFixed sets the expectations of a reproducer step to what a correct
+// response passes and the response of the finding failed. Without a
+// response, and for protocol violations which runStep reports itself, the
+// step already fails the same way.
+func expectFixed(step *core.ScenarioStep, finding *fuzzFinding) {
+	resp := finding.Response
+	if resp == nil {
+		return
+	}
+	if resp.Error != nil {
+		step.ExpectError = &core.ExpectedError{}
+	}
+	if finding.Kind == findingProtocol {
+		switch {
+		case resp.Error == nil && resp.Result == nil:
+			step.Assert = []core.Assertion{{Path: "$.result", Type: "object"}}
+		case resp.Error != nil && resp.Result == nil && resp.JSONRPC == core.JSONRPCVersion:
+			// The error code is reserved; bad arguments are invalid params.
+			code := core.InvalidParams
+			step.ExpectError.Code = &code
+		}
+		return
+	}
+	doc, err := core.ResponseDocument(resp)
+	if err != nil {
+		return
+	}
+	for _, a := range toolResultAssertions(resp.Result) {
+		if a.Check(doc, nil) != nil {
+			step.Assert = append(step.Assert, a)
+		}
+	}
+}
+
+// toolResultAssertions lists the checks of CheckToolResult that apply to
+// result as assertions.
+func toolResultAssertions(result interface{}) []core.Assertion {
+	out := []core.Assertion{
+		{Path: "$.result", Type: "object"},
+		{Path: "$.result.content", Type: "array"},
+	}
+	obj, _ := result.(map[string]interface{})
+	if _, ok := obj["isError"]; ok {
+		out = append(out, core.Assertion{Path: "$.result.isError", Type: "boolean"})
+	}
+	if _, ok := obj["structuredContent"]; ok {
+		out = append(out, core.Assertion{Path: "$.result.structuredContent", Type: "object"})
+	}
+	content, _ := obj["content"].([]interface{})
+	for i, item := range content {
+		path := fmt.Sprintf("$.result.content[%d]", i)
+		out = append(out, core.Assertion{Path: path, Type: "object"})
+		block, _ := item.(map[string]interface{})
+		switch t, _ := block["type"].(string); t {
+		case "text":
+			out = append(out, core.Assertion{Path: path + ".text", Type: "string"})
+		case "image", "audio":
+			out = append(out,
+				core.Assertion{Path: path + ".data", Matches: base64Pattern},
+				core.Assertion{Path: path + ".mimeType", Matches: "."})
+		case "resource_link":
+			out = append(out,
+				core.Assertion{Path: path + ".uri", Type: "string"},
+				core.Assertion{Path: path + ".name", Type: "string"})
+		case "resource":
+			out = append(out,
+				core.Assertion{Path: path + ".resource", Type: "object"},
+				core.Assertion{Path: path + ".resource.uri", Type: "string"})
+			resource, _ := block["resource"].(map[string]interface{})
+			_, hasText := resource["text"]
+			switch _, hasBlob := resource["blob"]; {
+			case hasText && hasBlob:
+				out = append(out, core.Assertion{Path: path + ".resource.blob", Exists: boolPtr(false)})
+			case hasText:
+				out = append(out, core.Assertion{Path: path + ".resource.text", Type: "string"})
+			case hasBlob:
+				out = append(out, core.Assertion{Path: path + ".resource.blob", Matches: base64Pattern})
+			default:
+				out = append(out, core.Assertion{Path: path + ".resource.text", Exists: boolPtr(true)})
+			}
+		default:
+			out = append(out, core.Assertion{Path: path + ".type", Matches: "^(text|image|audio|resource_link|resource)$"})
+		}
+	}
+	return out
+}
+
+func boolPtr(b bool) *bool { return &b }
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// newFuzzServer serves a robust tool and a fragile one, which returns
+// malformed content for long texts and answers negative counts with a reserved error code.
+func newFuzzServer(t *testing.T) *httptest.Server {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"text":  map[string]interface{}{"type": "string"},
+			"count": map[string]interface{}{"type": "integer"},
+		},
+		"required": []interface{}{"text"},
+	}
+	tools := []interface{}{
+		map[string]interface{}{"name": "robust", "inputSchema": schema},
+		map[string]interface{}{"name": "fragile", "inputSchema": schema},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		switch req.Method {
+		case "initialize":
+			resp.Result = core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "fuzz", Version: "1.0.0"}}
+		case "tools/list":
+			resp.Result = map[string]interface{}{"tools": tools}
+		case "tools/call":
+			args, ok := req.Params["arguments"].(map[string]interface{})
+			text, isString := args["text"].(string)
+			if !ok || !isString {
+				resp = core.NewErrorResponse(req.ID, core.InvalidParams, "text is required", nil)
+				break
+			}
+			valid := map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "ok"}}}
+			if req.Params["name"] == "robust" {
+				resp.Result = valid
+				break
+			}
+			count, _ := args["count"].(float64)
+			switch {
+			case count < 0:
+				resp = core.NewErrorResponse(req.ID, -32500, "negative", nil)
+			case len(text) > 10:
+				resp.Result = map[string]interface{}{"content": text}
+			default:
+				resp.Result = valid
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectTests_Fuzz(t *testing.T) {
+	srv := newFuzzServer(t)
+	dir := filepath.Join(t.TempDir(), "repro")
+	opts := &TestOptions{Fuzz: true, FuzzIterations: 20, FuzzSeed: 7, FuzzOutput: dir, Timeout: time.Second}
+	cfg := &core.MCPConfig{Name: "fuzz", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("fuzz").Cases
+	if len(cases) != 3 {
+		t.Fatalf("expected list and 2 fuzz cases, got %d", len(cases))
+	}
+	if robust := cases[1]; robust.Name != "fuzz robust" || !robust.Passed() {
+		t.Errorf("expected robust tool to pass, got %+v", robust)
+	}
+	fragile := cases[2]
+	if fragile.Name != "fuzz fragile" || fragile.Status != StatusFailed {
+		t.Fatalf("expected fragile tool to fail, got %+v", fragile)
+	}
+	var malformed, protocol bool
+	for _, f := range fragile.Failures {
+		malformed = malformed || strings.HasPrefix(f, "malformed-result: content must be an array")
+		protocol = protocol || strings.HasPrefix(f, "protocol-violation: error code -32500 is reserved")
+	}
+	if !malformed || !protocol {
+		t.Errorf("expected malformed result and protocol violation, got %v", fragile.Failures)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "fragile-*.json"))
+	if err != nil || len(files) != len(fragile.Failures) {
+		t.Fatalf("expected one reproducer per failure, got %v (%v)", files, err)
+	}
+	for _, file := range files {
+		scenario, err := core.LoadScenario(file)
+		if err != nil {
+			t.Fatalf("reproducer %s: %v", file, err)
+		}
+		if len(scenario.Steps) != 1 || scenario.Steps[0].Method != "tools/call" {
+			t.Fatalf("unexpected reproducer %+v", scenario)
+		}
+		args := scenario.Steps[0].Params["arguments"].(map[string]interface{})
+		step := scenario.Steps[0]
+		var failures []string
+		switch {
+		case strings.Contains(file, "malformed-result"):
+			// Shrinking drops count and halves text while it stays over 10 bytes.
+			text, _ := args["text"].(string)
+			if len(args) != 1 || len(text) <= 10 || len(text) > 21 {
+				t.Errorf("expected shrunk text argument, got %d members and %d bytes", len(args), len(text))
+			}
+			if len(step.Assert) != 1 || step.Assert[0].Path != "$.result.content" || step.Assert[0].Type != "array" || step.ExpectError != nil {
+				t.Errorf("expected an assertion on the content type, got %+v", step)
+			}
+			failures = []string{"$.result.content: expected type array, got string"}
+		case strings.Contains(file, "protocol-violation"):
+			if args["count"] != -1.0 || args["text"] != "" {
+				t.Errorf("expected minimal negative count, got %v", args)
+			}
+			if step.ExpectError == nil || step.ExpectError.Code == nil || *step.ExpectError.Code != core.InvalidParams {
+				t.Errorf("expected an invalid params error, got %+v", step)
+			}
+			failures = []string{"error code -32500 is reserved by JSON-RPC", "expected error code -32602, got -32500"}
+		}
+
+		// Replaying the reproducer fails the way the fuzzer did.
+		replay, err := CollectTests(&TestOptions{ScriptFile: file, Timeout: time.Second}, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps := replay.Suite("script").Cases
+		if len(steps) != 1 || steps[0].Passed() || strings.Join(steps[0].Failures, "\n") != strings.Join(failures, "\n") {
+			t.Errorf("replaying %s: expected %q, got %+v", file, failures, steps)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "robust-malformed-result-1.json")); !os.IsNotExist(err) {
+		t.Error("no reproducer expected for the robust tool")
+	}
+}
+
+func TestCollectTests_FuzzDeterministic(t *testing.T) {
+	srv := newFuzzServer(t)
+	cfg := &core.MCPConfig{Name: "fuzz", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	run := func() []string {
+		opts := &TestOptions{Fuzz: true, FuzzIterations: 10, FuzzSeed: 3, FuzzOutput: t.TempDir(), Timeout: time.Second}
+		report, err := CollectTests(opts, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var out []string
+		for _, tc := range report.Suite("fuzz").Cases {
+			out = append(out, tc.Message)
+			for _, f := range tc.Failures {
+				out = append(out, f[:strings.Index(f, ", reproducer")])
+			}
+		}
+		return out
+	}
+	first, second := run(), run()
+	if strings.Join(first, "\n") != strings.Join(second, "\n") {
+		t.Errorf("runs with the same seed differ:\n%v\n%v", first, second)
+	}
+}
+
+func TestExpectFixed(t *testing.T) {
+	result := func(content ...interface{}) interface{} {
+		return map[string]interface{}{"content": content, "isError": "no"}
+	}
+	for _, c := range []struct {
+		name    string
+		finding *fuzzFinding
+		want    []string
+	}{
+		{"hang", &fuzzFinding{Kind: findingHang}, nil},
+		{"no result", &fuzzFinding{Kind: findingProtocol, Response: &core.Response{JSONRPC: core.JSONRPCVersion}}, []string{"$.result"}},
+		{"result and error", &fuzzFinding{Kind: findingProtocol, Response: &core.Response{JSONRPC: core.JSONRPCVersion, Result: 1, Error: &core.Error{Code: core.InvalidParams}}}, []string{"error"}},
+		{"malformed", &fuzzFinding{Kind: findingMalformed, Response: &core.Response{JSONRPC: core.JSONRPCVersion, Result: result(
+			map[string]interface{}{"type": "text", "text": "ok"},
+			map[string]interface{}{"type": "image", "data": "not base64!", "mimeType": "image/png"},
+			map[string]interface{}{"type": "resource", "resource": map[string]interface{}{"uri": "x:", "text": "a", "blob": "YQ=="}},
+			map[string]interface{}{"type": "video"},
+		)}}, []string{"$.result.isError", "$.result.content[1].data", "$.result.content[2].resource.blob", "$.result.content[3].type"}},
+	} {
+		step := &core.ScenarioStep{Method: "tools/call"}
+		expectFixed(step, c.finding)
+		var got []string
+		if step.ExpectError != nil {
+			got = append(got, "error")
+		}
+		for _, a := range step.Assert {
+			got = append(got, a.Path)
+		}
+		if strings.Join(got, " ") != strings.Join(c.want, " ") {
+			t.Errorf("%s: got %v, want %v", c.name, got, c.want)
+		}
+		if c.finding.Response == nil {
+			continue
+		}
+		if failures := step.Check(c.finding.Response, nil); len(failures) != len(step.Assert) {
+			t.Errorf("%s: expected every assertion to fail, got %v", c.name, failures)
+		}
+	}
+}
@@ -2,11 +2,16 @@ package handlers
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/aawadall/mcpcli/internal/core"
 	"github.com/aawadall/mcpcli/internal/generators"
+	"github.com/fatih/color"
 )
 
 // GenerateOptions holds all configurable parameters for project generation.
@@ -16,16 +21,32 @@ type GenerateOptions struct {
 	Transport string
 	Docker    bool
 	Examples  bool
-	Output    string
+	// DockerSet and ExamplesSet tell whether Docker and Examples were given
+	// explicitly, in which case they override a project spec.
+	DockerSet   bool
+	ExamplesSet bool
+	Output      string
+	// Author and Description are recorded in the generated project's
+	// metadata.
+	Author      string
+	Description string
 	// Interactive indicates if prompts should be shown. It is ignored by the generator.
-	Interactive  bool
-	Force        bool
+	Interactive bool
+	Force       bool
+	// DryRun lists the files that would be generated without writing them.
+	DryRun bool
+	// Diff prints the unified diff between the output directory and the
+	// generated project without writing it.
+	Diff         bool
 	Tools        []core.Tool
 	Resources    []core.Resource
+	Prompts      []core.Prompt
 	Capabilities []core.Capability
 }
 
-// ValidateGenerateOptions checks if the provided options are valid.
+// ValidateGenerateOptions checks if the provided options are valid, including
+// the tools, resources, prompts and capabilities given by flags or
+// interactively, which are held to the rules of project specs.
 func ValidateGenerateOptions(opts *GenerateOptions) error {
 	if opts.Name == "" {
 		return fmt.Errorf("project name is required")
@@ -34,16 +55,99 @@ func ValidateGenerateOptions(opts *GenerateOptions) error {
 	if !contains(validLanguages, opts.Language) {
 		return fmt.Errorf("invalid language: %s, valid options are: %v", opts.Language, validLanguages)
 	}
-	validTransports := []string{"stdio", "rest", "websocket"}
+	validTransports := []string{"stdio", "rest", "streamable-http", "websocket"}
 	if !contains(validTransports, opts.Transport) {
 		return fmt.Errorf("invalid transport: %s, valid options are: %v", opts.Transport, validTransports)
 	}
+	tools := map[string]bool{}
+	for _, tool := range opts.Tools {
+		if err := validateEntityName("tool", tool.Name, tools); err != nil {
+			return err
+		}
+		if err := tool.Validate(); err != nil {
+			return fmt.Errorf("invalid tool %s: %w", tool.Name, err)
+		}
+		if err := tool.ValidateUpdates(opts.Resources); err != nil {
+			return fmt.Errorf("invalid tool %s: %w", tool.Name, err)
+		}
+	}
+	resources := map[string]bool{}
+	for _, r := range opts.Resources {
+		if err := validateEntityName("resource", r.Name, resources); err != nil {
+			return err
+		}
+		if !core.IsValidResourceType(r.Type) {
+			return fmt.Errorf("invalid resource %s: invalid resource type: %s, valid options are: %v", r.Name, r.Type,
+				[]core.ResourceType{core.ResourceTypeDatabase, core.ResourceTypeFilesystem, core.ResourceTypeTime})
+		}
+		if r.URITemplate != "" {
+			if _, err := core.ParseURITemplate(r.URITemplate); err != nil {
+				return fmt.Errorf("invalid resource %s: invalid URI template: %w", r.Name, err)
+			}
+		}
+	}
+	prompts := map[string]bool{}
+	for _, p := range opts.Prompts {
+		if err := validateEntityName("prompt", p.Name, prompts); err != nil {
+			return err
+		}
+	}
+	capabilities := map[string]bool{}
+	for _, c := range opts.Capabilities {
+		if err := validateEntityName("capability", c.Name, capabilities); err != nil {
+			return err
+		}
+	}
 	if opts.Output == "" {
 		opts.Output = opts.Name
 	}
 	return nil
 }
 
+// ApplyProjectSpec fills the options left unset from a project spec, so that
+// flags and arguments take precedence over the spec file. Docker and Examples
+// come from the spec unless DockerSet or ExamplesSet is true.
+func ApplyProjectSpec(opts *GenerateOptions, spec *core.ProjectConfig) {
+	fill := func(opt *string, value string) {
+		if *opt == "" {
+			*opt = value
+		}
+	}
+	fill(&opts.Name, spec.Name)
+	fill(&opts.Language, spec.Language)
+	fill(&opts.Transport, spec.Transport)
+	fill(&opts.Output, spec.Output)
+	fill(&opts.Author, spec.Author)
+	fill(&opts.Description, spec.Description)
+	if !opts.DockerSet {
+		opts.Docker = spec.Docker
+	}
+	if !opts.ExamplesSet {
+		opts.Examples = spec.Examples
+	}
+	opts.Tools = append(opts.Tools, spec.Tools...)
+	opts.Resources = append(opts.Resources, spec.Resources...)
+	opts.Prompts = append(opts.Prompts, spec.Prompts...)
+	opts.Capabilities = append(opts.Capabilities, spec.Capabilities...)
+}
+
+// validateEntityName checks that the name of a tool, resource, prompt or
+// capability is an identifier not already in seen, and adds it to seen. Names
+// become file, function and class names in the generated project.
+func validateEntityName(kind, name string, seen map[string]bool) error {
+	if name == "" {
+		return fmt.Errorf("%s name is required", kind)
+	}
+	if !entityNamePattern.MatchString(name) {
+		return fmt.Errorf("%s name %q must be a valid identifier", kind, name)
+	}
+	if seen[name] {
+		return fmt.Errorf("duplicate %s name %q", kind, name)
+	}
+	seen[name] = true
+	return nil
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {
@@ -54,6 +158,12 @@ func contains(slice []string, item string) bool {
 }
 
 // GenerateProject creates the project structure based on the provided options.
+// The project is rendered in memory first. It is then written to a temporary
+// directory next to the output directory and renamed into place, so that a
+// failure leaves no partial project behind and a directory replaced with
+// Force is only removed once the new project is complete. With DryRun the
+// files are listed and with Diff they are compared to the output directory;
+// neither writes anything.
 func GenerateProject(opts *GenerateOptions) error {
 	config := &core.ProjectConfig{
 		Name:         opts.Name,
@@ -62,40 +172,209 @@ func GenerateProject(opts *GenerateOptions) error {
 		Docker:       opts.Docker,
 		Examples:     opts.Examples,
 		Output:       opts.Output,
+		Author:       opts.Author,
+		Description:  opts.Description,
 		Tools:        opts.Tools,
 		Resources:    opts.Resources,
+		Prompts:      opts.Prompts,
 		Capabilities: opts.Capabilities,
-	}
-	if err := prepareDirectory(opts.Output, opts.Force); err != nil {
-		return err
+		Version:      core.CLIVersion,
+		CreatedAt:    time.Now(),
 	}
 	generator, err := selectGenerator(opts.Language)
 	if err != nil {
 		return err
 	}
-	if err := generator.Generate(config); err != nil {
-		os.RemoveAll(opts.Output)
+	files, err := generator.Render(config)
+	if err != nil {
 		return fmt.Errorf("failed to generate project: %w", err)
 	}
+	if opts.Diff {
+		return printProjectDiff(os.Stdout, opts.Output, files)
+	}
+	exists, err := checkOutputDirectory(opts.Output, opts.Force)
+	if err != nil {
+		return err
+	}
+	if opts.DryRun {
+		printDryRun(os.Stdout, opts.Output, exists, files)
+		return nil
+	}
+	if err := writeProject(config, files, exists); err != nil {
+		return err
+	}
+	for _, f := range files {
+		color.Green("✅ Created file: %s", filepath.Join(opts.Output, f.Path))
+	}
 	printNextSteps(opts)
 	return nil
 }
 
-// prepareDirectory creates the output directory, removing it first when force is true.
-func prepareDirectory(path string, force bool) error {
-	if stat, err := os.Stat(path); err == nil {
-		if force {
-			if err := os.RemoveAll(path); err != nil {
-				return fmt.Errorf("failed to remove existing directory: %w", err)
+// checkOutputDirectory reports whether the output directory exists, which is
+// an error unless force is true. Force cannot replace the working directory or
+// one of its ancestors, which would be moved away from under the process.
+func checkOutputDirectory(path string, force bool) (bool, error) {
+	stat, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return false, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to check output directory: %w", err)
+	case !stat.IsDir():
+		return false, fmt.Errorf("output %s exists and is not a directory", path)
+	case !force:
+		return false, fmt.Errorf("output directory %s already exists, use --force to overwrite", path)
+	}
+	contains, err := containsWorkingDirectory(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to check output directory: %w", err)
+	}
+	if contains {
+		return false, fmt.Errorf("output directory %s contains the working directory and cannot be replaced, generate the project from another directory", path)
+	}
+	return true, nil
+}
+
+// containsWorkingDirectory reports whether dir is the working directory or
+// one of its ancestors.
+func containsWorkingDirectory(dir string) (bool, error) {
+	wd, err := os.Getwd()
+	if err != nil {
+		return false, err
+	}
+	if wd, err = filepath.EvalSymlinks(wd); err != nil {
+		return false, err
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return false, err
+	}
+	if abs, err = filepath.EvalSymlinks(abs); err != nil {
+		return false, err
+	}
+	rel, err := filepath.Rel(abs, wd)
+	if err != nil {
+		return false, err
+	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)), nil
+}
+
+// writeProject writes the files of a project, with its generation manifest,
+// to a temporary directory and renames it to config.Output. An existing
+// output directory is moved aside first and only removed once the new one is
+// in place.
+func writeProject(config *core.ProjectConfig, files []generators.File, exists bool) error {
+	output := filepath.Clean(config.Output)
+	parent := filepath.Dir(output)
+	if err := os.MkdirAll(parent, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	staging, err := os.MkdirTemp(parent, "."+filepath.Base(output)+".mcpcli-")
+	if err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+	if err := os.Chmod(staging, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for _, f := range files {
+		if err := writeProjectFile(staging, f.Path, f.Content); err != nil {
+			return fmt.Errorf("failed to generate project: %w", err)
+		}
+	}
+	if err := writeManifest(staging, config, files); err != nil {
+		return fmt.Errorf("failed to record the generation manifest: %w", err)
+	}
+
+	if !exists {
+		if err := os.Rename(staging, output); err != nil {
+			return fmt.Errorf("failed to move the project into place: %w", err)
+		}
+		return nil
+	}
+	previous := staging + ".old"
+	if err := os.Rename(output, previous); err != nil {
+		return fmt.Errorf("failed to replace existing directory: %w", err)
+	}
+	if err := os.Rename(staging, output); err != nil {
+		os.Rename(previous, output)
+		return fmt.Errorf("failed to move the project into place: %w", err)
+	}
+	if err := os.RemoveAll(previous); err != nil {
+		return fmt.Errorf("failed to remove existing directory: %w", err)
+	}
+	return nil
+}
+
+// printDryRun lists the files generation would write.
+func printDryRun(w io.Writer, output string, exists bool, files []generators.File) {
+	if exists {
+		fmt.Fprintf(w, "🔍 Dry run: %s would be replaced by %d files:\n", output, len(files))
+	} else {
+		fmt.Fprintf(w, "🔍 Dry run: %d files would be created in %s:\n", len(files), output)
+	}
+	for _, f := range files {
+		fmt.Fprintf(w, "   %s (%d bytes)\n", filepath.ToSlash(f.Path), len(f.Content))
+	}
+	fmt.Fprintf(w, "   and the generation manifest in %s\n", manifestDir)
+}
+
+// printProjectDiff writes the unified diff turning the files of the output
+// directory, which may not exist, into the generated ones. The generation
+// manifest is left out.
+func printProjectDiff(w io.Writer, output string, files []generators.File) error {
+	existing := map[string]bool{}
+	err := filepath.Walk(output, func(path string, info os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == output {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if info.IsDir() && path != output && info.Name() == manifestDir {
+			return filepath.SkipDir
+		}
+		if !info.IsDir() {
+			rel, _ := filepath.Rel(output, path)
+			existing[rel] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to read output directory: %w", err)
+	}
+
+	changed := 0
+	for _, f := range files {
+		name := filepath.ToSlash(f.Path)
+		from, current := "/dev/null", []byte(nil)
+		if existing[f.Path] {
+			delete(existing, f.Path)
+			if current, err = os.ReadFile(filepath.Join(output, f.Path)); err != nil {
+				return fmt.Errorf("failed to read %s: %w", f.Path, err)
 			}
-		} else if stat != nil {
-			return fmt.Errorf("output directory %s already exists, use --force to overwrite", path)
+			from = "a/" + name
+		}
+		if diff := core.UnifiedDiff(current, f.Content, from, "b/"+name); diff != "" {
+			fmt.Fprint(w, diff)
+			changed++
 		}
-	} else if !os.IsNotExist(err) {
-		return fmt.Errorf("failed to check output directory: %w", err)
 	}
-	if err := os.MkdirAll(path, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	removed := make([]string, 0, len(existing))
+	for path := range existing {
+		removed = append(removed, path)
+	}
+	sort.Strings(removed)
+	for _, path := range removed {
+		current, err := os.ReadFile(filepath.Join(output, path))
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		fmt.Fprint(w, core.UnifiedDiff(current, nil, "a/"+filepath.ToSlash(path), "/dev/null"))
+		changed++
+	}
+	if changed == 0 {
+		fmt.Fprintf(w, "✅ %s matches the generated project\n", output)
 	}
 	return nil
 }
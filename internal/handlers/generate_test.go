@@ -4,13 +4,17 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
 )
 
 func TestValidateGenerateOptions(t *testing.T) {
-	transports := []string{"stdio", "rest", "websocket"}
+	transports := []string{"stdio", "rest", "streamable-http", "websocket"}
 	for _, tr := range transports {
 		opts := &GenerateOptions{Name: "proj", Language: "golang", Transport: tr}
 		if err := ValidateGenerateOptions(opts); err != nil {
@@ -23,6 +27,29 @@ func TestValidateGenerateOptions(t *testing.T) {
 	}
 }
 
+func TestValidateGenerateOptions_Entities(t *testing.T) {
+	for _, c := range []struct {
+		opts GenerateOptions
+		want string
+	}{
+		{GenerateOptions{Tools: []core.Tool{{Name: "my tool"}}}, `tool name "my tool" must be a valid identifier`},
+		{GenerateOptions{Tools: []core.Tool{{Name: "search"}, {Name: "search"}}}, `duplicate tool name "search"`},
+		{GenerateOptions{Resources: []core.Resource{{Name: "my-notes", Type: "filesystem"}}}, `resource name "my-notes" must be a valid identifier`},
+		{GenerateOptions{Resources: []core.Resource{{Name: "notes", Type: "cloud"}}}, "invalid resource type: cloud"},
+		{GenerateOptions{Resources: []core.Resource{{Name: "notes", Type: "time", URITemplate: "x://{"}}}, "invalid URI template"},
+		{GenerateOptions{Prompts: []core.Prompt{{Name: "code-review"}}}, `prompt name "code-review" must be a valid identifier`},
+		{GenerateOptions{Prompts: []core.Prompt{{}}}, "prompt name is required"},
+		{GenerateOptions{Capabilities: []core.Capability{{Name: "log ging"}}}, `capability name "log ging" must be a valid identifier`},
+	} {
+		opts := c.opts
+		opts.Name, opts.Language, opts.Transport = "proj", "golang", "stdio"
+		err := ValidateGenerateOptions(&opts)
+		if err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Errorf("ValidateGenerateOptions() = %v, want error containing %q", err, c.want)
+		}
+	}
+}
+
 func TestGenerateProjectCreatesDir(t *testing.T) {
 	tmp := t.TempDir()
 	out := filepath.Join(tmp, "proj")
@@ -35,7 +62,7 @@ func TestGenerateProjectCreatesDir(t *testing.T) {
 	}
 }
 
-func TestPrepareDirectoryForce(t *testing.T) {
+func TestCheckOutputDirectoryForce(t *testing.T) {
 	tmp := t.TempDir()
 	// create subdir
 	path := tmp + "/sub"
@@ -43,13 +70,158 @@ func TestPrepareDirectoryForce(t *testing.T) {
 		t.Fatal(err)
 	}
 	// should fail without force
-	if err := prepareDirectory(path, false); err == nil {
+	if _, err := checkOutputDirectory(path, false); err == nil {
 		t.Fatal("expected error when directory exists without force")
 	}
-	// should succeed with force
-	if err := prepareDirectory(path, true); err != nil {
+	// should succeed with force, leaving the directory in place
+	if exists, err := checkOutputDirectory(path, true); err != nil || !exists {
 		t.Fatalf("force failed: %v", err)
 	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("directory was removed: %v", err)
+	}
+	if exists, err := checkOutputDirectory(tmp+"/missing", false); err != nil || exists {
+		t.Fatalf("unexpected result for a missing directory: %v, %v", exists, err)
+	}
+}
+
+func TestCheckOutputDirectory_WorkingDirectory(t *testing.T) {
+	tmp := t.TempDir()
+	inner := filepath.Join(tmp, "proj", "inner")
+	if err := os.MkdirAll(inner, 0755); err != nil {
+		t.Fatal(err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(inner); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	for _, path := range []string{".", "..", filepath.Join(tmp, "proj")} {
+		if _, err := checkOutputDirectory(path, true); err == nil || !strings.Contains(err.Error(), "contains the working directory") {
+			t.Errorf("%s: expected the working directory to be refused, got %v", path, err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(inner, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range []string{"sub", filepath.Join(tmp, "proj") + "-other"} {
+		os.MkdirAll(path, 0755)
+		if exists, err := checkOutputDirectory(path, true); err != nil || !exists {
+			t.Errorf("%s: force failed: %v", path, err)
+		}
+	}
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "golang", Transport: "stdio", Output: ".", Force: true}); err == nil {
+		t.Fatal("expected generating over the working directory to fail")
+	}
+	if _, err := os.Stat(inner); err != nil {
+		t.Fatalf("working directory was moved: %v", err)
+	}
+}
+
+func TestGenerateProject_Force(t *testing.T) {
+	tmp := t.TempDir()
+	out := filepath.Join(tmp, "proj")
+	if err := os.MkdirAll(out, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(out, "stale.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	opts := &GenerateOptions{Name: "proj", Language: "golang", Transport: "stdio", Output: out}
+	if err := GenerateProject(opts); err == nil {
+		t.Fatal("expected an error without force")
+	}
+	opts.Language = "cobol"
+	opts.Force = true
+	if err := GenerateProject(opts); err == nil {
+		t.Fatal("expected an error for an unsupported language")
+	}
+	if _, err := os.Stat(filepath.Join(out, "stale.txt")); err != nil {
+		t.Fatalf("failed generation touched the existing directory: %v", err)
+	}
+
+	opts.Language = "golang"
+	captureGenOutput(func() {
+		if err := GenerateProject(opts); err != nil {
+			t.Errorf("GenerateProject failed: %v", err)
+		}
+	})
+	if _, err := os.Stat(filepath.Join(out, "stale.txt")); !os.IsNotExist(err) {
+		t.Error("existing directory was not replaced")
+	}
+	if _, err := os.Stat(filepath.Join(out, "go.mod")); err != nil {
+		t.Errorf("project not generated: %v", err)
+	}
+	if entries, _ := os.ReadDir(tmp); len(entries) != 1 {
+		t.Errorf("expected only the project in %s, got %d entries", tmp, len(entries))
+	}
+}
+
+func TestGenerateProject_DryRun(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "proj")
+	opts := &GenerateOptions{Name: "proj", Language: "python", Transport: "stdio", Output: out, DryRun: true,
+		Tools: []core.Tool{{Name: "search"}}}
+	var err error
+	output := captureGenOutput(func() { err = GenerateProject(opts) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); !os.IsNotExist(err) {
+		t.Error("dry run wrote the project")
+	}
+	for _, want := range []string{"files would be created in " + out, "src/tools/search.py", "configs/mcp-config.json"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in the output:\n%s", want, output)
+		}
+	}
+}
+
+func TestGenerateProject_Diff(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "proj")
+	opts := &GenerateOptions{Name: "proj", Language: "golang", Transport: "stdio", Output: out}
+	captureGenOutput(func() {
+		if err := GenerateProject(opts); err != nil {
+			t.Error(err)
+		}
+	})
+
+	opts.Diff = true
+	var err error
+	output := captureGenOutput(func() { err = GenerateProject(opts) })
+	if err != nil || !strings.Contains(output, "matches the generated project") {
+		t.Errorf("expected no differences, got %v:\n%s", err, output)
+	}
+
+	readme := filepath.Join(out, "README.md")
+	data, _ := os.ReadFile(readme)
+	os.WriteFile(readme, append([]byte("My notes\n"), data...), 0644)
+	os.WriteFile(filepath.Join(out, "extra.txt"), []byte("extra\n"), 0644)
+	os.Remove(filepath.Join(out, "go.mod"))
+	opts.Examples = true
+	output = captureGenOutput(func() { err = GenerateProject(opts) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"--- a/README.md\n+++ b/README.md\n",
+		"-My notes\n",
+		"--- /dev/null\n+++ b/go.mod\n",
+		"--- a/extra.txt\n+++ /dev/null\n@@ -1 +0,0 @@\n-extra\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in the diff:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, manifestDir) {
+		t.Errorf("the manifest was diffed:\n%s", output)
+	}
+	if _, err := os.Stat(filepath.Join(out, "go.mod")); !os.IsNotExist(err) {
+		t.Error("diff wrote the project")
+	}
 }
 
 func TestSelectGeneratorUnsupported(t *testing.T) {
@@ -110,3 +282,108 @@ func TestPrintNextSteps_OtherLanguages(t *testing.T) {
 		}
 	}
 }
+
+func TestApplyProjectSpec(t *testing.T) {
+	spec := &core.ProjectConfig{
+		Name:        "from-spec",
+		Language:    "python",
+		Transport:   "rest",
+		Docker:      true,
+		Author:      "Jane Doe",
+		Description: "A spec project",
+		Tools:       []core.Tool{{Name: "search"}},
+	}
+	opts := &GenerateOptions{Language: "golang", Output: "out"}
+	ApplyProjectSpec(opts, spec)
+	if opts.Name != "from-spec" || opts.Transport != "rest" || !opts.Docker || opts.Author != "Jane Doe" || opts.Description != "A spec project" {
+		t.Errorf("spec values not applied: %+v", opts)
+	}
+	if opts.Language != "golang" || opts.Output != "out" {
+		t.Errorf("options set by flags were overridden: %+v", opts)
+	}
+	if len(opts.Tools) != 1 || opts.Tools[0].Name != "search" {
+		t.Errorf("spec tools not applied: %+v", opts.Tools)
+	}
+
+	// Flags given explicitly override the spec both ways.
+	spec = &core.ProjectConfig{Docker: true}
+	opts = &GenerateOptions{DockerSet: true, Examples: true, ExamplesSet: true}
+	ApplyProjectSpec(opts, spec)
+	if opts.Docker || !opts.Examples {
+		t.Errorf("explicit flags were overridden: %+v", opts)
+	}
+}
+
+// TestGenerateProject_Conformance runs the whole test suite, conformance
+// checks included, against generated stdio servers whose toolchain is
+// installed. Subscriptions are only advertised, and then tested, when a tool
+// updates a resource.
+func TestGenerateProject_Conformance(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds and starts generated servers")
+	}
+	for _, c := range []struct {
+		language, tool string
+		command        func(t *testing.T, dir string) string
+	}{
+		{"golang", "go", func(t *testing.T, dir string) string {
+			bin := filepath.Join(dir, "server")
+			build := exec.Command("go", "build", "-o", bin, "./cmd/server")
+			build.Dir = dir
+			if out, err := build.CombinedOutput(); err != nil {
+				t.Fatalf("go build: %v\n%s", err, out)
+			}
+			return bin
+		}},
+		{"javascript", "node", func(t *testing.T, dir string) string {
+			return "node " + filepath.Join(dir, "src", "index.js")
+		}},
+		{"python", "python3", func(t *testing.T, dir string) string {
+			return "python3 " + filepath.Join(dir, "src", "main.py")
+		}},
+	} {
+		for _, updates := range []bool{false, true} {
+			name := c.language
+			tools := []core.Tool{{Name: "search"}}
+			if updates {
+				name += "/updates"
+				tools = append(tools, core.Tool{Name: "save", Updates: []string{"notes"}})
+			}
+			t.Run(name, func(t *testing.T) {
+				if _, err := exec.LookPath(c.tool); err != nil {
+					t.Skipf("%s is not installed", c.tool)
+				}
+				dir := filepath.Join(t.TempDir(), "proj")
+				opts := &GenerateOptions{Name: "proj", Language: c.language, Transport: "stdio", Output: dir,
+					Tools: tools, Resources: []core.Resource{{Name: "notes", Type: "filesystem"}}}
+				var err error
+				captureGenOutput(func() { err = GenerateProject(opts) })
+				if err != nil {
+					t.Fatal(err)
+				}
+				cfg := &core.MCPConfig{Name: "proj", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": c.command(t, dir)}}}
+				report, err := CollectTests(&TestOptions{TestAll: true, Conformance: true, Timeout: 10 * time.Second}, cfg)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(report.Suite(ConformanceSuiteName+"@"+ConformanceSuiteVersion).Cases) != len(conformanceChecks) {
+					t.Error("expected the conformance suite to run")
+				}
+				notified := false
+				for _, suite := range report.Suites {
+					for _, tc := range suite.Cases {
+						if tc.Status == StatusFailed {
+							t.Errorf("%s: %s %v", suite.Name, tc.Name, tc.Failures)
+						}
+						if suite.Name == "subscriptions" && tc.Name == core.ResourceUpdatedNotification && tc.Status == StatusPassed {
+							notified = true
+						}
+					}
+				}
+				if notified != updates {
+					t.Errorf("expected %s to be tested: %v, got %v", core.ResourceUpdatedNotification, updates, notified)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/generators"
+)
+
+var (
+	// manifestDir holds what mcpcli recorded about a generated project,
+	// relative to the project directory.
+	manifestDir  = ".mcpcli"
+	manifestPath = filepath.Join(manifestDir, "manifest.json")
+	// baseDir keeps the generated content of every file, the common
+	// ancestor of the three-way merges run by upgrade.
+	baseDir = filepath.Join(manifestDir, "base")
+)
+
+// Manifest records the templates a project was generated from and the hash
+// of every file they produced.
+type Manifest struct {
+	// TemplateVersion is the mcpcli release whose templates generated the
+	// files.
+	TemplateVersion string    `json:"templateVersion"`
+	GeneratedAt     time.Time `json:"generatedAt"`
+	// Output, Author and Description are generate options rendered into
+	// files but not kept in configs/mcp-config.json.
+	Output      string `json:"output"`
+	Author      string `json:"author,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Files maps the slash separated path of every generated file to the
+	// SHA-256 of its generated content.
+	Files map[string]string `json:"files"`
+}
+
+func newManifest(config *core.ProjectConfig) *Manifest {
+	return &Manifest{
+		TemplateVersion: config.Version,
+		GeneratedAt:     config.CreatedAt,
+		Output:          config.Output,
+		Author:          config.Author,
+		Description:     config.Description,
+		Files:           map[string]string{},
+	}
+}
+
+// LoadManifest reads the generation manifest of the project in dir. The
+// error wraps fs.ErrNotExist when the project has none.
+func LoadManifest(dir string) (*Manifest, error) {
+	path := filepath.Join(dir, manifestPath)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read generation manifest: %w", err)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, core.FormatJSONError(data, err, "failed to parse "+path)
+	}
+	if m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return &m, nil
+}
+
+func (m *Manifest) save(dir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeProjectFile(dir, manifestPath, append(data, '\n'))
+}
+
+// record sets the generated content of files, keeping a copy of each as the
+// base of later merges.
+func (m *Manifest) record(dir string, files ...generators.File) error {
+	for _, f := range files {
+		if err := writeProjectFile(dir, filepath.Join(baseDir, f.Path), f.Content); err != nil {
+			return err
+		}
+		m.Files[filepath.ToSlash(f.Path)] = hashContent(f.Content)
+	}
+	return nil
+}
+
+// forget drops a file, given by its slash separated path, from the manifest.
+func (m *Manifest) forget(dir, path string) {
+	delete(m.Files, path)
+	os.Remove(filepath.Join(dir, baseDir, filepath.FromSlash(path)))
+}
+
+// base returns the generated content of a file, when its copy matches the
+// recorded hash.
+func (m *Manifest) base(dir, path string) ([]byte, bool) {
+	sum, ok := m.Files[filepath.ToSlash(path)]
+	if !ok {
+		return nil, false
+	}
+	content, err := os.ReadFile(filepath.Join(dir, baseDir, path))
+	if err != nil || hashContent(content) != sum {
+		return nil, false
+	}
+	return content, true
+}
+
+func hashContent(content []byte) string {
+	sum := sha256.Sum256(content)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// writeManifest records the files generated for config in a new manifest
+// of the project written to dir.
+func writeManifest(dir string, config *core.ProjectConfig, files []generators.File) error {
+	m := newManifest(config)
+	if err := m.record(dir, files...); err != nil {
+		return err
+	}
+	return m.save(dir)
+}
+
+// recordGenerated updates the manifest of the project in dir, when it has
+// one, with files mcpcli wrote into it.
+func recordGenerated(dir string, files ...generators.File) error {
+	m, err := LoadManifest(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := m.record(dir, files...); err != nil {
+		return err
+	}
+	return m.save(dir)
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// MockOptions contains flags for `mcpcli mock`.
+type MockOptions struct {
+	// Config is an MCP configuration whose tools and resources are served.
+	Config string
+	// Recording is a session recorded with `mcpcli test --record` whose
+	// responses are replayed.
+	Recording string
+	// Rules is a JSON file of core.MockRules overriding responses.
+	Rules string
+	// Transport is stdio, http or websocket. It defaults to the transport of
+	// the configuration, or stdio.
+	Transport string
+	// Host, Port and Path locate the HTTP and WebSocket endpoints. They
+	// default to the transport options of the configuration.
+	Host string
+	Port int
+	Path string
+	// Latency, ErrorRate and Seed replace the global values of the rules
+	// when set.
+	Latency   time.Duration
+	ErrorRate float64
+	Seed      int64
+}
+
+// Default endpoints of the network transports, matching the ones the client
+// connects to.
+const (
+	defaultMockHost          = "localhost"
+	defaultMockHTTPPort      = 8080
+	defaultMockHTTPPath      = "/mcp"
+	defaultMockWebSocketPort = 8081
+	defaultMockWebSocketPath = "/ws"
+)
+
+// NewMockServer builds the mock described by the options. The configuration
+// it serves is returned as well, or nil when only a recording is served.
+func NewMockServer(opts *MockOptions) (*core.MockServer, *core.MCPConfig, error) {
+	if opts.Config == "" && opts.Recording == "" {
+		return nil, nil, fmt.Errorf("a config or a recording is required")
+	}
+	var config *core.MCPConfig
+	if opts.Config != "" {
+		var err error
+		if config, err = LoadMCPConfig(opts.Config); err != nil {
+			return nil, nil, err
+		}
+	}
+	var frames []core.RecordedFrame
+	if opts.Recording != "" {
+		var err error
+		if frames, err = core.LoadRecording(opts.Recording); err != nil {
+			return nil, nil, err
+		}
+	}
+	rules := &core.MockRules{}
+	if opts.Rules != "" {
+		var err error
+		if rules, err = core.LoadMockRules(opts.Rules); err != nil {
+			return nil, nil, err
+		}
+	}
+	if opts.Latency > 0 {
+		rules.Latency = opts.Latency.String()
+	}
+	if opts.ErrorRate > 0 {
+		rules.ErrorRate = opts.ErrorRate
+	}
+	if opts.Seed != 0 {
+		rules.Seed = opts.Seed
+	}
+	server, err := core.NewMockServer(config, frames, rules)
+	if err != nil {
+		return nil, nil, err
+	}
+	return server, config, nil
+}
+
+// RunMock serves the mock over the selected transport until stdin is closed,
+// for stdio, or until the process is interrupted.
+func RunMock(opts *MockOptions, stderr io.Writer) error {
+	server, config, err := NewMockServer(opts)
+	if err != nil {
+		return err
+	}
+	var transport core.Transport
+	if config != nil {
+		transport = config.Transport
+	}
+	if opts.Transport != "" {
+		transport.Type = opts.Transport
+	}
+
+	switch transport.Type {
+	case "", "stdio":
+		fmt.Fprintln(stderr, "Serving mock MCP server on stdio")
+		return server.ServeStream(os.Stdin, os.Stdout)
+	case "rest", "http", "streamable-http", "sse":
+		addr, path, err := mockEndpoint(opts, transport.Options, defaultMockHTTPPort, defaultMockHTTPPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Serving mock MCP server on http://%s%s\n", addr, path)
+		return serveMock(server, addr, path)
+	case "websocket":
+		addr, path, err := mockEndpoint(opts, transport.Options, defaultMockWebSocketPort, defaultMockWebSocketPath)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Serving mock MCP server on ws://%s%s\n", addr, path)
+		return serveMock(server, addr, path)
+	default:
+		return fmt.Errorf("unsupported transport type: %s", transport.Type)
+	}
+}
+
+// mockEndpoint returns the listen address and path, from the flags or else
+// the "host", "port" and "path" transport options.
+func mockEndpoint(opts *MockOptions, options map[string]interface{}, defaultPort int, defaultPath string) (string, string, error) {
+	host := opts.Host
+	if host == "" {
+		host, _ = options["host"].(string)
+	}
+	if host == "" {
+		host = defaultMockHost
+	}
+	port := opts.Port
+	if port == 0 {
+		port = defaultPort
+		switch p := options["port"].(type) {
+		case float64:
+			port = int(p)
+		case int:
+			port = p
+		case string:
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return "", "", fmt.Errorf("invalid transport port %q", p)
+			}
+			port = n
+		}
+	}
+	path := opts.Path
+	if path == "" {
+		path, _ = options["path"].(string)
+	}
+	if path == "" {
+		path = defaultPath
+	}
+	if path[0] != '/' {
+		path = "/" + path
+	}
+	return net.JoinHostPort(host, strconv.Itoa(port)), path, nil
+}
+
+// serveMock serves the mock over HTTP, and WebSocket upgrades, until the
+// process is interrupted.
+func serveMock(server *core.MockServer, addr, path string) error {
+	mux := http.NewServeMux()
+	mux.Handle(path, server)
+	httpServer := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.ListenAndServe() }()
+	select {
+	case err := <-errc:
+		return fmt.Errorf("mock server failed: %w", err)
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("failed to stop mock server: %w", err)
+	}
+	return nil
+}
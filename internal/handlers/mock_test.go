@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// writeMockConfig writes an MCP configuration with one tool and one resource.
+func writeMockConfig(t *testing.T, dir string) string {
+	t.Helper()
+	cfg := core.NewMCPConfig("demo", "1.0.0", "mock", []core.Tool{{Name: "echo", Description: "Echoes"}}, []core.Resource{{Name: "docs/readme", Type: "filesystem"}})
+	data, _ := json.Marshal(cfg)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestMockServer_PassesTests(t *testing.T) {
+	server, config, err := NewMockServer(&MockOptions{Config: writeMockConfig(t, t.TempDir())})
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(server)
+	defer srv.Close()
+
+	for _, transport := range []core.Transport{
+		{Type: "http", Options: map[string]interface{}{"url": srv.URL}},
+		{Type: "streamable-http", Options: map[string]interface{}{"url": srv.URL}},
+		{Type: "websocket", Options: map[string]interface{}{"url": "ws" + strings.TrimPrefix(srv.URL, "http")}},
+	} {
+		config.Transport = transport
+		opts := &TestOptions{TestAll: true, Conformance: true, Timeout: 5 * time.Second}
+		report, err := CollectTests(opts, config)
+		if err != nil {
+			t.Fatalf("%s: %v", transport.Type, err)
+		}
+		for _, suite := range report.Suites {
+			for _, c := range suite.Cases {
+				if !c.Passed() {
+					t.Errorf("%s: %s: %s %s %v", transport.Type, suite.Name, c.Name, c.Status, c.Failures)
+				}
+			}
+		}
+	}
+}
+
+func TestMockServer_ServesRecording(t *testing.T) {
+	tmp := t.TempDir()
+	config, err := LoadMCPConfig(writeMockConfig(t, tmp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, _, err := NewMockServer(&MockOptions{Config: writeMockConfig(t, tmp)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	liveSrv := httptest.NewServer(live)
+	defer liveSrv.Close()
+
+	session := filepath.Join(tmp, "session.jsonl")
+	config.Transport = core.Transport{Type: "http", Options: map[string]interface{}{"url": liveSrv.URL}}
+	if _, err := CollectTests(&TestOptions{TestTools: true, Record: session, Timeout: 5 * time.Second}, config); err != nil {
+		t.Fatal(err)
+	}
+
+	replayed, _, err := NewMockServer(&MockOptions{Recording: session})
+	if err != nil {
+		t.Fatal(err)
+	}
+	replaySrv := httptest.NewServer(replayed)
+	defer replaySrv.Close()
+	config.Transport.Options["url"] = replaySrv.URL
+	report, err := CollectReplay(&ReplayOptions{Recording: session, Timeout: 5 * time.Second}, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		for _, c := range report.Suite("replay").Cases {
+			t.Log(c.Name, c.Status, c.Failures)
+		}
+		t.Fatal(err)
+	}
+}
+
+func TestMockServer_Options(t *testing.T) {
+	tmp := t.TempDir()
+	if _, _, err := NewMockServer(&MockOptions{}); err == nil {
+		t.Error("expected an error without config or recording")
+	}
+
+	rules := filepath.Join(tmp, "rules.json")
+	os.WriteFile(rules, []byte(`{"rules":[{"method":"tools/call","error":{"code":-32001,"message":"down"}}]}`), 0644)
+	server, _, err := NewMockServer(&MockOptions{Config: writeMockConfig(t, tmp), Rules: rules})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply := server.HandleMessage(context.Background(), []byte(`{"jsonrpc":"2.0","method":"tools/call","params":{"name":"echo"},"id":1}`))
+	if !strings.Contains(string(reply), `"message":"down"`) {
+		t.Errorf("rule not applied: %s", reply)
+	}
+
+	os.WriteFile(rules, []byte(`{"latency":"later"}`), 0644)
+	if _, _, err := NewMockServer(&MockOptions{Config: writeMockConfig(t, tmp), Rules: rules}); err == nil || !strings.Contains(err.Error(), "invalid latency") {
+		t.Errorf("expected invalid latency error, got %v", err)
+	}
+}
+
+func TestMockEndpoint(t *testing.T) {
+	options := map[string]interface{}{"port": float64(9000), "path": "rpc"}
+	addr, path, err := mockEndpoint(&MockOptions{}, options, 8080, "/mcp")
+	if err != nil || addr != "localhost:9000" || path != "/rpc" {
+		t.Errorf("got %s %s %v", addr, path, err)
+	}
+	addr, path, err = mockEndpoint(&MockOptions{Host: "0.0.0.0", Port: 7000, Path: "/x"}, options, 8080, "/mcp")
+	if err != nil || addr != "0.0.0.0:7000" || path != "/x" {
+		t.Errorf("flags must win, got %s %s %v", addr, path, err)
+	}
+	if _, _, err := mockEndpoint(&MockOptions{}, map[string]interface{}{"port": "x"}, 8080, "/mcp"); err == nil {
+		t.Error("expected an invalid port error")
+	}
+}
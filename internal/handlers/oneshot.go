@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// Output formats of `mcpcli call`, `mcpcli read` and `mcpcli list`.
+const (
+	OutputJSON  = "json"
+	OutputYAML  = "yaml"
+	OutputTable = "table"
+)
+
+// OutputFormats lists the accepted output formats.
+var OutputFormats = []string{OutputJSON, OutputYAML, OutputTable}
+
+// ListKinds lists what `mcpcli list` can list.
+var ListKinds = []string{"tools", "resources", "templates", "prompts"}
+
+// ClientOptions contains the flags shared by the one-shot commands, which
+// send a single request and print its result.
+type ClientOptions struct {
+	// Config is an MCP configuration describing the server.
+	Config string
+	// Command starts a stdio server instead of Config.
+	Command string
+	// Output is one of OutputFormats and defaults to OutputJSON.
+	Output string
+	// Timeout bounds each request sent to the server. Zero disables it.
+	Timeout time.Duration
+	// ProtocolVersion is the MCP revision requested during initialization.
+	ProtocolVersion string
+}
+
+// CallOptions contains the flags of `mcpcli call`.
+type CallOptions struct {
+	ClientOptions
+	// Args are key=value arguments. Values are decoded as JSON when they are
+	// valid JSON and used as strings otherwise.
+	Args []string
+	// JSONArgs is a file holding the arguments as a JSON object, or "-" for
+	// stdin. Args override its members.
+	JSONArgs string
+}
+
+// clientConfig returns the configuration of the server to connect to.
+func clientConfig(opts *ClientOptions) (*core.MCPConfig, error) {
+	switch {
+	case opts.Config != "" && opts.Command != "":
+		return nil, fmt.Errorf("use either --config or --command, not both")
+	case opts.Command != "":
+		return &core.MCPConfig{Transport: core.Transport{Type: "stdio", Options: map[string]interface{}{"command": opts.Command}}}, nil
+	case opts.Config != "":
+		config, err := LoadMCPConfig(opts.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		return config, nil
+	}
+	return nil, fmt.Errorf("--config or --command is required")
+}
+
+// validateOutput checks the output format, defaulting it to JSON.
+func validateOutput(opts *ClientOptions) error {
+	if opts.Output == "" {
+		opts.Output = OutputJSON
+	}
+	for _, f := range OutputFormats {
+		if f == opts.Output {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported output format %q, valid formats are %v", opts.Output, OutputFormats)
+}
+
+// connectClient starts the server, or connects to it, and initializes the
+// session.
+func connectClient(opts *ClientOptions) (*core.MCPClient, error) {
+	config, err := clientConfig(opts)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := core.NewClientTransport(config.Transport)
+	if err != nil {
+		return nil, err
+	}
+	client := core.NewMCPClientWithTransport(transport, os.Stderr)
+	version := opts.ProtocolVersion
+	if version == "" {
+		version = core.LatestProtocolVersion
+	}
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	defer cancel()
+	if _, err := client.InitializeContext(ctx, version, core.Implementation{Name: "mcpcli", Version: core.CLIVersion}, core.ClientCapabilities{}, nil); err != nil {
+		msg := describeCallError(client, err)
+		client.Close()
+		return nil, fmt.Errorf("failed to initialize: %s", msg)
+	}
+	return client, nil
+}
+
+// ParseToolArguments builds tool arguments from a JSON file and key=value
+// pairs.
+func ParseToolArguments(args []string, jsonArgs string) (map[string]interface{}, error) {
+	arguments := map[string]interface{}{}
+	if jsonArgs != "" {
+		var data []byte
+		var err error
+		if jsonArgs == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(jsonArgs)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read arguments: %w", err)
+		}
+		if err := json.Unmarshal(data, &arguments); err != nil {
+			return nil, core.FormatJSONError(data, err, "arguments must be a JSON object")
+		}
+		if arguments == nil {
+			return nil, fmt.Errorf("arguments must be a JSON object, got null")
+		}
+	}
+	for _, arg := range args {
+		key, raw, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid argument %q, expected key=value", arg)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		arguments[key] = value
+	}
+	return arguments, nil
+}
+
+// RunCall calls a tool and prints its result. It fails when the server
+// answers with an error or the tool reports one.
+func RunCall(opts *CallOptions, tool string, out io.Writer) error {
+	if err := validateOutput(&opts.ClientOptions); err != nil {
+		return err
+	}
+	arguments, err := ParseToolArguments(opts.Args, opts.JSONArgs)
+	if err != nil {
+		return err
+	}
+	client, err := connectClient(&opts.ClientOptions)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	defer cancel()
+	resp, err := client.CallToolContext(ctx, tool, arguments, nil)
+	result, err := oneShotResult(client, "tools/call", resp, err)
+	if err != nil {
+		return err
+	}
+	if err := printResult(out, opts.Output, result, callTable); err != nil {
+		return err
+	}
+	if obj, ok := result.(map[string]interface{}); ok && obj["isError"] == true {
+		return fmt.Errorf("tool %s reported an error", tool)
+	}
+	return nil
+}
+
+// RunRead reads a resource and prints its contents.
+func RunRead(opts *ClientOptions, uri string, out io.Writer) error {
+	if err := validateOutput(opts); err != nil {
+		return err
+	}
+	client, err := connectClient(opts)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	defer cancel()
+	resp, err := client.ReadResourceContext(ctx, uri, nil)
+	result, err := oneShotResult(client, "resources/read", resp, err)
+	if err != nil {
+		return err
+	}
+	return printResult(out, opts.Output, result, readTable)
+}
+
+// RunList lists the tools, resources, resource templates or prompts of the
+// server, following pagination, and prints them.
+func RunList(opts *ClientOptions, kind string, out io.Writer) error {
+	if !containsKind(kind) {
+		return fmt.Errorf("cannot list %q, valid kinds are %v", kind, ListKinds)
+	}
+	if err := validateOutput(opts); err != nil {
+		return err
+	}
+	client, err := connectClient(opts)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var first func(context.Context, interface{}) (*core.Response, error)
+	var table func(map[string]interface{}) [][]string
+	method, key := kind+"/list", kind
+	switch kind {
+	case "tools":
+		first, table = client.ListToolsContext, toolsTable
+	case "resources":
+		first, table = client.ListResourcesContext, resourcesTable
+	case "templates":
+		first, table = client.ListResourceTemplatesContext, templatesTable
+		method, key = "resources/templates/list", "resourceTemplates"
+	case "prompts":
+		first, table = client.ListPromptsContext, promptsTable
+	}
+
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	defer cancel()
+	resp, callErr := first(ctx, nil)
+	var items []interface{}
+	for {
+		result, err := oneShotResult(client, method, resp, callErr)
+		if err != nil {
+			return err
+		}
+		page, ok := result.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s returned %s, expected an object", method, marshalCompact(result))
+		}
+		pageItems, _ := page[key].([]interface{})
+		items = append(items, pageItems...)
+		cursor, _ := page["nextCursor"].(string)
+		if cursor == "" {
+			break
+		}
+		resp, callErr = client.CallContext(ctx, method, map[string]interface{}{"cursor": cursor}, nil)
+	}
+	if items == nil {
+		items = []interface{}{}
+	}
+	listed := map[string]interface{}{key: items}
+	return printResult(out, opts.Output, listed, func(v interface{}) [][]string {
+		return table(v.(map[string]interface{}))
+	})
+}
+
+func containsKind(kind string) bool {
+	for _, k := range ListKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// oneShotResult returns the result of a response, or an error describing why
+// there is none.
+func oneShotResult(client *core.MCPClient, method string, resp *core.Response, err error) (interface{}, error) {
+	if err != nil {
+		return nil, fmt.Errorf("%s failed: %s", method, describeCallError(client, err))
+	}
+	if resp.Error != nil {
+		msg := fmt.Sprintf("%s failed: %s (code %d)", method, resp.Error.Message, resp.Error.Code)
+		if resp.Error.Data != nil {
+			msg += ": " + marshalCompact(resp.Error.Data)
+		}
+		return nil, fmt.Errorf("%s", msg)
+	}
+	return resp.Result, nil
+}
+
+// printResult writes a result in the requested format. Tables are built by
+// rows, whose first row is the header.
+func printResult(out io.Writer, format string, result interface{}, rows func(interface{}) [][]string) error {
+	switch format {
+	case OutputYAML:
+		data, err := core.MarshalYAML(result)
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		_, err = out.Write(data)
+		return err
+	case OutputTable:
+		w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		for _, row := range rows(result) {
+			fmt.Fprintln(w, strings.Join(row, "\t"))
+		}
+		return w.Flush()
+	default:
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode result: %w", err)
+		}
+		_, err = fmt.Fprintln(out, string(data))
+		return err
+	}
+}
+
+// cell returns a string member flattened to one line.
+func cell(obj map[string]interface{}, key string) string {
+	s, _ := obj[key].(string)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func objects(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+	var objs []map[string]interface{}
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+func toolsTable(result map[string]interface{}) [][]string {
+	rows := [][]string{{"NAME", "DESCRIPTION"}}
+	for _, tool := range objects(result["tools"]) {
+		rows = append(rows, []string{cell(tool, "name"), cell(tool, "description")})
+	}
+	return rows
+}
+
+func resourcesTable(result map[string]interface{}) [][]string {
+	rows := [][]string{{"URI", "NAME", "MIME TYPE"}}
+	for _, r := range objects(result["resources"]) {
+		rows = append(rows, []string{cell(r, "uri"), cell(r, "name"), cell(r, "mimeType")})
+	}
+	return rows
+}
+
+func templatesTable(result map[string]interface{}) [][]string {
+	rows := [][]string{{"URI TEMPLATE", "NAME", "MIME TYPE"}}
+	for _, r := range objects(result["resourceTemplates"]) {
+		rows = append(rows, []string{cell(r, "uriTemplate"), cell(r, "name"), cell(r, "mimeType")})
+	}
+	return rows
+}
+
+func promptsTable(result map[string]interface{}) [][]string {
+	rows := [][]string{{"NAME", "ARGUMENTS", "DESCRIPTION"}}
+	for _, p := range objects(result["prompts"]) {
+		var args []string
+		for _, a := range objects(p["arguments"]) {
+			name := cell(a, "name")
+			if a["required"] == true {
+				name += "*"
+			}
+			args = append(args, name)
+		}
+		rows = append(rows, []string{cell(p, "name"), strings.Join(args, ","), cell(p, "description")})
+	}
+	return rows
+}
+
+func callTable(result interface{}) [][]string {
+	obj, _ := result.(map[string]interface{})
+	rows := [][]string{{"TYPE", "CONTENT"}}
+	for _, item := range objects(obj["content"]) {
+		rows = append(rows, []string{cell(item, "type"), contentSummary(item)})
+	}
+	if structured, ok := obj["structuredContent"]; ok {
+		rows = append(rows, []string{"structured", marshalCompact(structured)})
+	}
+	return rows
+}
+
+func readTable(result interface{}) [][]string {
+	obj, _ := result.(map[string]interface{})
+	rows := [][]string{{"URI", "MIME TYPE", "CONTENT"}}
+	for _, item := range objects(obj["contents"]) {
+		rows = append(rows, []string{cell(item, "uri"), cell(item, "mimeType"), contentSummary(item)})
+	}
+	return rows
+}
+
+// contentSummary shows text content on one line and describes binary and
+// embedded content.
+func contentSummary(item map[string]interface{}) string {
+	if text, ok := item["text"].(string); ok {
+		return strings.ReplaceAll(strings.ReplaceAll(text, "\r", `\r`), "\n", `\n`)
+	}
+	for _, key := range []string{"data", "blob"} {
+		if data, ok := item[key].(string); ok {
+			return fmt.Sprintf("<%d bytes of base64 %s>", len(data), cell(item, "mimeType"))
+		}
+	}
+	if resource, ok := item["resource"].(map[string]interface{}); ok {
+		return cell(resource, "uri")
+	}
+	return cell(item, "uri")
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func oneShotOptions(t *testing.T, output string) ClientOptions {
+	return ClientOptions{Command: mcptest.Command(t), Output: output, Timeout: 5 * time.Second}
+}
+
+func TestParseToolArguments(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "args.json")
+	os.WriteFile(file, []byte(`{"message": "from file", "count": 1}`), 0644)
+	got, err := ParseToolArguments([]string{"count=2", "flag=true", "name=plain text", "list=[1,\"a\"]", "quoted=\"42\"", "empty="}, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]interface{}{
+		"message": "from file",
+		"count":   float64(2),
+		"flag":    true,
+		"name":    "plain text",
+		"list":    []interface{}{float64(1), "a"},
+		"quoted":  "42",
+		"empty":   "",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	for _, tc := range []struct {
+		args     []string
+		jsonArgs string
+		want     string
+	}{
+		{[]string{"novalue"}, "", "expected key=value"},
+		{[]string{"=x"}, "", "expected key=value"},
+		{nil, filepath.Join(t.TempDir(), "missing.json"), "failed to read arguments"},
+	} {
+		if _, err := ParseToolArguments(tc.args, tc.jsonArgs); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v %s: expected error containing %q, got %v", tc.args, tc.jsonArgs, tc.want, err)
+		}
+	}
+	os.WriteFile(file, []byte(`[1]`), 0644)
+	if _, err := ParseToolArguments(nil, file); err == nil || !strings.Contains(err.Error(), "JSON object") {
+		t.Errorf("expected an object error, got %v", err)
+	}
+}
+
+func TestRunCall(t *testing.T) {
+	var out bytes.Buffer
+	opts := &CallOptions{ClientOptions: oneShotOptions(t, OutputJSON), Args: []string{"message=hello"}}
+	if err := RunCall(opts, mcptest.EchoTool, &out); err != nil {
+		t.Fatal(err)
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out.String())
+	}
+	if got := marshalCompact(result); got != `{"content":[{"text":"hello","type":"text"}]}` {
+		t.Errorf("unexpected result %s", got)
+	}
+
+	out.Reset()
+	opts.Output = OutputTable
+	opts.Args = []string{"message=two\nlines"}
+	if err := RunCall(opts, mcptest.EchoTool, &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "TYPE  CONTENT\ntext  two\\nlines\n" {
+		t.Errorf("unexpected table %q", got)
+	}
+
+	out.Reset()
+	opts.Args = nil
+	if err := RunCall(opts, mcptest.EchoTool, &out); err == nil || !strings.Contains(err.Error(), "tools/call failed: message must be a string (code -32602)") {
+		t.Errorf("expected the server error, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("nothing must be printed on errors, got %q", out.String())
+	}
+}
+
+func TestRunCall_ToolError(t *testing.T) {
+	rules := &core.MockRules{Rules: []core.MockRule{{Method: "tools/call", Result: map[string]interface{}{
+		"content": []interface{}{map[string]interface{}{"type": "text", "text": "boom"}},
+		"isError": true,
+	}}}}
+	mock, err := core.NewMockServer(&core.MCPConfig{Tools: []core.Tool{{Name: "fail"}}}, nil, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(mock)
+	defer srv.Close()
+	cfg := filepath.Join(t.TempDir(), "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Transport: core.Transport{Type: "http", Options: map[string]interface{}{"url": srv.URL}}})
+	os.WriteFile(cfg, data, 0644)
+
+	var out bytes.Buffer
+	opts := &CallOptions{ClientOptions: ClientOptions{Config: cfg, Output: OutputYAML}}
+	if err := RunCall(opts, "fail", &out); err == nil || err.Error() != "tool fail reported an error" {
+		t.Errorf("expected a tool error, got %v", err)
+	}
+	if got := out.String(); got != "content:\n  - text: boom\n    type: text\nisError: true\n" {
+		t.Errorf("the result must still be printed, got %q", got)
+	}
+}
+
+func TestRunRead(t *testing.T) {
+	var out bytes.Buffer
+	opts := oneShotOptions(t, OutputYAML)
+	if err := RunRead(&opts, mcptest.GreetingURI, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "contents:\n  - mimeType: text/plain\n    text: " + mcptest.GreetingText + "\n    uri: " + mcptest.GreetingURI + "\n"
+	if out.String() != want {
+		t.Errorf("got %q, want %q", out.String(), want)
+	}
+
+	if err := RunRead(&opts, "file:///missing", &out); err == nil || !strings.Contains(err.Error(), "(code -32002)") {
+		t.Errorf("expected a resource error, got %v", err)
+	}
+}
+
+func TestRunList(t *testing.T) {
+	var out bytes.Buffer
+	opts := oneShotOptions(t, OutputTable)
+	if err := RunList(&opts, "resources", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "URI                   NAME      MIME TYPE\n"+mcptest.GreetingURI+"  greeting  text/plain\n" {
+		t.Errorf("unexpected table %q", got)
+	}
+
+	if err := RunList(&opts, "templates", &out); err == nil || !strings.Contains(err.Error(), "resources/templates/list failed: Method not found") {
+		t.Errorf("expected a method not found error, got %v", err)
+	}
+	if err := RunList(&opts, "prompts", &out); err == nil || !strings.Contains(err.Error(), "prompts/list failed: Method not found") {
+		t.Errorf("expected a method not found error, got %v", err)
+	}
+	if err := RunList(&opts, "widgets", &out); err == nil || !strings.Contains(err.Error(), `cannot list "widgets"`) {
+		t.Errorf("expected an invalid kind error, got %v", err)
+	}
+	opts.Output = "xml"
+	if err := RunList(&opts, "tools", &out); err == nil || !strings.Contains(err.Error(), "unsupported output format") {
+		t.Errorf("expected an invalid format error, got %v", err)
+	}
+}
+
+func TestTemplatesTable(t *testing.T) {
+	rows := templatesTable(map[string]interface{}{"resourceTemplates": []interface{}{
+		map[string]interface{}{"uriTemplate": "file:///{path}", "name": "files", "mimeType": "text/plain"},
+	}})
+	if len(rows) != 2 || rows[1][0] != "file:///{path}" || rows[1][1] != "files" || rows[1][2] != "text/plain" {
+		t.Errorf("unexpected rows %v", rows)
+	}
+}
+
+func TestRunList_Pagination(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := mcptest.Handle(&req)
+		if req.Method == "tools/list" {
+			page := map[string]interface{}{"tools": []interface{}{map[string]interface{}{"name": "first", "description": "One\nmore line"}}, "nextCursor": "2"}
+			if req.Params["cursor"] == "2" {
+				page = map[string]interface{}{"tools": []interface{}{map[string]interface{}{"name": "second"}}}
+			}
+			resp = &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID, Result: page}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+	cfg := filepath.Join(t.TempDir(), "config.json")
+	data, _ := json.Marshal(&core.MCPConfig{Transport: core.Transport{Type: "http", Options: map[string]interface{}{"url": srv.URL}}})
+	os.WriteFile(cfg, data, 0644)
+
+	var out bytes.Buffer
+	opts := &ClientOptions{Config: cfg, Output: OutputTable}
+	if err := RunList(opts, "tools", &out); err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != "NAME    DESCRIPTION\nfirst   One more line\nsecond  \n" {
+		t.Errorf("unexpected table %q", got)
+	}
+}
+
+func TestClientConfig(t *testing.T) {
+	if _, err := clientConfig(&ClientOptions{}); err == nil || !strings.Contains(err.Error(), "--config or --command is required") {
+		t.Errorf("expected a missing server error, got %v", err)
+	}
+	if _, err := clientConfig(&ClientOptions{Config: "a.json", Command: "server"}); err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("expected a conflict error, got %v", err)
+	}
+	cfg, err := clientConfig(&ClientOptions{Command: "python server.py"})
+	if err != nil || cfg.Transport.Type != "stdio" || cfg.Transport.Options["command"] != "python server.py" {
+		t.Errorf("unexpected config %+v, %v", cfg, err)
+	}
+}
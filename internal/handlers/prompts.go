@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// samplePromptArgument is the value given to the required arguments of a
+// prompt retrieved by the prompts suite.
+const samplePromptArgument = "example"
+
+// runPromptCases checks the prompts listed in a prompts/list response: each
+// prompt is retrieved with its required arguments filled in, and prompts
+// answering with an error or returning malformed messages fail.
+func runPromptCases(client *core.MCPClient, opts *TestOptions, listed *core.Response, id *int, suite *TestSuite) {
+	result, err := core.DecodeListPromptsResult(listed)
+	if err != nil {
+		suite.Add(&TestCase{Name: "prompts/list result", Status: StatusFailed, Message: err.Error(), Response: listed})
+		return
+	}
+	for _, prompt := range result.Prompts {
+		suite.Add(runPromptCase(client, opts, prompt, id))
+	}
+}
+
+func runPromptCase(client *core.MCPClient, opts *TestOptions, prompt core.PromptDefinition, id *int) *TestCase {
+	tc := &TestCase{Name: "prompts/get " + prompt.Name, Status: StatusFailed}
+	if prompt.Name == "" {
+		tc.Message = "Prompt without a name"
+		return tc
+	}
+	args := map[string]string{}
+	for _, arg := range prompt.Arguments {
+		if arg.Required {
+			args[arg.Name] = samplePromptArgument
+		}
+	}
+
+	params := map[string]interface{}{"name": prompt.Name}
+	if len(args) > 0 {
+		params["arguments"] = args
+	}
+	tc.Request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: "prompts/get", Params: params, ID: *id}
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	resp, err := client.GetPromptContext(ctx, prompt.Name, args, *id)
+	tc.Duration = time.Since(start)
+	cancel()
+	*id++
+
+	switch {
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Prompt %s: %s", prompt.Name, describeCallError(client, err))
+		return tc
+	case resp.Error != nil:
+		tc.Response = resp
+		tc.Message = fmt.Sprintf("Prompt %s: prompts/get failed: %s (code %d)", prompt.Name, resp.Error.Message, resp.Error.Code)
+		return tc
+	}
+	tc.Response = resp
+	if problems := core.CheckPromptResult(resp.Result); len(problems) > 0 {
+		tc.Message = fmt.Sprintf("Prompt %s: malformed result", prompt.Name)
+		for _, p := range problems {
+			tc.Failures = append(tc.Failures, p.Error())
+		}
+		return tc
+	}
+	messages, _ := resp.Result.(map[string]interface{})["messages"].([]interface{})
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("Prompt %s: %d message(s) with arguments %s", prompt.Name, len(messages), marshalCompact(args))
+	return tc
+}
@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// newPromptServer serves prompts/list and prompts/get over HTTP, with prompts
+// exercising each failure the prompts suite detects. It advertises prompts
+// when advertise is set.
+func newPromptServer(t *testing.T, advertise bool) *httptest.Server {
+	prompts := []interface{}{
+		map[string]interface{}{"name": "good", "arguments": []interface{}{
+			map[string]interface{}{"name": "code", "required": true},
+			map[string]interface{}{"name": "style"},
+		}},
+		map[string]interface{}{"name": "malformed"},
+		map[string]interface{}{"name": "failing"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		switch req.Method {
+		case "initialize":
+			result := core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "prompts", Version: "1.0.0"}}
+			if advertise {
+				result.Capabilities.Prompts = &core.ServerPromptsCapability{}
+			}
+			resp.Result = result
+		case "prompts/list":
+			resp.Result = map[string]interface{}{"prompts": prompts}
+		case "prompts/get":
+			args, _ := req.Params["arguments"].(map[string]interface{})
+			switch req.Params["name"] {
+			case "good":
+				if _, ok := args["style"]; ok || args["code"] != samplePromptArgument {
+					resp = core.NewErrorResponse(req.ID, core.InvalidParams, "unexpected arguments", nil)
+					break
+				}
+				resp.Result = map[string]interface{}{"messages": []interface{}{
+					map[string]interface{}{"role": "user", "content": map[string]interface{}{"type": "text", "text": "Review this"}},
+				}}
+			case "malformed":
+				resp.Result = map[string]interface{}{"messages": []interface{}{map[string]interface{}{"role": "system", "content": "hi"}}}
+			case "failing":
+				resp = core.NewErrorResponse(req.ID, core.InternalError, "boom", nil)
+			}
+		default:
+			resp = core.NewErrorResponse(req.ID, core.MethodNotFound, "Method not found", nil)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectTests_Prompts(t *testing.T) {
+	srv := newPromptServer(t, false)
+	opts := &TestOptions{TestPrompts: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "prompts", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("prompts").Cases
+	if len(cases) != 4 {
+		t.Fatalf("expected list and 3 prompt cases, got %d", len(cases))
+	}
+	want := []struct {
+		name    string
+		status  TestStatus
+		message string
+	}{
+		{"prompts", StatusPassed, "Prompts:"},
+		{"prompts/get good", StatusPassed, `Prompt good: 1 message(s) with arguments {"code":"example"}`},
+		{"prompts/get malformed", StatusFailed, "Prompt malformed: malformed result"},
+		{"prompts/get failing", StatusFailed, "prompts/get failed: boom (code -32603)"},
+	}
+	for i, w := range want {
+		c := cases[i]
+		if c.Name != w.name || c.Status != w.status || !strings.Contains(c.Message, w.message) {
+			t.Errorf("case %d: got %s %s %q, want %s %s %q", i, c.Name, c.Status, c.Message, w.name, w.status, w.message)
+		}
+	}
+	if f := cases[2].Failures; len(f) != 2 || !strings.Contains(f[0], "role must be") || !strings.Contains(f[1], "must be an object") {
+		t.Errorf("unexpected failures: %v", f)
+	}
+	if cases[3].Request == nil || cases[3].Response == nil {
+		t.Error("failed calls should record the request and response")
+	}
+}
+
+func TestCollectTests_AllPromptsWhenAdvertised(t *testing.T) {
+	for _, advertise := range []bool{false, true} {
+		srv := newPromptServer(t, advertise)
+		opts := &TestOptions{TestAll: true, Timeout: 5 * time.Second}
+		cfg := &core.MCPConfig{Name: "prompts", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+		report, err := CollectTests(opts, cfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ran := false
+		for _, s := range report.Suites {
+			ran = ran || s.Name == "prompts"
+		}
+		if ran != advertise {
+			t.Errorf("advertised %v: prompts suite run %v", advertise, ran)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// ReplayOptions contains flags for `mcpcli replay`.
+type ReplayOptions struct {
+	Config string
+	// Recording is the session recorded with `mcpcli test --record`.
+	Recording string
+	// Ignore lists JSONPath expressions, evaluated against each response,
+	// of volatile values left out of the comparison.
+	Ignore []string
+	// Timeout bounds each request sent to the server. Zero disables it.
+	Timeout time.Duration
+	// Report and ReportFile select a machine-readable report, as for
+	// `mcpcli test`.
+	Report     string
+	ReportFile string
+}
+
+// replayMessage is the part of a recorded message used to pair requests with
+// their responses.
+type replayMessage struct {
+	Method string          `json:"method"`
+	ID     json.RawMessage `json:"id"`
+}
+
+// hasID reports whether the message carries a non-null ID.
+func (m *replayMessage) hasID() bool {
+	return len(m.ID) > 0 && string(m.ID) != "null"
+}
+
+// RunReplay re-sends the requests of a recorded session to the server
+// described by config, compares the responses with the recorded ones and
+// prints the results, or writes them in the requested report format.
+func RunReplay(opts *ReplayOptions, config *core.MCPConfig) error {
+	format, err := resolveReportFormat(opts.Report, opts.ReportFile)
+	if err != nil {
+		return err
+	}
+	report, err := CollectReplay(opts, config)
+	if err != nil {
+		return err
+	}
+	return writeResults(report, format, opts.ReportFile)
+}
+
+// CollectReplay replays a recorded session and returns one case per request.
+// A case fails when the response differs from the recorded one outside the
+// ignored paths. Notifications are re-sent as is and responses the client
+// sent to server requests are skipped.
+func CollectReplay(opts *ReplayOptions, config *core.MCPConfig) (*TestReport, error) {
+	frames, err := core.LoadRecording(opts.Recording)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := core.DiffJSON(nil, nil, opts.Ignore); err != nil {
+		return nil, fmt.Errorf("invalid ignore path: %w", err)
+	}
+
+	transport, err := core.NewClientTransport(config.Transport)
+	if err != nil {
+		return nil, err
+	}
+	client := core.NewMCPClientWithTransport(transport, os.Stderr)
+	defer client.Close()
+
+	report := &TestReport{Started: time.Now()}
+	defer func() { report.Duration = time.Since(report.Started) }()
+	suite := report.Suite("replay")
+
+	recorded := recordedResponses(frames)
+	for i, frame := range frames {
+		if frame.Direction != core.DirectionSend {
+			continue
+		}
+		var msg replayMessage
+		if json.Unmarshal(frame.Bytes(), &msg) != nil {
+			// Malformed messages are answered with a null ID, if at all.
+			msg = replayMessage{}
+		} else if msg.Method == "" {
+			continue
+		}
+		name := fmt.Sprintf("%s (line %d)", msg.Method, i+1)
+		if msg.Method == "" {
+			name = fmt.Sprintf("malformed message (line %d)", i+1)
+		}
+
+		var expected *core.RecordedFrame
+		var id interface{}
+		if msg.hasID() {
+			json.Unmarshal(msg.ID, &id)
+			expected = recorded[core.IDKey(id)]
+		} else {
+			expected = nullIDResponse(frames, i)
+		}
+		if expected == nil {
+			// Nothing to compare: notifications and requests whose response
+			// was not recorded are sent without waiting.
+			if err := client.Transport().Send(context.Background(), frame.Bytes()); err != nil {
+				suite.Add(&TestCase{Name: name, Status: StatusError, Message: fmt.Sprintf("%s: %v", name, err)})
+			}
+			continue
+		}
+		suite.Add(replayCase(client, opts, name, frame, id, expected))
+	}
+	return report, nil
+}
+
+// recordedResponses indexes the responses received in a recording by ID.
+func recordedResponses(frames []core.RecordedFrame) map[string]*core.RecordedFrame {
+	responses := map[string]*core.RecordedFrame{}
+	for i := range frames {
+		f := &frames[i]
+		var msg replayMessage
+		if f.Direction != core.DirectionReceive || json.Unmarshal(f.Bytes(), &msg) != nil || msg.Method != "" || !msg.hasID() {
+			continue
+		}
+		var id interface{}
+		json.Unmarshal(msg.ID, &id)
+		responses[core.IDKey(id)] = f
+	}
+	return responses
+}
+
+// nullIDResponse returns the response without ID received after the frame at
+// index sent and before the next message sent, if any.
+func nullIDResponse(frames []core.RecordedFrame, sent int) *core.RecordedFrame {
+	for i := sent + 1; i < len(frames); i++ {
+		f := &frames[i]
+		if f.Direction == core.DirectionSend {
+			return nil
+		}
+		var msg replayMessage
+		if json.Unmarshal(f.Bytes(), &msg) == nil && msg.Method == "" && !msg.hasID() {
+			return f
+		}
+	}
+	return nil
+}
+
+// replayCase re-sends a recorded request and compares the response with the
+// recorded one.
+func replayCase(client *core.MCPClient, opts *ReplayOptions, name string, frame core.RecordedFrame, id interface{}, expected *core.RecordedFrame) *TestCase {
+	tc := &TestCase{Name: name, Status: StatusFailed}
+	var req core.Request
+	if json.Unmarshal(frame.Bytes(), &req) == nil {
+		tc.Request = &req
+	} else {
+		tc.Failures = append(tc.Failures, "sent: "+string(frame.Bytes()))
+	}
+
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	start := time.Now()
+	resp, err := client.CallRaw(ctx, frame.Bytes(), id)
+	tc.Duration = time.Since(start)
+	cancel()
+	if err != nil {
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("%s: %s", name, describeCallError(client, err))
+		return tc
+	}
+	tc.Response = resp
+
+	var want interface{}
+	if err := json.Unmarshal(expected.Bytes(), &want); err != nil {
+		tc.Message = fmt.Sprintf("%s: invalid recorded response: %v", name, err)
+		return tc
+	}
+	diffs, _ := core.DiffJSON(want, resp, opts.Ignore)
+	if len(diffs) > 0 {
+		tc.Message = fmt.Sprintf("%s: response differs from the recording", name)
+		tc.Failures = append(tc.Failures, diffs...)
+		return tc
+	}
+	tc.Status = StatusPassed
+	tc.Failures = nil
+	tc.Message = fmt.Sprintf("%s: response matches the recording", name)
+	return tc
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+// recordSession records `mcpcli test --tools --conformance` against the test
+// server.
+func recordSession(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+	cfg := &core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	opts := &TestOptions{TestTools: true, Conformance: true, Record: path, Timeout: 5 * time.Second}
+	if _, err := CollectTests(opts, cfg); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCollectTests_Record(t *testing.T) {
+	frames, err := core.LoadRecording(recordSession(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sent, received int
+	for _, f := range frames {
+		if f.Time.IsZero() {
+			t.Errorf("frame without timestamp: %+v", f)
+		}
+		switch f.Direction {
+		case core.DirectionSend:
+			sent++
+		case core.DirectionReceive:
+			received++
+		}
+	}
+	if sent == 0 || received == 0 {
+		t.Fatalf("expected frames in both directions, got %d sent and %d received", sent, received)
+	}
+	if !strings.Contains(string(frames[0].Bytes()), `"method":"initialize"`) {
+		t.Errorf("expected initialize first, got %s", frames[0].Bytes())
+	}
+	var raw bool
+	for _, f := range frames {
+		raw = raw || f.Raw != ""
+	}
+	if !raw {
+		t.Error("expected the malformed conformance message to be recorded raw")
+	}
+}
+
+func TestCollectReplay(t *testing.T) {
+	recording := recordSession(t)
+	cfg := &core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	report, err := CollectReplay(&ReplayOptions{Recording: recording, Timeout: 5 * time.Second}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("replay").Cases
+	if len(cases) < 5 {
+		t.Fatalf("expected a case per request, got %d", len(cases))
+	}
+	var malformed bool
+	for _, c := range cases {
+		if !c.Passed() {
+			t.Errorf("%s: %s %v", c.Name, c.Message, c.Failures)
+		}
+		malformed = malformed || strings.HasPrefix(c.Name, "malformed message")
+	}
+	if !malformed {
+		t.Error("expected the malformed message to be replayed and compared")
+	}
+	if cases[0].Name != "initialize (line 1)" {
+		t.Errorf("expected initialize first, got %s", cases[0].Name)
+	}
+}
+
+// newDriftedServer serves the test server over HTTP with another server
+// version and echo results in upper case.
+func newDriftedServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			json.NewEncoder(w).Encode(core.NewErrorResponse(nil, core.ParseError, "Parse error", nil))
+			return
+		}
+		if req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := mcptest.Handle(&req)
+		if result, ok := resp.Result.(core.InitializeResult); ok {
+			result.ServerInfo.Version = "2.0.0"
+			resp.Result = result
+		}
+		if req.Method == "tools/call" && resp.Error == nil {
+			resp.Result = map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "CHANGED"}}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectReplay_Differences(t *testing.T) {
+	recording := recordSession(t)
+	srv := newDriftedServer(t)
+	cfg := &core.MCPConfig{Name: "drifted", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+
+	report, err := CollectReplay(&ReplayOptions{Recording: recording, Timeout: 5 * time.Second}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failures := map[string][]string{}
+	for _, c := range report.Suite("replay").Cases {
+		if !c.Passed() {
+			failures[c.Name[:strings.Index(c.Name, " ")]] = c.Failures
+		}
+	}
+	if len(failures) != 2 {
+		t.Fatalf("expected initialize and tools/call to differ, got %v", failures)
+	}
+	if f := failures["initialize"]; len(f) != 1 || f[0] != `$.result.serverInfo.version: expected "1.0.0", got "2.0.0"` {
+		t.Errorf("unexpected initialize differences %q", f)
+	}
+	if f := failures["tools/call"]; len(f) != 1 || !strings.HasPrefix(f[0], "$.result.content[0].text: expected") {
+		t.Errorf("unexpected tools/call differences %q", f)
+	}
+
+	opts := &ReplayOptions{Recording: recording, Timeout: 5 * time.Second, Ignore: []string{"$.result.serverInfo.version", "$.result.content[*].text"}}
+	report, err = CollectReplay(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := report.Err(); err != nil {
+		t.Errorf("expected ignored paths to pass: %v", err)
+	}
+}
+
+func TestCollectReplay_Errors(t *testing.T) {
+	cfg := &core.MCPConfig{Name: "test", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "true"}}}
+	if _, err := CollectReplay(&ReplayOptions{Recording: filepath.Join(t.TempDir(), "missing.jsonl")}, cfg); err == nil {
+		t.Error("expected error for missing recording")
+	}
+	recording := recordSession(t)
+	if _, err := CollectReplay(&ReplayOptions{Recording: recording, Ignore: []string{"result"}}, cfg); err == nil || !strings.Contains(err.Error(), "invalid ignore path") {
+		t.Errorf("expected invalid ignore path error, got %v", err)
+	}
+}
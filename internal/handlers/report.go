@@ -0,0 +1,364 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// ReportFormats lists the machine-readable formats supported by
+// `mcpcli test --report`.
+var ReportFormats = []string{"junit", "tap", "json"}
+
+// ValidateReportFormat checks that format is one of ReportFormats.
+func ValidateReportFormat(format string) error {
+	if !contains(ReportFormats, format) {
+		return fmt.Errorf("invalid report format: %s, valid options are: %v", format, ReportFormats)
+	}
+	return nil
+}
+
+// WriteReport renders the report in the given format.
+func WriteReport(w io.Writer, format string, r *TestReport) error {
+	switch format {
+	case "junit":
+		return WriteJUnitReport(w, r)
+	case "tap":
+		return WriteTAPReport(w, r)
+	case "json":
+		return WriteJSONReport(w, r)
+	default:
+		return ValidateReportFormat(format)
+	}
+}
+
+// resolveReportFormat returns the report format selected by the --report and
+// --report-file flags: a report file defaults to JUnit.
+func resolveReportFormat(format, file string) (string, error) {
+	if format == "" && file != "" {
+		format = "junit"
+	}
+	if format != "" {
+		if err := ValidateReportFormat(format); err != nil {
+			return "", err
+		}
+	}
+	return format, nil
+}
+
+// writeResults prints the report for humans and writes it to file in format,
+// or prints it in format instead when no file is given. It returns the
+// report's error so that failed tests make the command fail.
+func writeResults(report *TestReport, format, file string) error {
+	switch {
+	case file != "":
+		WriteTextReport(os.Stdout, report)
+		if err := writeReportFile(file, format, report); err != nil {
+			return err
+		}
+	case format != "":
+		if err := WriteReport(os.Stdout, format, report); err != nil {
+			return fmt.Errorf("failed to write report: %w", err)
+		}
+	default:
+		WriteTextReport(os.Stdout, report)
+	}
+	return report.Err()
+}
+
+// writeReportFile renders the report to path.
+func writeReportFile(path, format string, r *TestReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file: %w", err)
+	}
+	if err := WriteReport(f, format, r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// seconds formats a duration the way JUnit consumers expect.
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// milliseconds converts a duration for the JSON and TAP reports.
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// failureDetails describes a failed case: its failed expectations followed by
+// the raw request and response.
+func failureDetails(c *TestCase) string {
+	var b strings.Builder
+	for _, f := range c.Failures {
+		fmt.Fprintf(&b, "%s\n", f)
+	}
+	if c.Spec != "" {
+		fmt.Fprintf(&b, "spec: %s\n", c.Spec)
+	}
+	if c.Request != nil {
+		fmt.Fprintf(&b, "request: %s\n", marshalCompact(c.Request))
+	}
+	if c.Response != nil {
+		fmt.Fprintf(&b, "response: %s\n", marshalCompact(c.Response))
+	}
+	return b.String()
+}
+
+// failureMessage is the one-line summary of a failed case.
+func failureMessage(c *TestCase) string {
+	if c.Message != "" {
+		return c.Message
+	}
+	if len(c.Failures) > 0 {
+		return c.Failures[0]
+	}
+	return string(c.Status)
+}
+
+type junitTestSuites struct {
+	XMLName   xml.Name         `xml:"testsuites"`
+	Name      string           `xml:"name,attr"`
+	Tests     int              `xml:"tests,attr"`
+	Failures  int              `xml:"failures,attr"`
+	Errors    int              `xml:"errors,attr"`
+	Skipped   int              `xml:"skipped,attr"`
+	Time      string           `xml:"time,attr"`
+	Timestamp string           `xml:"timestamp,attr,omitempty"`
+	Suites    []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+	Skipped   *junitProblem `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr,omitempty"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnitReport renders the report as JUnit XML, with one testsuite per
+// suite. Failed cases carry their request and response in the failure body.
+func WriteJUnitReport(w io.Writer, r *TestReport) error {
+	counts := r.Counts()
+	out := junitTestSuites{
+		Name:     "mcpcli",
+		Tests:    counts.Total(),
+		Failures: counts.Failed,
+		Errors:   counts.Errors,
+		Skipped:  counts.Skipped,
+		Time:     seconds(r.Duration),
+	}
+	if !r.Started.IsZero() {
+		out.Timestamp = r.Started.UTC().Format(time.RFC3339)
+	}
+	for _, s := range r.Suites {
+		sc := s.Counts()
+		suite := junitTestSuite{
+			Name:      s.Name,
+			Tests:     sc.Total(),
+			Failures:  sc.Failed,
+			Errors:    sc.Errors,
+			Skipped:   sc.Skipped,
+			Time:      seconds(s.Duration()),
+			Timestamp: out.Timestamp,
+		}
+		for _, c := range s.Cases {
+			tc := junitTestCase{Name: c.Name, ClassName: "mcpcli." + s.Name, Time: seconds(c.Duration)}
+			problem := &junitProblem{Message: failureMessage(c), Body: failureDetails(c)}
+			switch c.Status {
+			case StatusPassed:
+				tc.SystemOut = c.Message
+			case StatusFailed:
+				problem.Type = "failure"
+				tc.Failure = problem
+			case StatusError:
+				problem.Type = "error"
+				tc.Error = problem
+			case StatusSkipped:
+				tc.Skipped = &junitProblem{Message: c.Message}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+		out.Suites = append(out.Suites, suite)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(out); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// WriteTAPReport renders the report as TAP version 13. Failed cases carry a
+// YAML diagnostic block with their failures, request and response.
+func WriteTAPReport(w io.Writer, r *TestReport) error {
+	counts := r.Counts()
+	var b strings.Builder
+	fmt.Fprintf(&b, "TAP version 13\n1..%d\n", counts.Total())
+	n := 0
+	for _, s := range r.Suites {
+		for _, c := range s.Cases {
+			n++
+			description := tapEscape(s.Name + ": " + c.Name)
+			switch c.Status {
+			case StatusPassed:
+				fmt.Fprintf(&b, "ok %d - %s\n", n, description)
+			case StatusSkipped:
+				fmt.Fprintf(&b, "ok %d - %s # SKIP %s\n", n, description, tapEscape(c.Message))
+			default:
+				fmt.Fprintf(&b, "not ok %d - %s\n", n, description)
+				writeTAPDiagnostics(&b, c)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeTAPDiagnostics writes the YAML block of a failed case. Values are
+// written as JSON, which YAML parsers accept.
+func writeTAPDiagnostics(b *strings.Builder, c *TestCase) {
+	b.WriteString("  ---\n")
+	fmt.Fprintf(b, "  message: %s\n", marshalCompact(failureMessage(c)))
+	severity := "fail"
+	if c.Status == StatusError {
+		severity = "error"
+	}
+	fmt.Fprintf(b, "  severity: %s\n", severity)
+	fmt.Fprintf(b, "  duration_ms: %v\n", milliseconds(c.Duration))
+	if len(c.Failures) > 0 {
+		b.WriteString("  failures:\n")
+		for _, f := range c.Failures {
+			fmt.Fprintf(b, "    - %s\n", marshalCompact(f))
+		}
+	}
+	if c.Spec != "" {
+		fmt.Fprintf(b, "  spec: %s\n", marshalCompact(c.Spec))
+	}
+	if c.Request != nil {
+		fmt.Fprintf(b, "  request: %s\n", marshalCompact(c.Request))
+	}
+	if c.Response != nil {
+		fmt.Fprintf(b, "  response: %s\n", marshalCompact(c.Response))
+	}
+	b.WriteString("  ...\n")
+}
+
+// tapEscape keeps descriptions on one line and escapes the directive marker.
+func tapEscape(s string) string {
+	return strings.NewReplacer("\n", " ", "\r", " ", "#", "\\#").Replace(s)
+}
+
+type jsonReport struct {
+	Started    string      `json:"started,omitempty"`
+	DurationMS float64     `json:"durationMs"`
+	Summary    jsonSummary `json:"summary"`
+	Suites     []jsonSuite `json:"suites"`
+}
+
+type jsonSummary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Errors  int `json:"errors"`
+	Skipped int `json:"skipped"`
+}
+
+type jsonSuite struct {
+	Name       string      `json:"name"`
+	DurationMS float64     `json:"durationMs"`
+	Summary    jsonSummary `json:"summary"`
+	Cases      []jsonCase  `json:"cases"`
+}
+
+type jsonCase struct {
+	Name       string      `json:"name"`
+	Status     TestStatus  `json:"status"`
+	DurationMS float64     `json:"durationMs"`
+	Message    string      `json:"message,omitempty"`
+	Failures   []string    `json:"failures,omitempty"`
+	Spec       string      `json:"spec,omitempty"`
+	Request    interface{} `json:"request,omitempty"`
+	Response   interface{} `json:"response,omitempty"`
+}
+
+func newJSONSummary(c TestCounts) jsonSummary {
+	return jsonSummary{Total: c.Total(), Passed: c.Passed, Failed: c.Failed, Errors: c.Errors, Skipped: c.Skipped}
+}
+
+// WriteJSONReport renders the report as an indented JSON document. The
+// request and response are included for cases that did not pass.
+func WriteJSONReport(w io.Writer, r *TestReport) error {
+	out := jsonReport{
+		DurationMS: milliseconds(r.Duration),
+		Summary:    newJSONSummary(r.Counts()),
+		Suites:     []jsonSuite{},
+	}
+	if !r.Started.IsZero() {
+		out.Started = r.Started.UTC().Format(time.RFC3339Nano)
+	}
+	for _, s := range r.Suites {
+		suite := jsonSuite{
+			Name:       s.Name,
+			DurationMS: milliseconds(s.Duration()),
+			Summary:    newJSONSummary(s.Counts()),
+			Cases:      []jsonCase{},
+		}
+		for _, c := range s.Cases {
+			jc := jsonCase{
+				Name:       c.Name,
+				Status:     c.Status,
+				DurationMS: milliseconds(c.Duration),
+				Message:    c.Message,
+				Failures:   c.Failures,
+				Spec:       c.Spec,
+			}
+			if !c.Passed() {
+				if c.Request != nil {
+					jc.Request = c.Request
+				}
+				if c.Response != nil {
+					jc.Response = c.Response
+				}
+			}
+			suite.Cases = append(suite.Cases, jc)
+		}
+		out.Suites = append(out.Suites, suite)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestValidateReportFormat(t *testing.T) {
+	for _, f := range ReportFormats {
+		if err := ValidateReportFormat(f); err != nil {
+			t.Errorf("%s: unexpected error: %v", f, err)
+		}
+	}
+	if err := ValidateReportFormat("html"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnitReport(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Tests    int `xml:"tests,attr"`
+		Failures int `xml:"failures,attr"`
+		Errors   int `xml:"errors,attr"`
+		Skipped  int `xml:"skipped,attr"`
+		Suites   []struct {
+			Name  string `xml:"name,attr"`
+			Cases []struct {
+				Name    string `xml:"name,attr"`
+				Time    string `xml:"time,attr"`
+				Failure *struct {
+					Message string `xml:"message,attr"`
+					Body    string `xml:",chardata"`
+				} `xml:"failure"`
+				Error   *struct{} `xml:"error"`
+				Skipped *struct{} `xml:"skipped"`
+			} `xml:"testcase"`
+		} `xml:"testsuite"`
+	}
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, buf.String())
+	}
+	if doc.Tests != 4 || doc.Failures != 1 || doc.Errors != 1 || doc.Skipped != 1 || len(doc.Suites) != 3 {
+		t.Fatalf("unexpected totals: %+v", doc)
+	}
+	if c := doc.Suites[0].Cases[0]; c.Time != "1.000" || c.Failure != nil {
+		t.Errorf("unexpected passed case: %+v", c)
+	}
+	failed := doc.Suites[1].Cases[0]
+	if failed.Failure == nil || failed.Failure.Message != "unexpected error" || !strings.Contains(failed.Failure.Body, "spec: https://www.jsonrpc.org/") || !strings.Contains(failed.Failure.Body, `request: {"jsonrpc":"2.0","method":"bogus","id":2}`) || !strings.Contains(failed.Failure.Body, `response: {"jsonrpc":"2.0","id":2,"error":{"code":-32601`) {
+		t.Errorf("unexpected failure: %+v", failed.Failure)
+	}
+	if doc.Suites[1].Cases[1].Error == nil || doc.Suites[2].Cases[0].Skipped == nil {
+		t.Errorf("expected error and skipped elements: %s", buf.String())
+	}
+}
+
+func TestWriteTAPReport(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTAPReport(&buf, sampleReport()); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		"TAP version 13\n1..4\n",
+		"ok 1 - tools: tools\n",
+		"not ok 2 - script: Step 1: bogus\n  ---\n  message: \"unexpected error\"\n  severity: fail\n",
+		"  spec: \"https://www.jsonrpc.org/specification#error_object\"\n",
+		`  request: {"jsonrpc":"2.0","method":"bogus","id":2}`,
+		"not ok 3 - script: Step 2: list\n  ---\n  message: \"timeout\"\n  severity: error\n",
+		"ok 4 - capabilities: capabilities # SKIP",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	report := sampleReport()
+	report.Started = time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	var buf bytes.Buffer
+	if err := WriteJSONReport(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Started    string  `json:"started"`
+		DurationMS float64 `json:"durationMs"`
+		Summary    struct {
+			Total, Passed, Failed, Errors, Skipped int
+		} `json:"summary"`
+		Suites []struct {
+			Name  string `json:"name"`
+			Cases []struct {
+				Status     string          `json:"status"`
+				DurationMS float64         `json:"durationMs"`
+				Spec       string          `json:"spec"`
+				Request    json.RawMessage `json:"request"`
+				Response   json.RawMessage `json:"response"`
+			} `json:"cases"`
+		} `json:"suites"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if doc.Started != "2025-01-02T03:04:05Z" || doc.DurationMS != 1500 || doc.Summary.Total != 4 || doc.Summary.Errors != 1 {
+		t.Fatalf("unexpected report: %s", buf.String())
+	}
+	if c := doc.Suites[0].Cases[0]; c.Status != "passed" || c.DurationMS != 1000 {
+		t.Errorf("unexpected case: %+v", c)
+	}
+	if c := doc.Suites[1].Cases[0]; c.Status != "failed" || c.Spec == "" || c.Request == nil || c.Response == nil {
+		t.Errorf("failed case should carry request and response: %s", buf.String())
+	}
+}
+
+func TestRunTests_ReportFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.xml")
+	opts := &TestOptions{TestAll: true, ReportFile: path, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Tests: 10 passed, 0 failed") {
+		t.Errorf("expected text output on stdout, got: %s", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `<testsuites name="mcpcli" tests="11" failures="0" errors="0" skipped="1"`) {
+		t.Errorf("unexpected report: %s", data)
+	}
+}
+
+func TestRunTests_ReportStdout(t *testing.T) {
+	opts := &TestOptions{TestTools: true, Report: "json", Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "none", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "true"}}}
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err == nil {
+		t.Fatal("expected failing tests to return an error")
+	}
+	var doc map[string]interface{}
+	if jsonErr := json.Unmarshal([]byte(out), &doc); jsonErr != nil {
+		t.Fatalf("stdout is not a JSON report: %v\n%s", jsonErr, out)
+	}
+}
+
+func TestRunTests_InvalidReportFormat(t *testing.T) {
+	opts := &TestOptions{TestAll: true, Report: "html"}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "nonexistent-cmd"}}}
+	if err := RunTests(opts, cfg); err == nil || !strings.Contains(err.Error(), "invalid report format") {
+		t.Fatalf("expected report format error, got %v", err)
+	}
+}
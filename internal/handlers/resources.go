@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// sampleTemplateValue is the value given to every variable of a resource
+// template expanded by the resources suite.
+const sampleTemplateValue = "example"
+
+// runResourceTemplateCases checks the resource templates of the server: each
+// template listed by resources/templates/list must be a valid RFC 6570 URI
+// template, and the URI it expands to is read. Servers without templates
+// answer the list with "method not found", which skips the check.
+func runResourceTemplateCases(client *core.MCPClient, opts *TestOptions, id *int, suite *TestSuite) {
+	tc, listed := runListCase(client, opts, "Resource templates", "resources/templates/list", id)
+	if listed != nil && listed.Error != nil && listed.Error.Code == core.MethodNotFound {
+		tc.Status = StatusSkipped
+		tc.Message = "Resource templates: not supported by the server"
+		tc.Request, tc.Response = nil, nil
+	}
+	if !suite.Add(tc).Passed() {
+		return
+	}
+	result, err := core.DecodeListResourceTemplatesResult(listed)
+	if err != nil {
+		suite.Add(&TestCase{Name: "resources/templates/list result", Status: StatusFailed, Message: err.Error(), Response: listed})
+		return
+	}
+	for _, template := range result.ResourceTemplates {
+		suite.Add(runResourceTemplateCase(client, opts, template, id))
+	}
+}
+
+func runResourceTemplateCase(client *core.MCPClient, opts *TestOptions, template core.ResourceTemplateDefinition, id *int) *TestCase {
+	tc := &TestCase{Name: "resources/read " + template.Name, Status: StatusFailed}
+	parsed, err := core.ParseURITemplate(template.URITemplate)
+	if err != nil {
+		tc.Message = fmt.Sprintf("Resource template %s: %v", template.Name, err)
+		return tc
+	}
+	values := map[string]interface{}{}
+	for _, name := range parsed.Variables() {
+		values[name] = sampleTemplateValue
+	}
+	uri := parsed.Expand(values)
+
+	tc.Request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: "resources/read", Params: map[string]interface{}{"uri": uri}, ID: *id}
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	resp, err := client.ReadResourceContext(ctx, uri, *id)
+	tc.Duration = time.Since(start)
+	cancel()
+	*id++
+
+	switch {
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Resource template %s: %s", template.Name, describeCallError(client, err))
+		return tc
+	case resp.Error != nil && resp.Error.Code == core.ResourceNotFound:
+		tc.Status = StatusSkipped
+		tc.Message = fmt.Sprintf("Resource template %s: no resource at %s", template.Name, uri)
+		return tc
+	case resp.Error != nil:
+		tc.Response = resp
+		tc.Message = fmt.Sprintf("Resource template %s: resources/read %s failed: %s (code %d)", template.Name, uri, resp.Error.Message, resp.Error.Code)
+		return tc
+	}
+	tc.Response = resp
+	if problems := core.CheckReadResourceResult(resp.Result); len(problems) > 0 {
+		tc.Message = fmt.Sprintf("Resource template %s: malformed result", template.Name)
+		for _, p := range problems {
+			tc.Failures = append(tc.Failures, p.Error())
+		}
+		return tc
+	}
+	contents, _ := resp.Result.(map[string]interface{})["contents"].([]interface{})
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("Resource template %s: read %s (%d item(s))", template.Name, uri, len(contents))
+	return tc
+}
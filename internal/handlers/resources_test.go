@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// newResourceTemplateServer serves resources and resource templates over
+// HTTP, with templates exercising each outcome the resources suite detects.
+// Without templates it answers resources/templates/list with "method not
+// found".
+func newResourceTemplateServer(t *testing.T, templates bool) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		switch uri, _ := req.Params["uri"].(string); {
+		case req.Method == "initialize":
+			resp.Result = core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "templates", Version: "1.0.0"}}
+		case req.Method == "resources/list":
+			resp.Result = map[string]interface{}{"resources": []interface{}{}}
+		case req.Method == "resources/templates/list" && templates:
+			resp.Result = map[string]interface{}{"resourceTemplates": []interface{}{
+				map[string]interface{}{"name": "notes", "uriTemplate": "notes:///{folder}/{name}{?format}"},
+				map[string]interface{}{"name": "missing", "uriTemplate": "missing:///{id}"},
+				map[string]interface{}{"name": "invalid", "uriTemplate": "bad:///{id"},
+				map[string]interface{}{"name": "malformed", "uriTemplate": "malformed:///{id}"},
+				map[string]interface{}{"name": "failing", "uriTemplate": "failing:///{id}"},
+			}}
+		case req.Method == "resources/read" && strings.HasPrefix(uri, "notes:"):
+			resp.Result = map[string]interface{}{"contents": []interface{}{map[string]interface{}{"uri": uri, "text": "note"}}}
+		case req.Method == "resources/read" && strings.HasPrefix(uri, "missing:"):
+			resp = core.NewErrorResponse(req.ID, core.ResourceNotFound, "Resource not found", nil)
+		case req.Method == "resources/read" && strings.HasPrefix(uri, "malformed:"):
+			resp.Result = map[string]interface{}{"contents": []interface{}{map[string]interface{}{"uri": uri}}}
+		case req.Method == "resources/read":
+			resp = core.NewErrorResponse(req.ID, core.InternalError, "boom", nil)
+		default:
+			resp = core.NewErrorResponse(req.ID, core.MethodNotFound, "Method not found", nil)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectTests_ResourceTemplates(t *testing.T) {
+	srv := newResourceTemplateServer(t, true)
+	opts := &TestOptions{TestResources: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "templates", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("resources").Cases
+	want := []struct {
+		name    string
+		status  TestStatus
+		message string
+	}{
+		{"resources", StatusPassed, "Resources:"},
+		{"resource templates", StatusPassed, "Resource templates:"},
+		{"resources/read notes", StatusPassed, "Resource template notes: read notes:///example/example?format=example (1 item(s))"},
+		{"resources/read missing", StatusSkipped, "no resource at missing:///example"},
+		{"resources/read invalid", StatusFailed, "unclosed expression"},
+		{"resources/read malformed", StatusFailed, "Resource template malformed: malformed result"},
+		{"resources/read failing", StatusFailed, "resources/read failing:///example failed: boom (code -32603)"},
+	}
+	if len(cases) != len(want) {
+		t.Fatalf("expected %d cases, got %d", len(want), len(cases))
+	}
+	for i, w := range want {
+		c := cases[i]
+		if c.Name != w.name || c.Status != w.status || !strings.Contains(c.Message, w.message) {
+			t.Errorf("case %d: got %s %s %q, want %s %s %q", i, c.Name, c.Status, c.Message, w.name, w.status, w.message)
+		}
+	}
+	if f := cases[5].Failures; len(f) != 1 || !strings.Contains(f[0], "contents[0]: resource must have text or blob") {
+		t.Errorf("unexpected failures: %v", f)
+	}
+}
+
+func TestCollectTests_ResourceTemplatesUnsupported(t *testing.T) {
+	srv := newResourceTemplateServer(t, false)
+	opts := &TestOptions{TestResources: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "templates", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("resources").Cases
+	if len(cases) != 2 || cases[1].Status != StatusSkipped || cases[1].Request != nil {
+		t.Fatalf("expected the templates case to be skipped, got %+v", cases)
+	}
+	if report.Err() != nil {
+		t.Errorf("skipped templates should not fail the run: %v", report.Err())
+	}
+}
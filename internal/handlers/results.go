@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// TestStatus is the outcome of a single test case.
+type TestStatus string
+
+const (
+	// StatusPassed means every expectation of the case held.
+	StatusPassed TestStatus = "passed"
+	// StatusFailed means the server answered, but not as expected.
+	StatusFailed TestStatus = "failed"
+	// StatusError means no usable answer was received, for instance because
+	// the transport failed or the request timed out.
+	StatusError TestStatus = "error"
+	// StatusSkipped means the case could not run.
+	StatusSkipped TestStatus = "skipped"
+)
+
+// TestCase is the result of one check performed by `mcpcli test`.
+type TestCase struct {
+	Name     string
+	Status   TestStatus
+	Duration time.Duration
+	// Message summarizes the outcome in one line.
+	Message string
+	// Failures lists the individual expectations that did not hold.
+	Failures []string
+	// Spec links to the specification section the case verifies, if any.
+	Spec string
+	// Request and Response are the messages exchanged by the case, when it
+	// sent one.
+	Request  *core.Request
+	Response *core.Response
+}
+
+// Passed reports whether the case passed.
+func (c *TestCase) Passed() bool {
+	return c.Status == StatusPassed
+}
+
+// TestSuite groups the cases of one test category, such as tools or the
+// steps of a script.
+type TestSuite struct {
+	Name  string
+	Cases []*TestCase
+}
+
+// Add appends a case to the suite and returns it.
+func (s *TestSuite) Add(c *TestCase) *TestCase {
+	s.Cases = append(s.Cases, c)
+	return c
+}
+
+// Duration is the total time spent in the suite's cases.
+func (s *TestSuite) Duration() time.Duration {
+	var d time.Duration
+	for _, c := range s.Cases {
+		d += c.Duration
+	}
+	return d
+}
+
+// Counts tallies the suite's cases by status.
+func (s *TestSuite) Counts() TestCounts {
+	var counts TestCounts
+	for _, c := range s.Cases {
+		counts.add(c.Status)
+	}
+	return counts
+}
+
+// TestCounts holds the number of cases per status.
+type TestCounts struct {
+	Passed  int
+	Failed  int
+	Errors  int
+	Skipped int
+}
+
+func (c *TestCounts) add(status TestStatus) {
+	switch status {
+	case StatusPassed:
+		c.Passed++
+	case StatusFailed:
+		c.Failed++
+	case StatusError:
+		c.Errors++
+	case StatusSkipped:
+		c.Skipped++
+	}
+}
+
+// Total is the number of cases counted.
+func (c TestCounts) Total() int {
+	return c.Passed + c.Failed + c.Errors + c.Skipped
+}
+
+// TestReport is the outcome of a `mcpcli test` run.
+type TestReport struct {
+	Started  time.Time
+	Duration time.Duration
+	Suites   []*TestSuite
+}
+
+// Suite returns the suite with the given name, adding it when missing.
+func (r *TestReport) Suite(name string) *TestSuite {
+	for _, s := range r.Suites {
+		if s.Name == name {
+			return s
+		}
+	}
+	s := &TestSuite{Name: name}
+	r.Suites = append(r.Suites, s)
+	return s
+}
+
+// Counts tallies the cases of all suites by status.
+func (r *TestReport) Counts() TestCounts {
+	var counts TestCounts
+	for _, s := range r.Suites {
+		for _, c := range s.Cases {
+			counts.add(c.Status)
+		}
+	}
+	return counts
+}
+
+// Err returns an error when any case failed or errored, so that the command
+// exits with a non-zero status.
+func (r *TestReport) Err() error {
+	counts := r.Counts()
+	if bad := counts.Failed + counts.Errors; bad > 0 {
+		return fmt.Errorf("%d of %d tests failed", bad, counts.Total())
+	}
+	return nil
+}
+
+// WriteTextReport renders the report for humans.
+func WriteTextReport(w io.Writer, r *TestReport) {
+	for _, s := range r.Suites {
+		fmt.Fprintf(w, "⚠️ Testing %s...\n", s.Name)
+		for _, c := range s.Cases {
+			writeTextCase(w, c)
+		}
+	}
+	counts := r.Counts()
+	fmt.Fprintf(w, "Tests: %d passed, %d failed", counts.Passed, counts.Failed+counts.Errors)
+	if counts.Skipped > 0 {
+		fmt.Fprintf(w, ", %d skipped", counts.Skipped)
+	}
+	fmt.Fprintf(w, " (%s)\n", r.Duration.Round(time.Millisecond))
+}
+
+func writeTextCase(w io.Writer, c *TestCase) {
+	title := c.Message
+	if title == "" {
+		title = c.Name
+	}
+	switch c.Status {
+	case StatusPassed:
+		fmt.Fprintf(w, "✅ %s\n", title)
+		return
+	case StatusSkipped:
+		fmt.Fprintf(w, "⏭️ %s\n", title)
+		return
+	}
+	fmt.Fprintf(w, "❌ %s\n", title)
+	for _, f := range c.Failures {
+		fmt.Fprintf(w, "   - %s\n", f)
+	}
+	if c.Spec != "" {
+		fmt.Fprintf(w, "   spec:     %s\n", c.Spec)
+	}
+	if c.Request != nil {
+		fmt.Fprintf(w, "   request:  %s\n", marshalCompact(c.Request))
+	}
+	if c.Response != nil {
+		fmt.Fprintf(w, "   response: %s\n", marshalCompact(c.Response))
+	}
+	if c.Status == StatusError {
+		fmt.Fprintf(w, "⚠️ Make sure an MCP server is running and reachable through the configured transport\n")
+	}
+}
+
+func marshalCompact(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+func sampleReport() *TestReport {
+	report := &TestReport{Duration: 1500 * time.Millisecond}
+	report.Suite("tools").Add(&TestCase{Name: "tools", Status: StatusPassed, Message: "Tools: ok", Duration: time.Second})
+	report.Suite("script").Add(&TestCase{
+		Name:     "Step 1: bogus",
+		Status:   StatusFailed,
+		Failures: []string{"unexpected error"},
+		Spec:     "https://www.jsonrpc.org/specification#error_object",
+		Request:  &core.Request{JSONRPC: core.JSONRPCVersion, Method: "bogus", ID: 2},
+		Response: core.NewErrorResponse(2, core.MethodNotFound, "Method not found", nil),
+	})
+	report.Suite("script").Add(&TestCase{Name: "Step 2: list", Status: StatusError, Failures: []string{"timeout"}})
+	report.Suite("capabilities").Add(&TestCase{Name: "capabilities", Status: StatusSkipped})
+	return report
+}
+
+func TestTestReport_Counts(t *testing.T) {
+	report := sampleReport()
+	if len(report.Suites) != 3 {
+		t.Fatalf("expected 3 suites, got %d", len(report.Suites))
+	}
+	counts := report.Counts()
+	if counts != (TestCounts{Passed: 1, Failed: 1, Errors: 1, Skipped: 1}) || counts.Total() != 4 {
+		t.Fatalf("unexpected counts: %+v", counts)
+	}
+	if c := report.Suite("script").Counts(); c.Failed != 1 || c.Errors != 1 {
+		t.Fatalf("unexpected suite counts: %+v", c)
+	}
+	if d := report.Suite("tools").Duration(); d != time.Second {
+		t.Fatalf("unexpected suite duration: %v", d)
+	}
+	if err := report.Err(); err == nil || err.Error() != "2 of 4 tests failed" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := (&TestReport{}).Err(); err != nil {
+		t.Fatalf("empty report should pass, got %v", err)
+	}
+}
+
+func TestWriteTextReport(t *testing.T) {
+	var buf bytes.Buffer
+	WriteTextReport(&buf, sampleReport())
+	out := buf.String()
+	for _, want := range []string{
+		"⚠️ Testing tools...\n✅ Tools: ok\n",
+		"❌ Step 1: bogus\n   - unexpected error\n   spec:     https://www.jsonrpc.org/specification#error_object\n   request:  {\"jsonrpc\":\"2.0\",\"method\":\"bogus\",\"id\":2}\n   response: ",
+		"❌ Step 2: list\n   - timeout\n⚠️ Make sure an MCP server is running",
+		"⏭️ capabilities",
+		"Tests: 1 passed, 2 failed, 1 skipped (1.5s)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
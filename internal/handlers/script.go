@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// runScript executes the scenario steps in order and records one case per
+// step in suite. Values captured by a step are available to all following
+// steps.
+func runScript(client *core.MCPClient, scenario *core.Scenario, opts *TestOptions, id *int, suite *TestSuite) {
+	vars := core.ScenarioVars{}
+	for i := range scenario.Steps {
+		step := &scenario.Steps[i]
+		name := step.Name
+		if name == "" {
+			name = step.Method
+		}
+		suite.Add(runStep(client, step, opts, id, vars, fmt.Sprintf("Step %d: %s", i+1, name)))
+	}
+}
+
+// runStep sends a single step and checks the response against JSON-RPC and
+// the step expectations.
+func runStep(client *core.MCPClient, step *core.ScenarioStep, opts *TestOptions, id *int, vars core.ScenarioVars, name string) *TestCase {
+	tc := &TestCase{Name: name, Status: StatusPassed}
+	params, err := step.ExpandParams(vars)
+	if err != nil {
+		tc.Status = StatusFailed
+		tc.Failures = []string{err.Error()}
+		return tc
+	}
+	if step.Notification {
+		tc.Request = core.NewNotification(step.Method, params)
+		if err := client.SendNotification(step.Method, params); err != nil {
+			tc.Status = StatusError
+			tc.Failures = []string{err.Error()}
+		}
+		return tc
+	}
+
+	tc.Request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: step.Method, Params: params, ID: *id}
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	resp, err := client.CallContext(ctx, step.Method, params, *id)
+	tc.Duration = time.Since(start)
+	cancel()
+	*id++
+	if err != nil {
+		tc.Status = StatusError
+		tc.Failures = []string{err.Error()}
+		return tc
+	}
+	tc.Response = resp
+	var failures []error
+	if err := resp.CheckProtocol(); err != nil {
+		failures = append(failures, err)
+	}
+	failures = append(failures, step.Check(resp, vars)...)
+	if err := step.CaptureInto(resp, vars); err != nil {
+		failures = append(failures, err)
+	}
+	for _, f := range failures {
+		tc.Failures = append(tc.Failures, f.Error())
+	}
+	if len(failures) > 0 {
+		tc.Status = StatusFailed
+	}
+	return tc
+}
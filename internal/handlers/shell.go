@@ -0,0 +1,479 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/lineedit"
+)
+
+// ShellOptions contains flags for `mcpcli shell`.
+type ShellOptions struct {
+	Config string
+	// ProtocolVersion is the MCP revision requested during initialization.
+	ProtocolVersion string
+	// Timeout bounds each request sent to the server. Zero disables it.
+	Timeout time.Duration
+	// HistoryFile keeps the commands entered across sessions. Empty
+	// disables it.
+	HistoryFile string
+}
+
+// DefaultHistoryFile returns the default shell history file, in the user's
+// home directory.
+func DefaultHistoryFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".mcpcli_history")
+}
+
+// shellCommand is a command of the shell.
+type shellCommand struct {
+	name  string
+	usage string
+	help  string
+	run   func(s *Shell, ctx context.Context, args string) error
+}
+
+// shellCommands are the commands of the shell, in the order listed by help.
+var shellCommands []shellCommand
+
+func init() {
+	shellCommands = []shellCommand{
+		{"tools", "tools", "List the tools of the server", (*Shell).listTools},
+		{"call", "call <tool> [{json arguments}]", "Call a tool", (*Shell).callTool},
+		{"resources", "resources", "List the resources of the server", (*Shell).listResources},
+		{"read", "read <uri>", "Read a resource", (*Shell).readResource},
+		{"prompts", "prompts", "List the prompts of the server", (*Shell).listPrompts},
+		{"raw", "raw <method> [{json params}]", "Send any request and print the response", (*Shell).raw},
+		{"ping", "ping", "Check that the server answers", (*Shell).ping},
+		{"history", "history", "List the commands entered", (*Shell).showHistory},
+		{"help", "help", "List the commands", (*Shell).help},
+		{"exit", "exit", "Leave the shell (also quit or Ctrl-D)", nil},
+	}
+}
+
+// shellMethods are completed after raw.
+var shellMethods = []string{
+	"completion/complete",
+	"initialize",
+	"logging/setLevel",
+	"ping",
+	"prompts/get",
+	"prompts/list",
+	"resources/list",
+	"resources/read",
+	"resources/subscribe",
+	"resources/templates/list",
+	"resources/unsubscribe",
+	"tools/call",
+	"tools/list",
+}
+
+// errExit is returned by Execute for the exit command.
+var errExit = errors.New("exit")
+
+// Shell runs interactive commands against an initialized MCP client. The
+// names of the tools, resources and prompts listed by the server are kept for
+// completion.
+type Shell struct {
+	client  *core.MCPClient
+	out     io.Writer
+	timeout time.Duration
+	editor  *lineedit.Editor
+
+	tools     []string
+	resources []string
+	prompts   []string
+}
+
+// NewShell creates a shell printing to out. The editor provides the history
+// and may be nil.
+func NewShell(client *core.MCPClient, out io.Writer, timeout time.Duration, editor *lineedit.Editor) *Shell {
+	return &Shell{client: client, out: out, timeout: timeout, editor: editor}
+}
+
+// RunShell connects to the server described by config and reads commands
+// until the input ends or the user exits.
+func RunShell(opts *ShellOptions, config *core.MCPConfig) error {
+	transport, err := core.NewClientTransport(config.Transport)
+	if err != nil {
+		return err
+	}
+	client := core.NewMCPClientWithTransport(transport, os.Stderr)
+	defer client.Close()
+
+	version := opts.ProtocolVersion
+	if version == "" {
+		version = core.LatestProtocolVersion
+	}
+	ctx, cancel := requestContext(&TestOptions{Timeout: opts.Timeout})
+	initResult, err := client.InitializeContext(ctx, version, core.Implementation{Name: "mcpcli", Version: core.CLIVersion}, core.ClientCapabilities{}, nil)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("failed to initialize: %w", err)
+	}
+
+	editor := lineedit.New(os.Stdin, os.Stdout)
+	editor.Prompt = "mcp> "
+	history := loadHistory(editor, opts.HistoryFile)
+	if history != nil {
+		defer history.Close()
+	}
+	shell := NewShell(client, os.Stdout, opts.Timeout, editor)
+	editor.Complete = shell.Complete
+	if editor.Interactive() {
+		fmt.Printf("Connected to %s %s (protocol %s). Type help for the commands.\n", initResult.ServerInfo.Name, initResult.ServerInfo.Version, initResult.ProtocolVersion)
+	}
+	shell.Prefetch(initResult)
+
+	for {
+		line, err := editor.ReadLine()
+		if errors.Is(err, lineedit.ErrInterrupted) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		editor.AddHistory(line)
+		if history != nil && strings.TrimSpace(line) != "" {
+			fmt.Fprintln(history, line)
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		err = shell.Execute(ctx, line)
+		stop()
+		if errors.Is(err, errExit) {
+			return nil
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "error: %v\n", err)
+		}
+	}
+}
+
+// loadHistory adds the lines of the history file to the editor and opens it
+// for appending. It returns nil when there is no usable history file.
+func loadHistory(editor *lineedit.Editor, path string) *os.File {
+	if path == "" {
+		return nil
+	}
+	if f, err := os.Open(path); err == nil {
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			editor.AddHistory(scanner.Text())
+		}
+		f.Close()
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil
+	}
+	return f
+}
+
+// Prefetch fills the completion lists with the tools, resources and prompts
+// of the server, for the capabilities it advertised.
+func (s *Shell) Prefetch(init *core.InitializeResult) {
+	if advertises(init, "tools") {
+		if result, err := s.list(context.Background(), "tools/list"); err == nil {
+			s.tools = listedNames(result, "tools", "name")
+		}
+	}
+	if advertises(init, "resources") {
+		if result, err := s.list(context.Background(), "resources/list"); err == nil {
+			s.resources = listedNames(result, "resources", "uri")
+		}
+	}
+	if advertises(init, "prompts") {
+		if result, err := s.list(context.Background(), "prompts/list"); err == nil {
+			s.prompts = listedNames(result, "prompts", "name")
+		}
+	}
+}
+
+// Execute runs one command line. Failed requests are printed; the error
+// reports invalid commands and transport failures.
+func (s *Shell) Execute(ctx context.Context, line string) error {
+	name, args := splitWord(strings.TrimSpace(line))
+	switch name {
+	case "":
+		return nil
+	case "exit", "quit":
+		return errExit
+	}
+	for _, c := range shellCommands {
+		if c.name == name && c.run != nil {
+			return c.run(s, ctx, args)
+		}
+	}
+	return fmt.Errorf("unknown command %q, type help for the commands", name)
+}
+
+// Complete returns the command names, tool names, resource URIs or methods
+// that may end line.
+func (s *Shell) Complete(line string) []string {
+	line = strings.TrimLeft(line, " ")
+	if !strings.Contains(line, " ") {
+		var names []string
+		for _, c := range shellCommands {
+			names = append(names, c.name)
+		}
+		return names
+	}
+	name, rest, _ := strings.Cut(line, " ")
+	if strings.Contains(strings.TrimLeft(rest, " "), " ") {
+		// Only the first argument is completed.
+		return nil
+	}
+	switch name {
+	case "call":
+		return s.tools
+	case "read":
+		return s.resources
+	case "raw":
+		return shellMethods
+	}
+	return nil
+}
+
+// splitWord splits the first word of s from the rest.
+func splitWord(s string) (string, string) {
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i+1:])
+	}
+	return s, ""
+}
+
+// parseObject decodes an optional JSON object argument.
+func parseObject(s, what string) (map[string]interface{}, error) {
+	if s == "" {
+		return map[string]interface{}{}, nil
+	}
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil || obj == nil {
+		return nil, fmt.Errorf("%s must be a JSON object: %s", what, s)
+	}
+	return obj, nil
+}
+
+// request sends a request and returns the result, printing the error when the
+// server answers with one.
+func (s *Shell) request(ctx context.Context, method string, params map[string]interface{}) (interface{}, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	resp, err := s.client.CallContext(ctx, method, params, nil)
+	if err != nil {
+		return nil, errors.New(describeCallError(s.client, err))
+	}
+	if resp.Error != nil {
+		msg := fmt.Sprintf("error %d: %s", resp.Error.Code, resp.Error.Message)
+		if resp.Error.Data != nil {
+			msg += "\n" + prettyJSON(resp.Error.Data)
+		}
+		return nil, &shellError{msg}
+	}
+	return resp.Result, nil
+}
+
+// shellError is an error answered by the server, printed as is.
+type shellError struct{ msg string }
+
+func (e *shellError) Error() string { return e.msg }
+
+// show prints the result of a request, or the error answered by the server.
+func (s *Shell) show(result interface{}, err error) error {
+	var serverErr *shellError
+	if errors.As(err, &serverErr) {
+		fmt.Fprintln(s.out, serverErr.msg)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(s.out, prettyJSON(result))
+	return nil
+}
+
+// list sends a list request and returns its result.
+func (s *Shell) list(ctx context.Context, method string) (map[string]interface{}, error) {
+	result, err := s.request(ctx, method, nil)
+	if err != nil {
+		return nil, err
+	}
+	obj, ok := result.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s returned %s, expected an object", method, marshalCompact(result))
+	}
+	return obj, nil
+}
+
+// printList prints one line per listed item: its key and description.
+func (s *Shell) printList(ctx context.Context, method, field, key string) ([]string, error) {
+	result, err := s.list(ctx, method)
+	var serverErr *shellError
+	if errors.As(err, &serverErr) {
+		fmt.Fprintln(s.out, serverErr.msg)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	items, _ := result[field].([]interface{})
+	if len(items) == 0 {
+		fmt.Fprintf(s.out, "No %s.\n", field)
+	}
+	width := 0
+	for _, name := range listedNames(result, field, key) {
+		width = max(width, len(name))
+	}
+	for _, item := range items {
+		obj, _ := item.(map[string]interface{})
+		name, _ := obj[key].(string)
+		description, _ := obj["description"].(string)
+		if key != "name" {
+			if n, _ := obj["name"].(string); n != "" && n != name {
+				description = strings.TrimSpace(n + "  " + description)
+			}
+		}
+		fmt.Fprintf(s.out, "%-*s  %s\n", width, name, firstLine(description))
+	}
+	return listedNames(result, field, key), nil
+}
+
+// listedNames returns the sorted key values of the listed items.
+func listedNames(result map[string]interface{}, field, key string) []string {
+	items, _ := result[field].([]interface{})
+	var names []string
+	for _, item := range items {
+		if obj, ok := item.(map[string]interface{}); ok {
+			if name, ok := obj[key].(string); ok && name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i]
+	}
+	return s
+}
+
+// prettyJSON indents a value for display.
+func prettyJSON(v interface{}) string {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(data)
+}
+
+func (s *Shell) listTools(ctx context.Context, args string) error {
+	names, err := s.printList(ctx, "tools/list", "tools", "name")
+	if names != nil {
+		s.tools = names
+	}
+	return err
+}
+
+func (s *Shell) listResources(ctx context.Context, args string) error {
+	names, err := s.printList(ctx, "resources/list", "resources", "uri")
+	if names != nil {
+		s.resources = names
+	}
+	return err
+}
+
+func (s *Shell) listPrompts(ctx context.Context, args string) error {
+	names, err := s.printList(ctx, "prompts/list", "prompts", "name")
+	if names != nil {
+		s.prompts = names
+	}
+	return err
+}
+
+func (s *Shell) callTool(ctx context.Context, args string) error {
+	name, rest := splitWord(args)
+	if name == "" {
+		return fmt.Errorf("usage: call <tool> [{json arguments}]")
+	}
+	arguments, err := parseObject(rest, "arguments")
+	if err != nil {
+		return err
+	}
+	return s.show(s.request(ctx, "tools/call", map[string]interface{}{"name": name, "arguments": arguments}))
+}
+
+func (s *Shell) readResource(ctx context.Context, args string) error {
+	if args == "" {
+		return fmt.Errorf("usage: read <uri>")
+	}
+	return s.show(s.request(ctx, "resources/read", map[string]interface{}{"uri": args}))
+}
+
+func (s *Shell) raw(ctx context.Context, args string) error {
+	method, rest := splitWord(args)
+	if method == "" {
+		return fmt.Errorf("usage: raw <method> [{json params}]")
+	}
+	var params map[string]interface{}
+	if rest != "" {
+		var err error
+		if params, err = parseObject(rest, "params"); err != nil {
+			return err
+		}
+	}
+	return s.show(s.request(ctx, method, params))
+}
+
+func (s *Shell) ping(ctx context.Context, args string) error {
+	start := time.Now()
+	if _, err := s.request(ctx, "ping", nil); err != nil {
+		return s.show(nil, err)
+	}
+	fmt.Fprintf(s.out, "pong (%s)\n", time.Since(start).Round(time.Microsecond))
+	return nil
+}
+
+func (s *Shell) showHistory(ctx context.Context, args string) error {
+	if s.editor == nil {
+		return nil
+	}
+	for i, line := range s.editor.History() {
+		fmt.Fprintf(s.out, "%4d  %s\n", i+1, line)
+	}
+	return nil
+}
+
+func (s *Shell) help(ctx context.Context, args string) error {
+	width := 0
+	for _, c := range shellCommands {
+		width = max(width, len(c.usage))
+	}
+	for _, c := range shellCommands {
+		fmt.Fprintf(s.out, "  %-*s  %s\n", width, c.usage, c.help)
+	}
+	return nil
+}
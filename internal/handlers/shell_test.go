@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/lineedit"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+// newTestShell connects a shell to the mcptest server.
+func newTestShell(t *testing.T) (*Shell, *bytes.Buffer) {
+	t.Helper()
+	transport, err := core.NewClientTransport(core.Transport{Type: "stdio", Options: map[string]interface{}{"command": mcptest.Command(t)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := core.NewMCPClientWithTransport(transport, os.Stderr)
+	t.Cleanup(func() { client.Close() })
+	init, err := client.Initialize(core.Implementation{Name: "test", Version: "1"}, core.ClientCapabilities{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	editor := lineedit.New(strings.NewReader(""), &out)
+	shell := NewShell(client, &out, 5*time.Second, editor)
+	shell.Prefetch(init)
+	return shell, &out
+}
+
+func TestShell_Commands(t *testing.T) {
+	shell, out := newTestShell(t)
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{"tools", []string{"echo  Echoes the message back"}},
+		{`call echo {"message": "hi there"}`, []string{`"text": "hi there"`, `"type": "text"`}},
+		{"call echo", []string{"error -32602: message must be a string"}},
+		{"resources", []string{mcptest.GreetingURI + "  greeting"}},
+		{"read " + mcptest.GreetingURI, []string{`"text": "` + mcptest.GreetingText + `"`}},
+		{"read file:///missing", []string{"error -32002: Resource not found", `"uri": "file:///missing"`}},
+		{"prompts", []string{"error -32601"}},
+		{`raw tools/call {"name":"echo","arguments":{"message":"raw"}}`, []string{`"text": "raw"`}},
+		{"raw ping", []string{"{}"}},
+		{"ping", []string{"pong ("}},
+		{"help", []string{"call <tool> [{json arguments}]", "raw <method> [{json params}]"}},
+	} {
+		out.Reset()
+		if err := shell.Execute(context.Background(), tc.line); err != nil {
+			t.Errorf("%s: %v", tc.line, err)
+			continue
+		}
+		for _, want := range tc.want {
+			if !strings.Contains(out.String(), want) {
+				t.Errorf("%s: output missing %q:\n%s", tc.line, want, out)
+			}
+		}
+	}
+}
+
+func TestShell_InvalidCommands(t *testing.T) {
+	shell, _ := newTestShell(t)
+	for _, tc := range []struct {
+		line, want string
+	}{
+		{"frobnicate", `unknown command "frobnicate"`},
+		{"call", "usage: call"},
+		{"call echo [1]", "arguments must be a JSON object"},
+		{"read", "usage: read"},
+		{"raw ping {", "params must be a JSON object"},
+	} {
+		if err := shell.Execute(context.Background(), tc.line); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tc.line, tc.want, err)
+		}
+	}
+	for _, line := range []string{"exit", "  quit "} {
+		if err := shell.Execute(context.Background(), line); err != errExit {
+			t.Errorf("%s: expected errExit, got %v", line, err)
+		}
+	}
+	if err := shell.Execute(context.Background(), "   "); err != nil {
+		t.Errorf("blank line: %v", err)
+	}
+}
+
+func TestShell_Complete(t *testing.T) {
+	shell, _ := newTestShell(t)
+	for _, tc := range []struct {
+		line string
+		want []string
+	}{
+		{"call ", []string{mcptest.EchoTool}},
+		{"call ec", []string{mcptest.EchoTool}},
+		{"read fi", []string{mcptest.GreetingURI}},
+		{"call echo {", nil},
+		{"tools ", nil},
+	} {
+		if got := shell.Complete(tc.line); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("%q: got %v, want %v", tc.line, got, tc.want)
+		}
+	}
+	if got := shell.Complete("re"); !containsString(got, "resources") || !containsString(got, "read") {
+		t.Errorf("expected command names, got %v", got)
+	}
+	if got := shell.Complete("raw tools/"); !containsString(got, "tools/call") {
+		t.Errorf("expected methods, got %v", got)
+	}
+}
+
+func TestShell_History(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	os.WriteFile(path, []byte("tools\nping\n"), 0600)
+	editor := lineedit.New(strings.NewReader(""), &bytes.Buffer{})
+	f := loadHistory(editor, path)
+	if f == nil {
+		t.Fatal("history file not opened")
+	}
+	f.WriteString("resources\n")
+	f.Close()
+	if got := strings.Join(editor.History(), ","); got != "tools,ping" {
+		t.Errorf("unexpected history %q", got)
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "tools\nping\nresources\n" {
+		t.Errorf("history not appended: %q", data)
+	}
+
+	var out bytes.Buffer
+	shell := NewShell(nil, &out, 0, editor)
+	shell.Execute(context.Background(), "history")
+	if !strings.Contains(out.String(), "   2  ping") {
+		t.Errorf("unexpected history output %q", out.String())
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
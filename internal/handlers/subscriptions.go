@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// updateWait is how long the subscriptions suite waits for an update
+// notification after calling a tool.
+const updateWait = time.Second
+
+// runSubscriptionCases checks resource subscriptions: every listed resource
+// is subscribed to, a tool is called to change one of them and the server
+// must then send notifications/resources/updated for it. Once unsubscribed,
+// calling the tool again must not produce further updates.
+func runSubscriptionCases(client *core.MCPClient, opts *TestOptions, id *int, suite *TestSuite) {
+	tc, listed := runListCase(client, opts, "Resources", "resources/list", id)
+	if !suite.Add(tc).Passed() {
+		return
+	}
+	result, err := core.DecodeListResourcesResult(listed)
+	if err != nil {
+		suite.Add(&TestCase{Name: "resources/list result", Status: StatusFailed, Message: err.Error(), Response: listed})
+		return
+	}
+	if len(result.Resources) == 0 {
+		suite.Add(&TestCase{Name: "resources/subscribe", Status: StatusSkipped, Message: "Subscriptions: no resources to subscribe to"})
+		return
+	}
+
+	updates := make(chan string, 64)
+	remove := client.OnResourceUpdated("", func(uri string) {
+		select {
+		case updates <- uri:
+		default:
+		}
+	})
+	defer remove()
+
+	subscribed := map[string]bool{}
+	var resources []core.ResourceDefinition
+	for _, resource := range result.Resources {
+		if tc := runSubscribeCase(client, opts, "resources/subscribe", resource, id); suite.Add(tc).Passed() {
+			subscribed[resource.URI] = true
+			resources = append(resources, resource)
+		}
+	}
+	if len(resources) == 0 {
+		return
+	}
+
+	tc, trigger := runTriggerCase(client, opts, subscribed, updates, id)
+	suite.Add(tc)
+	for _, resource := range resources {
+		suite.Add(runSubscribeCase(client, opts, "resources/unsubscribe", resource, id))
+	}
+	if trigger != nil && tc.Passed() {
+		suite.Add(runUnsubscribedCase(client, opts, *trigger, subscribed, updates, id))
+	}
+}
+
+// runSubscribeCase subscribes to or unsubscribes from a resource, according
+// to method.
+func runSubscribeCase(client *core.MCPClient, opts *TestOptions, method string, resource core.ResourceDefinition, id *int) *TestCase {
+	tc := &TestCase{Name: method + " " + resource.Name, Status: StatusFailed}
+	tc.Request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: method, Params: map[string]interface{}{"uri": resource.URI}, ID: *id}
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	var resp *core.Response
+	var err error
+	if method == "resources/subscribe" {
+		resp, err = client.SubscribeResourceContext(ctx, resource.URI, *id)
+	} else {
+		resp, err = client.UnsubscribeResourceContext(ctx, resource.URI, *id)
+	}
+	tc.Duration = time.Since(start)
+	cancel()
+	*id++
+
+	switch {
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Resource %s: %s", resource.URI, describeCallError(client, err))
+		return tc
+	case resp.Error != nil:
+		tc.Response = resp
+		tc.Message = fmt.Sprintf("Resource %s: %s failed: %s (code %d)", resource.URI, method, resp.Error.Message, resp.Error.Code)
+		return tc
+	}
+	tc.Response = resp
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("Resource %s: %s succeeded", resource.URI, method)
+	return tc
+}
+
+// runTriggerCase calls tools until one of them makes the server report an
+// update to a subscribed resource, and returns the tool that did. Only the
+// tool named by opts.TriggerTool is tried when it is set.
+func runTriggerCase(client *core.MCPClient, opts *TestOptions, subscribed map[string]bool, updates <-chan string, id *int) (*TestCase, *core.ToolDefinition) {
+	tc := &TestCase{Name: core.ResourceUpdatedNotification, Status: StatusFailed}
+	ctx, cancel := requestContext(opts)
+	listed, err := client.ListToolsContext(ctx, *id)
+	cancel()
+	*id++
+	var tools []core.ToolDefinition
+	if err == nil && listed.Error == nil {
+		if result, err := core.DecodeListToolsResult(listed); err == nil {
+			tools = result.Tools
+		}
+	}
+	if opts.TriggerTool != "" {
+		trigger := core.ToolDefinition{Name: opts.TriggerTool}
+		for _, tool := range tools {
+			if tool.Name == opts.TriggerTool {
+				trigger = tool
+			}
+		}
+		tools = []core.ToolDefinition{trigger}
+	}
+	if len(tools) == 0 {
+		tc.Status = StatusSkipped
+		tc.Message = "Subscriptions: no tool to trigger a resource update"
+		return tc, nil
+	}
+
+	start := time.Now()
+	for i := range tools {
+		drainUpdates(updates)
+		if err := callTrigger(client, opts, tools[i], id); err != nil {
+			tc.Failures = append(tc.Failures, err.Error())
+			continue
+		}
+		if uri, ok := awaitUpdate(updates, subscribed, updateWait); ok {
+			tc.Duration = time.Since(start)
+			tc.Failures = nil
+			tc.Status = StatusPassed
+			tc.Message = fmt.Sprintf("Resource %s updated after calling tool %s", uri, tools[i].Name)
+			return tc, &tools[i]
+		}
+	}
+	tc.Duration = time.Since(start)
+	tc.Message = fmt.Sprintf("No %s notification for a subscribed resource after calling %d tool(s)", core.ResourceUpdatedNotification, len(tools))
+	return tc, nil
+}
+
+// runUnsubscribedCase calls the tool which triggered an update again, after
+// the resources were unsubscribed from, and fails if an update still comes.
+func runUnsubscribedCase(client *core.MCPClient, opts *TestOptions, trigger core.ToolDefinition, subscribed map[string]bool, updates <-chan string, id *int) *TestCase {
+	tc := &TestCase{Name: core.ResourceUpdatedNotification + " after unsubscribe", Status: StatusFailed}
+	drainUpdates(updates)
+	start := time.Now()
+	if err := callTrigger(client, opts, trigger, id); err != nil {
+		tc.Duration = time.Since(start)
+		tc.Status = StatusError
+		tc.Message = err.Error()
+		return tc
+	}
+	uri, updated := awaitUpdate(updates, subscribed, exitWait)
+	tc.Duration = time.Since(start)
+	if updated {
+		tc.Message = fmt.Sprintf("Resource %s updated after unsubscribing from it", uri)
+		return tc
+	}
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("No update after unsubscribing and calling tool %s", trigger.Name)
+	return tc
+}
+
+// callTrigger calls a tool with arguments generated from its inputSchema, or
+// without arguments when it has none.
+func callTrigger(client *core.MCPClient, opts *TestOptions, tool core.ToolDefinition, id *int) error {
+	var args map[string]interface{}
+	if schema, err := tool.CompileInputSchema(); err == nil {
+		if sample, err := schema.Sample(); err == nil {
+			args, _ = sample.(map[string]interface{})
+		}
+	}
+	ctx, cancel := requestContext(opts)
+	resp, err := client.CallToolContext(ctx, tool.Name, args, *id)
+	cancel()
+	*id++
+	switch {
+	case err != nil:
+		return fmt.Errorf("tool %s: %s", tool.Name, describeCallError(client, err))
+	case resp.Error != nil:
+		return fmt.Errorf("tool %s: tools/call failed: %s (code %d)", tool.Name, resp.Error.Message, resp.Error.Code)
+	}
+	return nil
+}
+
+// awaitUpdate waits up to timeout for an update about one of the subscribed
+// resources.
+func awaitUpdate(updates <-chan string, subscribed map[string]bool, timeout time.Duration) (string, bool) {
+	deadline := time.After(timeout)
+	for {
+		select {
+		case uri := <-updates:
+			if subscribed[uri] {
+				return uri, true
+			}
+		case <-deadline:
+			return "", false
+		}
+	}
+}
+
+func drainUpdates(updates <-chan string) {
+	for {
+		select {
+		case <-updates:
+		default:
+			return
+		}
+	}
+}
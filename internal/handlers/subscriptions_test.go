@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
+)
+
+func TestCollectTests_Subscriptions(t *testing.T) {
+	opts := &TestOptions{TestSubscriptions: true, TriggerTool: mcptest.EchoTool, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("subscriptions").Cases
+	want := []struct {
+		name    string
+		message string
+	}{
+		{"resources", "Resources:"},
+		{"resources/subscribe greeting", "resources/subscribe succeeded"},
+		{"notifications/resources/updated", "Resource file:///greeting.txt updated after calling tool echo"},
+		{"resources/unsubscribe greeting", "resources/unsubscribe succeeded"},
+		{"notifications/resources/updated after unsubscribe", "No update after unsubscribing"},
+	}
+	if len(cases) != len(want) {
+		t.Fatalf("expected %d cases, got %d", len(want), len(cases))
+	}
+	for i, w := range want {
+		c := cases[i]
+		if c.Name != w.name || c.Status != StatusPassed || !strings.Contains(c.Message, w.message) {
+			t.Errorf("case %d: got %s %s %q, want %s passed %q", i, c.Name, c.Status, c.Message, w.name, w.message)
+		}
+	}
+}
+
+// TestCollectTests_SubscriptionsWithoutUpdates uses a server accepting
+// subscriptions which never reports updates.
+func TestCollectTests_SubscriptionsWithoutUpdates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		switch req.Method {
+		case "initialize":
+			resp.Result = core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "silent", Version: "1.0.0"}}
+		case "resources/list":
+			resp.Result = map[string]interface{}{"resources": []interface{}{
+				map[string]interface{}{"uri": "file:///a.txt", "name": "a"},
+				map[string]interface{}{"uri": "file:///b.txt", "name": "b"},
+			}}
+		case "resources/subscribe":
+			if req.Params["uri"] == "file:///b.txt" {
+				resp = core.NewErrorResponse(req.ID, core.InvalidParams, "cannot subscribe", nil)
+				break
+			}
+			resp.Result = map[string]interface{}{}
+		case "resources/unsubscribe":
+			resp.Result = map[string]interface{}{}
+		case "tools/list":
+			resp.Result = map[string]interface{}{"tools": []interface{}{
+				map[string]interface{}{"name": "touch", "inputSchema": map[string]interface{}{"type": "object"}},
+			}}
+		case "tools/call":
+			resp.Result = map[string]interface{}{"content": []interface{}{}}
+		default:
+			resp = core.NewErrorResponse(req.ID, core.MethodNotFound, "Method not found", nil)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	opts := &TestOptions{TestSubscriptions: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "silent", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("subscriptions").Cases
+	want := []struct {
+		name    string
+		status  TestStatus
+		message string
+	}{
+		{"resources", StatusPassed, "Resources:"},
+		{"resources/subscribe a", StatusPassed, "resources/subscribe succeeded"},
+		{"resources/subscribe b", StatusFailed, "resources/subscribe failed: cannot subscribe (code -32602)"},
+		{"notifications/resources/updated", StatusFailed, "No notifications/resources/updated notification for a subscribed resource after calling 1 tool(s)"},
+		{"resources/unsubscribe a", StatusPassed, "resources/unsubscribe succeeded"},
+	}
+	if len(cases) != len(want) {
+		t.Fatalf("expected %d cases, got %d", len(want), len(cases))
+	}
+	for i, w := range want {
+		c := cases[i]
+		if c.Name != w.name || c.Status != w.status || !strings.Contains(c.Message, w.message) {
+			t.Errorf("case %d: got %s %s %q, want %s %s %q", i, c.Name, c.Status, c.Message, w.name, w.status, w.message)
+		}
+	}
+}
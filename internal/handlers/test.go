@@ -1,11 +1,13 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
+	"time"
 
 	"github.com/aawadall/mcpcli/internal/core"
 )
@@ -16,11 +18,46 @@ type TestOptions struct {
 	TestAll          bool
 	TestResources    bool
 	TestTools        bool
+	TestPrompts      bool
 	TestCapabilities bool
 	TestInit         bool
-	ScriptFile       string
+	// TestSubscriptions checks that subscribed resources produce update
+	// notifications. --all runs it when the server advertises subscriptions.
+	TestSubscriptions bool
+	// TriggerTool is the tool called to change a subscribed resource. Every
+	// tool is tried when it is empty.
+	TriggerTool string
+	ScriptFile  string
+	// ProtocolVersion is the MCP revision requested during initialization.
+	// It defaults to core.LatestProtocolVersion.
+	ProtocolVersion string
+	// Timeout bounds each request sent to the server. Zero disables it.
+	Timeout time.Duration
+	// Report is the machine-readable format of the results, one of
+	// ReportFormats. The human-readable output is used when empty.
+	Report string
+	// ReportFile receives the machine-readable results. When empty they are
+	// written to stdout instead of the human-readable output.
+	ReportFile string
+	// Fuzz calls every tool with inputs generated from its inputSchema.
+	Fuzz bool
+	// FuzzIterations is the number of random inputs per tool, in addition to
+	// the edge cases.
+	FuzzIterations int
+	// FuzzSeed makes the generated inputs reproducible.
+	FuzzSeed int64
+	// FuzzOutput is the directory receiving reproducer scripts.
+	FuzzOutput string
+	// Record is the path of a JSON Lines file receiving every frame
+	// exchanged with the server, for `mcpcli replay`.
+	Record string
+	// Conformance runs the checks of the built-in conformance suite.
+	Conformance bool
 }
 
+// DefaultTestTimeout is the per-request timeout used by `mcpcli test`.
+const DefaultTestTimeout = 30 * time.Second
+
 // LoadMCPConfig reads a configuration file which may be either an MCPConfig
 // or a ProjectConfig and returns the resulting MCPConfig.
 func LoadMCPConfig(configPath string) (*core.MCPConfig, error) {
@@ -40,84 +77,289 @@ func LoadMCPConfig(configPath string) (*core.MCPConfig, error) {
 	return &config, nil
 }
 
-// RunTests connects to an MCP server based on the config and executes the
-// selected tests.
+// RunTests connects to an MCP server based on the config, executes the
+// selected tests and prints their results, or writes them in the requested
+// report format. It returns an error when the tests could not be run or when
+// any of them failed.
 func RunTests(opts *TestOptions, config *core.MCPConfig) error {
-	var client *core.MCPClient
-	if config.Transport.Type == "stdio" {
-		if serverCmd, ok := config.Transport.Options["command"].(string); ok && serverCmd != "" {
-			parts := strings.Fields(serverCmd)
-			if len(parts) == 0 {
-				return fmt.Errorf("invalid server command: %s", serverCmd)
-			}
-			cmd := exec.Command(parts[0], parts[1:]...)
-			stdin, err := cmd.StdinPipe()
-			if err != nil {
-				return fmt.Errorf("failed to create stdin pipe: %w", err)
-			}
-			stdout, err := cmd.StdoutPipe()
-			if err != nil {
-				return fmt.Errorf("failed to create stdout pipe: %w", err)
-			}
-			if err := cmd.Start(); err != nil {
-				return fmt.Errorf("failed to start server: %w", err)
-			}
-			defer cmd.Wait()
-			client = core.NewMCPClientWithIO(stdout, stdin, os.Stderr)
-		} else {
-			client = core.NewMCPClient()
+	format, err := resolveReportFormat(opts.Report, opts.ReportFile)
+	if err != nil {
+		return err
+	}
+	report, err := CollectTests(opts, config)
+	if err != nil {
+		return err
+	}
+	return writeResults(report, format, opts.ReportFile)
+}
+
+// CollectTests connects to an MCP server based on the config and executes the
+// selected tests, returning their results. The error reports problems
+// preventing the tests from running, not failed tests.
+func CollectTests(opts *TestOptions, config *core.MCPConfig) (*TestReport, error) {
+	var scenario *core.Scenario
+	if opts.ScriptFile != "" {
+		var err error
+		if scenario, err = core.LoadScenario(opts.ScriptFile); err != nil {
+			return nil, err
 		}
-	} else {
-		client = core.NewMCPClient()
 	}
 
+	transport, err := core.NewClientTransport(config.Transport)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Record == "" {
+		client := core.NewMCPClientWithTransport(transport, os.Stderr)
+		defer client.Close()
+		return runSelectedTests(client, opts, config, scenario), nil
+	}
+
+	recording, err := os.Create(opts.Record)
+	if err != nil {
+		transport.Close()
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+	recorder := core.NewRecordingTransport(transport, recording)
+	client := core.NewMCPClientWithTransport(recorder, os.Stderr)
+	report := runSelectedTests(client, opts, config, scenario)
+	client.Close()
+	if err := recording.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write recording: %w", err)
+	}
+	if err := recorder.Err(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// runSelectedTests initializes the session and runs the tests selected by
+// opts, or the scenario when one is given.
+func runSelectedTests(client *core.MCPClient, opts *TestOptions, config *core.MCPConfig, scenario *core.Scenario) *TestReport {
+	report := &TestReport{Started: time.Now()}
+	defer func() { report.Duration = time.Since(report.Started) }()
+
 	id := 1
-	if opts.ScriptFile != "" {
-		fmt.Printf("⚠️ Reading and executing script: %s\n", opts.ScriptFile)
-		fmt.Printf("⚠️ Script execution is not implemented yet\n")
-		// Continue without failing to allow tests to proceed
-		return nil
+	version := opts.ProtocolVersion
+	if version == "" {
+		version = core.LatestProtocolVersion
+	}
+	clientInfo := core.Implementation{Name: "mcpcli", Version: core.CLIVersion}
+	var initResult *core.InitializeResult
+	var initErr error
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	initRequest, initResponse := recordExchange(client, id, func() {
+		initResult, initErr = client.InitializeContext(ctx, version, clientInfo, core.ClientCapabilities{}, id)
+	})
+	initDuration := time.Since(start)
+	cancel()
+	id++
+
+	if scenario != nil {
+		suite := report.Suite("script")
+		if initErr != nil {
+			suite.Add(&TestCase{
+				Name:     "initialize",
+				Status:   StatusError,
+				Message:  fmt.Sprintf("Script not run: initialization failed: %v", initErr),
+				Request:  initRequest,
+				Response: initResponse,
+			})
+			return report
+		}
+		runScript(client, scenario, opts, &id, suite)
+		return report
+	}
+
+	if opts.TestAll || opts.TestInit {
+		tc := initCase(version, initResult, initErr)
+		tc.Duration = initDuration
+		if !tc.Passed() {
+			tc.Request, tc.Response = initRequest, initResponse
+		}
+		report.Suite("initialization").Add(tc)
+	}
+
+	if opts.TestAll || opts.TestCapabilities {
+		report.Suite("capabilities").Add(capabilitiesCase(config.Capabilities, initResult, initErr))
 	}
 
 	if opts.TestAll || opts.TestResources {
-		fmt.Printf("⚠️ Testing resources...\n")
-		fmt.Printf("⚠️ Sending request: {\"method\":\"resources/list\",\"id\":%d}\n", id)
-		resp, err := client.ListResources(id)
-		id++
-		formatAndPrintResult("Resources", resp, err)
+		suite := report.Suite("resources")
+		if tc, _ := runListCase(client, opts, "Resources", "resources/list", &id); suite.Add(tc).Passed() {
+			runResourceTemplateCases(client, opts, &id, suite)
+		}
 	}
 
 	if opts.TestAll || opts.TestTools {
-		fmt.Printf("⚠️ Testing tools...\n")
-		fmt.Printf("⚠️ Sending request: {\"method\":\"tools/list\",\"id\":%d}\n", id)
-		resp, err := client.ListTools(id)
-		id++
-		if err != nil {
-			fmt.Printf("❌ Failed to list tools: %v\n", err)
-			fmt.Printf("⚠️ Make sure an MCP server is running and connected via stdin/stdout\n")
-		} else if resp.Error != nil {
-			fmt.Printf("❌ MCP error: %s\n", resp.Error.Message)
-		} else {
-			fmt.Printf("✅ Tools: %v\n", resp.Result)
+		suite := report.Suite("tools")
+		if tc, resp := runListCase(client, opts, "Tools", "tools/list", &id); suite.Add(tc).Passed() {
+			runToolCases(client, opts, resp, &id, suite)
 		}
 	}
 
-	// TODO: Add capabilities and init tests
-	return nil
+	// --all only covers prompts when the server advertises them, since many
+	// servers offer none.
+	if opts.TestPrompts || opts.TestAll && initErr == nil && initResult.Capabilities.Prompts != nil {
+		suite := report.Suite("prompts")
+		if tc, resp := runListCase(client, opts, "Prompts", "prompts/list", &id); suite.Add(tc).Passed() {
+			runPromptCases(client, opts, resp, &id, suite)
+		}
+	}
+
+	if opts.TestSubscriptions || opts.TestAll && initErr == nil && initResult.Capabilities.Resources != nil && initResult.Capabilities.Resources.Subscribe {
+		runSubscriptionCases(client, opts, &id, report.Suite("subscriptions"))
+	}
+
+	if opts.Conformance {
+		runConformanceChecks(client, opts, initResult, initErr, &id, report.Suite(ConformanceSuiteName+"@"+ConformanceSuiteVersion))
+	}
+
+	if opts.Fuzz {
+		suite := report.Suite("fuzz")
+		if tc, resp := runListCase(client, opts, "Tools", "tools/list", &id); suite.Add(tc).Passed() {
+			runFuzzCases(client, opts, resp, &id, suite)
+		}
+	}
+	return report
 }
 
-// formatAndPrintResult prints the result of a test request in a consistent way.
-// It reports errors from the transport, MCP errors from the response, or the
-// successful result value.
-func formatAndPrintResult(name string, resp *core.Response, err error) {
-	if err != nil {
-		fmt.Printf("❌ Failed to list %s: %v\n", strings.ToLower(name), err)
-		fmt.Printf("⚠️ Make sure an MCP server is running and connected via stdin/stdout\n")
-		return
+// runListCase sends a list request and records its outcome.
+func runListCase(client *core.MCPClient, opts *TestOptions, name, method string, id *int) (*TestCase, *core.Response) {
+	var resp *core.Response
+	var err error
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	req, got := recordExchange(client, *id, func() {
+		resp, err = client.CallContext(ctx, method, nil, *id)
+	})
+	elapsed := time.Since(start)
+	cancel()
+	*id++
+	tc := listCase(name, resp, err)
+	tc.Duration = elapsed
+	if !tc.Passed() {
+		tc.Request, tc.Response = req, got
+	}
+	return tc, resp
+}
+
+// recordExchange runs call and returns the request the client sent with id
+// and the response the server sent back.
+func recordExchange(client *core.MCPClient, id interface{}, call func()) (*core.Request, *core.Response) {
+	var req *core.Request
+	var resp *core.Response
+	remove := client.OnExchange(func(request *core.Request, response *core.Response) {
+		if core.IDKey(request.ID) == core.IDKey(id) {
+			req, resp = request, response
+		}
+	})
+	call()
+	remove()
+	return req, resp
+}
+
+// requestContext returns the context bounding a single request according to
+// the configured timeout.
+func requestContext(opts *TestOptions) (context.Context, context.CancelFunc) {
+	if opts.Timeout <= 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), opts.Timeout)
+}
+
+// initCase checks the outcome of the initialize handshake. A server
+// answering with a protocol version other than the requested one, or one the
+// client does not support, fails the case.
+func initCase(requested string, result *core.InitializeResult, err error) *TestCase {
+	tc := &TestCase{Name: "initialize"}
+	var versionErr *core.ProtocolVersionError
+	switch {
+	case errors.As(err, &versionErr):
+		tc.Status = StatusFailed
+		tc.Message = fmt.Sprintf("Initialization failed: %v", err)
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Initialization failed: %v", err)
+	case result.ProtocolVersion != requested:
+		tc.Status = StatusFailed
+		tc.Message = fmt.Sprintf("Protocol version mismatch: requested %s, server returned %s", requested, result.ProtocolVersion)
+	default:
+		tc.Status = StatusPassed
+		tc.Message = fmt.Sprintf("Initialization: %s %s (protocol %s)", result.ServerInfo.Name, result.ServerInfo.Version, result.ProtocolVersion)
+	}
+	return tc
+}
+
+// capabilitiesCase checks that every capability enabled in the configuration
+// is advertised by the server during initialization.
+func capabilitiesCase(expected core.Capabilities, result *core.InitializeResult, err error) *TestCase {
+	tc := &TestCase{Name: "capabilities"}
+	if err != nil || result == nil {
+		tc.Status = StatusError
+		tc.Message = "Failed to read capabilities: initialization did not succeed"
+		return tc
+	}
+	var missing []string
+	if expected.Resources.Enabled && result.Capabilities.Resources == nil {
+		missing = append(missing, "resources")
+	}
+	if expected.Tools.Enabled && result.Capabilities.Tools == nil {
+		missing = append(missing, "tools")
+	}
+	if expected.Prompts.Enabled && result.Capabilities.Prompts == nil {
+		missing = append(missing, "prompts")
+	}
+	if len(missing) > 0 {
+		tc.Status = StatusFailed
+		tc.Message = fmt.Sprintf("Capabilities not advertised by server: %s", strings.Join(missing, ", "))
+		return tc
+	}
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("Capabilities: %s", describeCapabilities(result.Capabilities))
+	return tc
+}
+
+// describeCapabilities lists the capability names advertised by a server.
+func describeCapabilities(caps core.ServerCapabilities) string {
+	var names []string
+	if caps.Resources != nil {
+		names = append(names, "resources")
+	}
+	if caps.Tools != nil {
+		names = append(names, "tools")
+	}
+	if caps.Prompts != nil {
+		names = append(names, "prompts")
+	}
+	if caps.Logging != nil {
+		names = append(names, "logging")
 	}
-	if resp.Error != nil {
-		fmt.Printf("❌ MCP error: %s\n", resp.Error.Message)
-		return
+	if caps.Completions != nil {
+		names = append(names, "completions")
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, ", ")
+}
+
+// listCase checks the answer to a list request. It reports errors from the
+// transport, MCP errors from the response, or the successful result value.
+func listCase(name string, resp *core.Response, err error) *TestCase {
+	tc := &TestCase{Name: strings.ToLower(name)}
+	switch {
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Failed to list %s: %v", strings.ToLower(name), err)
+	case resp.Error != nil:
+		tc.Status = StatusFailed
+		tc.Message = fmt.Sprintf("MCP error: %s", resp.Error.Message)
+		tc.Response = resp
+	default:
+		tc.Status = StatusPassed
+		tc.Message = fmt.Sprintf("%s: %v", name, resp.Result)
 	}
-	fmt.Printf("✅ %s: %v\n", name, resp.Result)
+	return tc
 }
@@ -5,12 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/mcptest"
 )
 
 func writeConfig(t *testing.T, dir string) string {
@@ -58,15 +62,89 @@ func TestRunTests_BadCommand(t *testing.T) {
 func TestRunTests_NoServer(t *testing.T) {
 	opts := &TestOptions{TestAll: true}
 	cfg := &core.MCPConfig{Name: "none", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "true"}}}
-	if err := RunTests(opts, cfg); err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err == nil || !strings.Contains(err.Error(), "4 of 4 tests failed") {
+		t.Fatalf("expected all tests to fail, got %v", err)
+	}
+	if !strings.Contains(out, "Tests: 0 passed, 4 failed") {
+		t.Fatalf("unexpected output: %s", out)
 	}
 }
 func TestRunTests_WithScript(t *testing.T) {
 	opts := &TestOptions{ScriptFile: "script.txt"}
 	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "true"}}}
-	if err := RunTests(opts, cfg); err != nil {
-		t.Fatalf("RunTests with script failed: %v", err)
+	if err := RunTests(opts, cfg); err == nil {
+		t.Fatal("expected error for missing script file")
+	}
+}
+
+func writeScript(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "scenario.json")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunTests_ScriptPasses(t *testing.T) {
+	script := writeScript(t, `{
+  "name": "echo",
+  "steps": [
+    {"name": "list", "method": "tools/list",
+     "assert": [
+       {"path": "$.result.tools", "type": "array"},
+       {"path": "$.result.tools[0].name", "equals": "echo"},
+       {"path": "$.result.tools[*].name", "equals": ["echo"]}
+     ],
+     "capture": {"tool": "$.result.tools[0].name"}},
+    {"name": "call", "method": "tools/call", "params": {"name": "${tool}", "arguments": {"message": "hi ${tool}"}},
+     "assert": [{"path": "$.result.content[0].text", "matches": "^hi echo$"}]},
+    {"name": "missing", "method": "resources/read", "params": {"uri": "file:///nope"},
+     "expectError": {"code": -32002, "message": "not found"}},
+    {"method": "notifications/roots/list_changed", "notification": true}
+  ]
+}`)
+	opts := &TestOptions{ScriptFile: script, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Tests: 4 passed, 0 failed") || !strings.Contains(out, "✅ Step 2: call") {
+		t.Fatalf("unexpected output: %s", out)
+	}
+}
+
+func TestRunTests_ScriptFails(t *testing.T) {
+	script := writeScript(t, `{"steps": [
+    {"method": "tools/list", "assert": [{"path": "$.result.tools[0].name", "equals": "other"}]},
+    {"method": "bogus"},
+    {"method": "tools/list", "expectError": {"code": -32601}}
+  ]}`)
+	opts := &TestOptions{ScriptFile: script, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": mcptest.Command(t)}}}
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err == nil || !strings.Contains(err.Error(), "3 of 3 tests failed") {
+		t.Fatalf("expected failing steps, got %v", err)
+	}
+	for _, want := range []string{`expected "other", got "echo"`, "Method not found: bogus", "expected an error response", `request:  {"jsonrpc":"2.0","method":"bogus","id":`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q: %s", want, out)
+		}
+	}
+}
+
+func TestRunTests_ScriptInvalid(t *testing.T) {
+	script := writeScript(t, `{"steps": [{"method": "tools/list", "assert": [{"path": "$.x", "equal": 1}]}]}`)
+	opts := &TestOptions{ScriptFile: script}
+	cfg := &core.MCPConfig{Name: "s", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "true"}}}
+	err := RunTests(opts, cfg)
+	if err == nil || !strings.Contains(err.Error(), "equal") {
+		t.Fatalf("expected unknown field error, got %v", err)
 	}
 }
 
@@ -98,31 +176,141 @@ func captureOutput(f func()) string {
 	return buf.String()
 }
 
-func TestFormatAndPrintResult_Error(t *testing.T) {
-	out := captureOutput(func() {
-		formatAndPrintResult("Tools", nil, fmt.Errorf("boom"))
-	})
-	if !strings.Contains(out, "Failed to list tools") {
-		t.Fatalf("unexpected output: %s", out)
+func TestListCase(t *testing.T) {
+	tc := listCase("Tools", nil, fmt.Errorf("boom"))
+	if tc.Status != StatusError || !strings.Contains(tc.Message, "Failed to list tools") {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+
+	tc = listCase("Tools", &core.Response{Error: &core.Error{Message: "bad"}}, nil)
+	if tc.Status != StatusFailed || !strings.Contains(tc.Message, "MCP error") || tc.Response == nil {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+
+	tc = listCase("Tools", &core.Response{Result: "ok"}, nil)
+	if tc.Status != StatusPassed || tc.Message != "Tools: ok" {
+		t.Fatalf("unexpected case: %+v", tc)
 	}
 }
 
-func TestFormatAndPrintResult_MCPError(t *testing.T) {
-	resp := &core.Response{Error: &core.Error{Message: "bad"}}
-	out := captureOutput(func() {
-		formatAndPrintResult("Tools", resp, nil)
-	})
-	if !strings.Contains(out, "MCP error") {
-		t.Fatalf("unexpected output: %s", out)
+func TestInitCase(t *testing.T) {
+	ok := &core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "srv", Version: "1.0"}}
+	if tc := initCase(core.LatestProtocolVersion, ok, nil); tc.Status != StatusPassed || !strings.Contains(tc.Message, "Initialization: srv 1.0") {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+
+	mismatch := &core.InitializeResult{ProtocolVersion: "2024-11-05"}
+	if tc := initCase(core.LatestProtocolVersion, mismatch, nil); tc.Status != StatusFailed || !strings.Contains(tc.Message, "Protocol version mismatch") {
+		t.Fatalf("expected mismatch failure, got: %+v", tc)
+	}
+
+	versionErr := &core.ProtocolVersionError{Requested: core.LatestProtocolVersion, Received: "1999-01-01"}
+	if tc := initCase(core.LatestProtocolVersion, mismatch, versionErr); tc.Status != StatusFailed || !strings.Contains(tc.Message, "unsupported protocol version") {
+		t.Fatalf("expected unsupported version failure, got: %+v", tc)
+	}
+
+	if tc := initCase(core.LatestProtocolVersion, nil, fmt.Errorf("boom")); tc.Status != StatusError || tc.Message != "Initialization failed: boom" {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+}
+
+func TestCapabilitiesCase(t *testing.T) {
+	expected := core.Capabilities{Tools: core.ToolsCapability{Enabled: true}, Resources: core.ResourcesCapability{Enabled: true}}
+	result := &core.InitializeResult{Capabilities: core.ServerCapabilities{Tools: &core.ServerToolsCapability{}}}
+	if tc := capabilitiesCase(expected, result, nil); tc.Status != StatusFailed || !strings.Contains(tc.Message, "not advertised by server: resources") {
+		t.Fatalf("expected missing resources, got: %+v", tc)
+	}
+
+	result.Capabilities.Resources = &core.ServerResourcesCapability{}
+	if tc := capabilitiesCase(expected, result, nil); tc.Status != StatusPassed || tc.Message != "Capabilities: resources, tools" {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+
+	if tc := capabilitiesCase(expected, nil, fmt.Errorf("no server")); tc.Status != StatusError || !strings.Contains(tc.Message, "initialization did not succeed") {
+		t.Fatalf("unexpected case: %+v", tc)
+	}
+}
+
+func TestRunTests_Timeout(t *testing.T) {
+	opts := &TestOptions{TestTools: true, Timeout: 100 * time.Millisecond}
+	cfg := &core.MCPConfig{Name: "hung", Transport: core.Transport{Type: "stdio", Options: map[string]any{"command": "sleep 30"}}}
+	start := time.Now()
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err == nil {
+		t.Fatal("expected the timed out test to fail")
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("RunTests blocked for %v", elapsed)
+	}
+	if !strings.Contains(out, "deadline exceeded") {
+		t.Fatalf("expected timeout failure, got: %s", out)
 	}
 }
 
-func TestFormatAndPrintResult_Success(t *testing.T) {
-	resp := &core.Response{Result: "ok"}
-	out := captureOutput(func() {
-		formatAndPrintResult("Tools", resp, nil)
-	})
-	if !strings.Contains(out, "✅ Tools: ok") {
+func TestRunTests_HTTPTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		var result interface{} = map[string]interface{}{"tools": []interface{}{}}
+		if req.Method == "initialize" {
+			result = core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "http", Version: "1.0.0"}}
+		}
+		json.NewEncoder(w).Encode(&core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID, Result: result})
+	}))
+	defer srv.Close()
+	opts := &TestOptions{TestTools: true, TestInit: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "http", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	var err error
+	out := captureOutput(func() { err = RunTests(opts, cfg) })
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out, "✅ Tools") || !strings.Contains(out, "http 1.0.0") {
 		t.Fatalf("unexpected output: %s", out)
 	}
 }
+
+func TestCollectTests_RecordsExchanges(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := core.NewErrorResponse(req.ID, core.InternalError, "no tools", nil)
+		if req.Method == "initialize" {
+			resp = &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID, Result: core.InitializeResult{ProtocolVersion: "2024-11-05", ServerInfo: core.Implementation{Name: "old", Version: "1.0.0"}}}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+	opts := &TestOptions{TestInit: true, TestTools: true, Timeout: 5 * time.Second}
+	cfg := &core.MCPConfig{Name: "http", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	init := report.Suite("initialization").Cases[0]
+	if init.Passed() || init.Request == nil || init.Response == nil {
+		t.Fatalf("expected the failed initialize exchange, got %+v", init)
+	}
+	if init.Request.Params["protocolVersion"] != core.LatestProtocolVersion || init.Request.Params["clientInfo"] == nil {
+		t.Errorf("initialize request not recorded as sent: %+v", init.Request.Params)
+	}
+	if result, _ := init.Response.Result.(map[string]interface{}); result["protocolVersion"] != "2024-11-05" {
+		t.Errorf("initialize response not recorded as received: %+v", init.Response)
+	}
+
+	list := report.Suite("tools").Cases[0]
+	if list.Passed() || list.Request == nil || list.Request.Method != "tools/list" || core.IDKey(list.Request.ID) != "2" {
+		t.Fatalf("expected the failed tools/list request, got %+v", list.Request)
+	}
+	if list.Response == nil || list.Response.Error == nil || list.Response.Error.Message != "no tools" {
+		t.Errorf("expected the tools/list error response, got %+v", list.Response)
+	}
+}
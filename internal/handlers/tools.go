@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// exitWait is how long a failed call waits for the server process to exit
+// before the failure is attributed to something else than a crash.
+const exitWait = 200 * time.Millisecond
+
+// runToolCases checks the tools listed in a tools/list response: each tool's
+// inputSchema must be a valid JSON Schema, and the tool is called with
+// arguments generated from it. Tools that crash the server, time out, answer
+// with an error or return malformed content fail.
+func runToolCases(client *core.MCPClient, opts *TestOptions, listed *core.Response, id *int, suite *TestSuite) {
+	result, err := core.DecodeListToolsResult(listed)
+	if err != nil {
+		suite.Add(&TestCase{Name: "tools/list result", Status: StatusFailed, Message: err.Error(), Response: listed})
+		return
+	}
+	for _, tool := range result.Tools {
+		suite.Add(runToolCase(client, opts, tool, id))
+	}
+}
+
+func runToolCase(client *core.MCPClient, opts *TestOptions, tool core.ToolDefinition, id *int) *TestCase {
+	tc := &TestCase{Name: "tools/call " + tool.Name, Status: StatusFailed}
+	schema, err := tool.CompileInputSchema()
+	if err != nil {
+		tc.Message = fmt.Sprintf("Tool %s: invalid inputSchema: %v", tool.Name, err)
+		return tc
+	}
+	sample, err := schema.Sample()
+	if err != nil {
+		tc.Message = fmt.Sprintf("Tool %s: cannot generate arguments from inputSchema: %v", tool.Name, err)
+		return tc
+	}
+	args, _ := sample.(map[string]interface{})
+
+	params := map[string]interface{}{"name": tool.Name, "arguments": args}
+	tc.Request = &core.Request{JSONRPC: core.JSONRPCVersion, Method: "tools/call", Params: params, ID: *id}
+	ctx, cancel := requestContext(opts)
+	start := time.Now()
+	resp, err := client.CallToolContext(ctx, tool.Name, args, *id)
+	tc.Duration = time.Since(start)
+	cancel()
+	*id++
+
+	switch {
+	case err != nil:
+		tc.Status = StatusError
+		tc.Message = fmt.Sprintf("Tool %s: %s", tool.Name, describeCallError(client, err))
+		return tc
+	case resp.Error != nil:
+		tc.Response = resp
+		tc.Message = fmt.Sprintf("Tool %s: tools/call failed: %s (code %d)", tool.Name, resp.Error.Message, resp.Error.Code)
+		return tc
+	}
+	tc.Response = resp
+	if problems := core.CheckToolResult(resp.Result); len(problems) > 0 {
+		tc.Message = fmt.Sprintf("Tool %s: malformed result", tool.Name)
+		for _, p := range problems {
+			tc.Failures = append(tc.Failures, p.Error())
+		}
+		return tc
+	}
+	tc.Status = StatusPassed
+	tc.Message = fmt.Sprintf("Tool %s: called with %s", tool.Name, marshalCompact(args))
+	if isError, _ := resp.Result.(map[string]interface{})["isError"].(bool); isError {
+		tc.Message += " (reported a tool error)"
+	}
+	if len(schema.Unsupported) > 0 {
+		tc.Message += fmt.Sprintf(" (arguments generated ignoring unsupported inputSchema keywords: %s)", strings.Join(schema.Unsupported, "; "))
+	}
+	return tc
+}
+
+// describeCallError explains why a call got no answer, telling timeouts and
+// crashes of a stdio server apart from other transport failures.
+func describeCallError(client *core.MCPClient, err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Sprintf("timed out: %v", err)
+	}
+	if cmd, ok := core.AsCommandTransport(client.Transport()); ok {
+		select {
+		case <-cmd.Exited():
+			if exitErr := cmd.ExitError(); exitErr != nil {
+				return fmt.Sprintf("server crashed: %v", exitErr)
+			}
+			return "server exited"
+		case <-time.After(exitWait):
+		}
+	}
+	return err.Error()
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// newToolServer serves tools/list and tools/call over HTTP, with tools
+// exercising each failure the tools suite detects.
+func newToolServer(t *testing.T) *httptest.Server {
+	tools := []interface{}{
+		map[string]interface{}{"name": "good", "inputSchema": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"n": map[string]interface{}{"type": "integer", "minimum": 3}},
+			"required":   []interface{}{"n"},
+		}},
+		map[string]interface{}{"name": "badschema", "inputSchema": map[string]interface{}{"type": "strng"}},
+		map[string]interface{}{"name": "malformed", "inputSchema": map[string]interface{}{"type": "object"}},
+		map[string]interface{}{"name": "failing", "inputSchema": map[string]interface{}{"type": "object"}},
+		map[string]interface{}{"name": "slow", "inputSchema": map[string]interface{}{"type": "object"}},
+		map[string]interface{}{"name": "lookahead", "inputSchema": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"code": map[string]interface{}{"type": "string", "pattern": `^(?=.*\d).+$`}},
+		}},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req core.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.IsNotification() {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		resp := &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID}
+		switch req.Method {
+		case "initialize":
+			resp.Result = core.InitializeResult{ProtocolVersion: core.LatestProtocolVersion, ServerInfo: core.Implementation{Name: "tools", Version: "1.0.0"}}
+		case "tools/list":
+			resp.Result = map[string]interface{}{"tools": tools}
+		case "tools/call":
+			args, _ := req.Params["arguments"].(map[string]interface{})
+			switch req.Params["name"] {
+			case "lookahead":
+				resp.Result = map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "ok"}}}
+			case "good":
+				if n, _ := args["n"].(float64); n < 3 {
+					resp = core.NewErrorResponse(req.ID, core.InvalidParams, "n too small", nil)
+					break
+				}
+				resp.Result = map[string]interface{}{"content": []interface{}{map[string]interface{}{"type": "text", "text": "ok"}}}
+			case "malformed":
+				resp.Result = map[string]interface{}{"content": "ok"}
+			case "failing":
+				resp = core.NewErrorResponse(req.ID, core.InternalError, "boom", nil)
+			case "slow":
+				select {
+				case <-time.After(2 * time.Second):
+				case <-r.Context().Done():
+				}
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCollectTests_Tools(t *testing.T) {
+	srv := newToolServer(t)
+	opts := &TestOptions{TestTools: true, Timeout: 500 * time.Millisecond}
+	cfg := &core.MCPConfig{Name: "tools", Transport: core.Transport{Type: "rest", Options: map[string]any{"url": srv.URL}}}
+	report, err := CollectTests(opts, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := report.Suite("tools").Cases
+	if len(cases) != 7 {
+		t.Fatalf("expected list and 6 tool cases, got %d", len(cases))
+	}
+	want := []struct {
+		name    string
+		status  TestStatus
+		message string
+	}{
+		{"tools", StatusPassed, "Tools:"},
+		{"tools/call good", StatusPassed, `Tool good: called with {"n":3}`},
+		{"tools/call badschema", StatusFailed, `invalid inputSchema: invalid schema at #/type: unknown type "strng"`},
+		{"tools/call malformed", StatusFailed, "Tool malformed: malformed result"},
+		{"tools/call failing", StatusFailed, "tools/call failed: boom (code -32603)"},
+		{"tools/call slow", StatusError, "Tool slow: timed out"},
+		{"tools/call lookahead", StatusPassed, "ignoring unsupported inputSchema keywords: #/properties/code/pattern"},
+	}
+	for i, w := range want {
+		c := cases[i]
+		if c.Name != w.name || c.Status != w.status || !strings.Contains(c.Message, w.message) {
+			t.Errorf("case %d: got %s %s %q, want %s %s %q", i, c.Name, c.Status, c.Message, w.name, w.status, w.message)
+		}
+	}
+	if f := cases[3].Failures; len(f) != 1 || f[0] != "content must be an array, got string" {
+		t.Errorf("unexpected failures: %v", f)
+	}
+	if cases[4].Request == nil || cases[4].Response == nil {
+		t.Error("failed calls should record the request and response")
+	}
+	if err := report.Err(); err == nil || err.Error() != "4 of 7 tests failed" {
+		t.Errorf("unexpected result: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/generators"
+)
+
+// UpgradeOptions contains the flags of `mcpcli upgrade`.
+type UpgradeOptions struct {
+	// Dir is the directory of the project.
+	Dir string
+	// Reject leaves files whose merge conflicts as they are and writes the
+	// template changes to a .rej file next to them, instead of writing
+	// conflict markers.
+	Reject bool
+}
+
+// RunUpgrade renders a project created by generate with the templates of
+// this mcpcli and brings the changes into its files. Files left as they were
+// generated are replaced, files with local changes are three-way merged with
+// their generated content recorded in the manifest as the common ancestor,
+// and files deleted locally stay deleted. Where a merge fails, the file gets
+// conflict markers, or a .rej file when there is no recorded ancestor or
+// Reject is set. The manifest is then updated to the new templates.
+func RunUpgrade(opts *UpgradeOptions, w io.Writer) error {
+	if opts.Dir == "" {
+		opts.Dir = "."
+	}
+	config, err := LoadProject(opts.Dir)
+	if err != nil {
+		return err
+	}
+	generator, err := selectGenerator(config.Language)
+	if err != nil {
+		return err
+	}
+	manifest, err := LoadManifest(opts.Dir)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		fmt.Fprintf(w, "⚠️  %s not found: files changed since they were generated cannot be merged and get .rej files\n", manifestPath)
+		manifest = &Manifest{Output: config.Name, Files: map[string]string{}}
+	case err != nil:
+		return err
+	}
+	config.Output, config.Author, config.Description = manifest.Output, manifest.Author, manifest.Description
+	config.Version, config.CreatedAt = core.CLIVersion, time.Now()
+
+	files, err := generator.Render(config)
+	if err != nil {
+		return err
+	}
+	from := manifest.TemplateVersion
+	if from == "" {
+		from = "unknown"
+	}
+	fmt.Fprintf(w, "🔄 Upgrading %s from the templates of mcpcli %s to %s\n", config.Name, from, core.CLIVersion)
+
+	unresolved := 0
+	rendered := map[string]bool{}
+	for _, file := range files {
+		rendered[filepath.ToSlash(file.Path)] = true
+		resolved, err := upgradeFile(opts, manifest, file, w)
+		if err != nil {
+			return err
+		}
+		if !resolved {
+			unresolved++
+		}
+	}
+
+	upgraded := newManifest(config)
+	var stale []string
+	for path := range manifest.Files {
+		if !rendered[path] {
+			stale = append(stale, path)
+		}
+	}
+	sort.Strings(stale)
+	for _, path := range stale {
+		fmt.Fprintf(w, "⚠️  %s is no longer generated; remove it if it is unused\n", path)
+		manifest.forget(opts.Dir, path)
+	}
+	if err := upgraded.record(opts.Dir, files...); err != nil {
+		return err
+	}
+	if err := upgraded.save(opts.Dir); err != nil {
+		return err
+	}
+	if unresolved > 0 {
+		return fmt.Errorf("%d file(s) could not be merged, resolve them by hand", unresolved)
+	}
+	fmt.Fprintf(w, "✅ Upgraded %s to the templates of mcpcli %s\n", config.Name, core.CLIVersion)
+	return nil
+}
+
+// upgradeFile brings the regenerated content of a file into the project. It
+// reports false when the file was left with conflicts or a .rej file.
+func upgradeFile(opts *UpgradeOptions, manifest *Manifest, file generators.File, w io.Writer) (bool, error) {
+	current, err := os.ReadFile(filepath.Join(opts.Dir, file.Path))
+	recorded, known := manifest.Files[filepath.ToSlash(file.Path)]
+	switch {
+	case os.IsNotExist(err) && known:
+		fmt.Fprintf(w, "⏭️  Skipped %s, which was deleted\n", file.Path)
+		return true, nil
+	case os.IsNotExist(err):
+		if err := writeProjectFile(opts.Dir, file.Path, file.Content); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "✅ Created %s\n", file.Path)
+		return true, nil
+	case err != nil:
+		return false, fmt.Errorf("failed to read %s: %w", file.Path, err)
+	case bytes.Equal(current, file.Content):
+		return true, nil
+	case known && hashContent(current) == recorded:
+		if err := writeProjectFile(opts.Dir, file.Path, file.Content); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "✅ Updated %s\n", file.Path)
+		return true, nil
+	}
+
+	base, ok := manifest.base(opts.Dir, file.Path)
+	if !ok {
+		return false, writeReject(opts.Dir, file.Path, current, file.Content, w)
+	}
+	merged, conflicts := core.Merge3(base, current, file.Content, file.Path, "mcpcli "+core.CLIVersion)
+	switch {
+	case conflicts == 0:
+		if bytes.Equal(merged, current) {
+			return true, nil
+		}
+		if err := writeProjectFile(opts.Dir, file.Path, merged); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(w, "✅ Merged %s with its local changes\n", file.Path)
+		return true, nil
+	case opts.Reject:
+		return false, writeReject(opts.Dir, file.Path, base, file.Content, w)
+	}
+	if err := writeProjectFile(opts.Dir, file.Path, merged); err != nil {
+		return false, err
+	}
+	fmt.Fprintf(w, "⚠️  %s has %d conflict(s); resolve the conflict markers\n", file.Path, conflicts)
+	return false, nil
+}
+
+// writeReject leaves a file as is and writes the diff from old to new, the
+// template changes it did not get, to a .rej file next to it.
+func writeReject(dir, path string, old, new []byte, w io.Writer) error {
+	name := filepath.ToSlash(path)
+	diff := core.UnifiedDiff(old, new, "a/"+name, "b/"+name)
+	if err := writeProjectFile(dir, path+".rej", []byte(diff)); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "⚠️  %s could not be merged; apply the template changes in %s.rej by hand\n", path, path)
+	return nil
+}
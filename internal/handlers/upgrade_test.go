@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/core"
+	"github.com/aawadall/mcpcli/internal/generators"
+)
+
+// pretendOld pretends the templates of an older mcpcli rendered the files of
+// olds, by writing them to the project and recording them as generated.
+func pretendOld(t *testing.T, dir string, olds map[string]string) {
+	t.Helper()
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.TemplateVersion = "0.1.0"
+	for path, old := range olds {
+		file := generators.File{Path: path, Content: []byte(old)}
+		if err := m.record(dir, file); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, path), file.Content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.save(dir); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func generateGoProject(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "golang", Transport: "stdio", Output: dir}); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func readFile(t *testing.T, dir, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestGenerateProject_WritesManifest(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "python", Transport: "stdio", Output: dir, Author: "me"}); err != nil {
+		t.Fatal(err)
+	}
+	m, err := LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TemplateVersion != core.CLIVersion || m.Output != dir || m.Author != "me" || m.GeneratedAt.IsZero() {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	for path, content := range readTree(t, dir) {
+		if _, ok := m.Files[filepath.ToSlash(path)]; !ok {
+			t.Errorf("%s is not recorded", path)
+		} else if hashContent([]byte(content)) != m.Files[filepath.ToSlash(path)] {
+			t.Errorf("%s has a wrong hash", path)
+		}
+	}
+	if len(m.Files) == 0 {
+		t.Fatal("no file recorded")
+	}
+	for path := range m.Files {
+		if base, ok := m.base(dir, path); !ok || string(base) != readFile(t, dir, path) {
+			t.Errorf("base of %s was not kept", path)
+		}
+	}
+}
+
+func TestRunUpgrade(t *testing.T) {
+	dir := generateGoProject(t)
+	readme, client, mcp, gomod := "README.md", filepath.Join("pkg", "mcp", "client.go"), filepath.Join("pkg", "mcp", "mcp.go"), "go.mod"
+	current := map[string]string{}
+	for _, path := range []string{readme, client, mcp, gomod} {
+		current[path] = readFile(t, dir, path)
+	}
+	// The old templates lacked the first line of each file.
+	old := func(path string) string { return current[path][strings.Index(current[path], "\n")+1:] }
+	pretendOld(t, dir, map[string]string{readme: old(readme), client: old(client), mcp: old(mcp), gomod: old(gomod)})
+
+	// README.md is edited away from the template change, client.go on the
+	// lines the template changed, mcp.go is deleted and go.mod is untouched.
+	os.WriteFile(filepath.Join(dir, readme), []byte(old(readme)+"My notes\n"), 0644)
+	lines := strings.SplitN(old(client), "\n", 2)
+	clientEdit := "// mine\n" + lines[1]
+	os.WriteFile(filepath.Join(dir, client), []byte(clientEdit), 0644)
+	os.Remove(filepath.Join(dir, mcp))
+	m, _ := LoadManifest(dir)
+	m.Files["internal/legacy.go"] = hashContent(nil)
+	m.save(dir)
+
+	var out bytes.Buffer
+	err := RunUpgrade(&UpgradeOptions{Dir: dir}, &out)
+	if err == nil || !strings.Contains(err.Error(), "1 file(s) could not be merged") {
+		t.Fatalf("expected one unresolved file, got %v\n%s", err, out.String())
+	}
+	if got := readFile(t, dir, readme); got != current[readme]+"My notes\n" {
+		t.Errorf("README.md was not merged:\n%s", got)
+	}
+	if got := readFile(t, dir, gomod); got != current[gomod] {
+		t.Errorf("go.mod was not updated:\n%s", got)
+	}
+	if _, err := os.Stat(filepath.Join(dir, mcp)); !os.IsNotExist(err) {
+		t.Errorf("deleted %s was recreated", mcp)
+	}
+	if got := readFile(t, dir, client); !strings.HasPrefix(got, "<<<<<<< "+client+"\n// mine\n=======\n") || !strings.Contains(got, ">>>>>>> mcpcli "+core.CLIVersion+"\n") {
+		t.Errorf("expected conflict markers in %s:\n%s", client, got)
+	}
+	for _, want := range []string{"Merged README.md", "Updated go.mod", "Skipped " + mcp, client + " has 1 conflict(s)", "internal/legacy.go is no longer generated"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in the output:\n%s", want, out.String())
+		}
+	}
+
+	m, err = LoadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.TemplateVersion != core.CLIVersion || m.Output != dir {
+		t.Errorf("unexpected manifest: %+v", m)
+	}
+	if _, ok := m.Files["internal/legacy.go"]; ok {
+		t.Error("stale file kept in the manifest")
+	}
+	for path, content := range current {
+		if m.Files[filepath.ToSlash(path)] != hashContent([]byte(content)) {
+			t.Errorf("%s is not recorded with its new content", path)
+		}
+	}
+
+	// Upgrading again only finds the unresolved conflict, now a local change.
+	out.Reset()
+	os.WriteFile(filepath.Join(dir, client), []byte(clientEdit), 0644)
+	if err := RunUpgrade(&UpgradeOptions{Dir: dir}, &out); err != nil {
+		t.Fatalf("second upgrade: %v\n%s", err, out.String())
+	}
+	if got := readFile(t, dir, client); got != clientEdit {
+		t.Errorf("local change of %s was lost:\n%s", client, got)
+	}
+}
+
+func TestRunUpgrade_Reject(t *testing.T) {
+	path := "go.mod"
+	dir := generateGoProject(t)
+	current := readFile(t, dir, path)
+	pretendOld(t, dir, map[string]string{path: "module old\n" + current})
+	edited := "module mine\n" + current
+	os.WriteFile(filepath.Join(dir, path), []byte(edited), 0644)
+
+	var out bytes.Buffer
+	if err := RunUpgrade(&UpgradeOptions{Dir: dir, Reject: true}, &out); err == nil {
+		t.Fatalf("expected an error for the rejected file\n%s", out.String())
+	}
+	if got := readFile(t, dir, path); got != edited {
+		t.Errorf("rejected file was changed:\n%s", got)
+	}
+	if rej := readFile(t, dir, path+".rej"); !strings.HasPrefix(rej, "--- a/go.mod\n+++ b/go.mod\n@@ -1,") || !strings.Contains(rej, "\n-module old\n") {
+		t.Errorf("unexpected go.mod.rej:\n%s", rej)
+	}
+}
+
+func TestRunUpgrade_WithoutManifest(t *testing.T) {
+	dir := generateGoProject(t)
+	if err := os.RemoveAll(filepath.Join(dir, manifestDir)); err != nil {
+		t.Fatal(err)
+	}
+	readme := filepath.Join(dir, "README.md")
+	os.WriteFile(readme, []byte("mine\n"), 0644)
+
+	var out bytes.Buffer
+	if err := RunUpgrade(&UpgradeOptions{Dir: dir}, &out); err == nil {
+		t.Fatalf("expected an error for the unmerged file\n%s", out.String())
+	}
+	if got := readFile(t, dir, "README.md"); got != "mine\n" {
+		t.Errorf("README.md was changed:\n%s", got)
+	}
+	if rej := readFile(t, dir, "README.md.rej"); !strings.Contains(rej, "\n-mine\n") {
+		t.Errorf("unexpected README.md.rej:\n%s", rej)
+	}
+	if _, err := LoadManifest(dir); err != nil {
+		t.Errorf("manifest was not written: %v", err)
+	}
+}
+
+func TestRunUpgrade_AfterAdd(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "proj")
+	if err := GenerateProject(&GenerateOptions{Name: "proj", Language: "javascript", Transport: "stdio", Output: dir, Description: "demo"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, opts := range []*AddOptions{
+		{Dir: dir, Kind: "tool", Tool: core.Tool{Name: "echo"}},
+		{Dir: dir, Kind: "resource", Resource: core.Resource{Name: "notes", Type: "filesystem"}},
+		{Dir: dir, Kind: "capability", Capability: core.Capability{Name: "logging", Enabled: true}},
+	} {
+		if err := RunAdd(opts, &bytes.Buffer{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	before := readTree(t, dir)
+
+	var out bytes.Buffer
+	if err := RunUpgrade(&UpgradeOptions{Dir: dir}, &out); err != nil {
+		t.Fatalf("RunUpgrade: %v\n%s", err, out.String())
+	}
+	after := readTree(t, dir)
+	for path, content := range after {
+		if path == projectConfigPath {
+			if content != before[path] && !strings.Contains(out.String(), "Updated "+path) {
+				t.Errorf("config changed without being reported:\n%s", out.String())
+			}
+			continue
+		}
+		if before[path] != content {
+			t.Errorf("%s changed:\n%s", path, content)
+		}
+	}
+	if strings.Contains(out.String(), "⚠️") {
+		t.Errorf("unexpected warnings:\n%s", out.String())
+	}
+}
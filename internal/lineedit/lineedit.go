@@ -0,0 +1,290 @@
+// Package lineedit reads lines from a terminal with editing, history and tab
+// completion, for the interactive commands of mcpcli. When the input is not a
+// terminal lines are read as is.
+package lineedit
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrInterrupted is returned by ReadLine when the user presses Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// MaxHistory is the number of lines kept in the history.
+const MaxHistory = 1000
+
+// CompleteFunc returns the words that may replace the last word of line, the
+// text before the cursor.
+type CompleteFunc func(line string) []string
+
+// Editor reads lines from its input.
+type Editor struct {
+	// Prompt is printed before each line read from a terminal.
+	Prompt string
+	// Complete, when set, is called on Tab.
+	Complete CompleteFunc
+
+	in      *bufio.Reader
+	fd      int
+	out     io.Writer
+	history []string
+}
+
+// New creates an editor reading from in and echoing to out. Editing is only
+// enabled when in is a terminal.
+func New(in io.Reader, out io.Writer) *Editor {
+	e := &Editor{in: bufio.NewReader(in), fd: -1, out: out}
+	if f, ok := in.(*os.File); ok && isTerminal(int(f.Fd())) {
+		e.fd = int(f.Fd())
+	}
+	return e
+}
+
+// Interactive reports whether the input is a terminal.
+func (e *Editor) Interactive() bool {
+	return e.fd >= 0
+}
+
+// History returns the lines added to the history, oldest first.
+func (e *Editor) History() []string {
+	return append([]string(nil), e.history...)
+}
+
+// AddHistory appends a line to the history. Blank lines and repetitions of
+// the last line are skipped.
+func (e *Editor) AddHistory(line string) {
+	if strings.TrimSpace(line) == "" || (len(e.history) > 0 && e.history[len(e.history)-1] == line) {
+		return
+	}
+	e.history = append(e.history, line)
+	if len(e.history) > MaxHistory {
+		e.history = e.history[len(e.history)-MaxHistory:]
+	}
+}
+
+// ReadLine returns the next line, without its line ending. It returns io.EOF
+// at the end of the input, or when Ctrl-D is pressed on an empty line.
+func (e *Editor) ReadLine() (string, error) {
+	if !e.Interactive() {
+		line, err := e.in.ReadString('\n')
+		if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+			return "", err
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+	restore, err := makeRaw(e.fd)
+	if err != nil {
+		return "", fmt.Errorf("failed to set terminal mode: %w", err)
+	}
+	defer restore()
+	return e.edit()
+}
+
+// lineState is the line being edited.
+type lineState struct {
+	buf    []rune
+	pos    int
+	recall int
+	// draft keeps the edited line while browsing the history.
+	draft []rune
+}
+
+// edit reads keys in raw mode until the line is accepted.
+func (e *Editor) edit() (string, error) {
+	s := &lineState{recall: len(e.history)}
+	e.refresh(s)
+	for {
+		r, _, err := e.in.ReadRune()
+		if err != nil {
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			fmt.Fprint(e.out, "\r\n")
+			return string(s.buf), nil
+		case 3: // Ctrl-C
+			fmt.Fprint(e.out, "^C\r\n")
+			return "", ErrInterrupted
+		case 4: // Ctrl-D
+			if len(s.buf) == 0 {
+				fmt.Fprint(e.out, "\r\n")
+				return "", io.EOF
+			}
+			s.deleteAt(s.pos)
+		case 127, 8: // Backspace
+			if s.pos > 0 {
+				s.pos--
+				s.deleteAt(s.pos)
+			}
+		case 1: // Ctrl-A
+			s.pos = 0
+		case 5: // Ctrl-E
+			s.pos = len(s.buf)
+		case 2: // Ctrl-B
+			s.move(-1)
+		case 6: // Ctrl-F
+			s.move(1)
+		case 11: // Ctrl-K
+			s.buf = s.buf[:s.pos]
+		case 21: // Ctrl-U
+			s.buf = append([]rune(nil), s.buf[s.pos:]...)
+			s.pos = 0
+		case 16: // Ctrl-P
+			e.recall(s, -1)
+		case 14: // Ctrl-N
+			e.recall(s, 1)
+		case '\t':
+			e.complete(s)
+		case 27:
+			e.escape(s)
+		default:
+			if r >= ' ' {
+				s.insert([]rune{r})
+			}
+		}
+		e.refresh(s)
+	}
+}
+
+// escape handles the arrow, Home, End and Delete key sequences.
+func (e *Editor) escape(s *lineState) {
+	b, err := e.in.ReadByte()
+	if err != nil || (b != '[' && b != 'O') {
+		return
+	}
+	key, err := e.in.ReadByte()
+	if err != nil {
+		return
+	}
+	switch key {
+	case 'A':
+		e.recall(s, -1)
+	case 'B':
+		e.recall(s, 1)
+	case 'C':
+		s.move(1)
+	case 'D':
+		s.move(-1)
+	case 'H':
+		s.pos = 0
+	case 'F':
+		s.pos = len(s.buf)
+	case '1', '3', '4', '7', '8':
+		// VT sequences end with '~'.
+		if next, err := e.in.ReadByte(); err != nil || next != '~' {
+			return
+		}
+		switch key {
+		case '1', '7':
+			s.pos = 0
+		case '4', '8':
+			s.pos = len(s.buf)
+		case '3':
+			s.deleteAt(s.pos)
+		}
+	}
+}
+
+func (s *lineState) insert(runes []rune) {
+	buf := make([]rune, 0, len(s.buf)+len(runes))
+	buf = append(buf, s.buf[:s.pos]...)
+	buf = append(buf, runes...)
+	s.buf = append(buf, s.buf[s.pos:]...)
+	s.pos += len(runes)
+}
+
+func (s *lineState) deleteAt(i int) {
+	if i < len(s.buf) {
+		s.buf = append(s.buf[:i], s.buf[i+1:]...)
+	}
+}
+
+func (s *lineState) move(delta int) {
+	s.pos = min(max(s.pos+delta, 0), len(s.buf))
+}
+
+// recall replaces the line with an older (delta -1) or newer (delta 1)
+// history entry. Moving past the newest entry restores the edited line.
+func (e *Editor) recall(s *lineState, delta int) {
+	next := s.recall + delta
+	if next < 0 || next > len(e.history) {
+		return
+	}
+	if s.recall == len(e.history) {
+		s.draft = s.buf
+	}
+	s.recall = next
+	if next == len(e.history) {
+		s.buf = s.draft
+	} else {
+		s.buf = []rune(e.history[next])
+	}
+	s.pos = len(s.buf)
+}
+
+// complete extends the word before the cursor with the longest prefix shared
+// by the completions, and lists them when there is nothing to add.
+func (e *Editor) complete(s *lineState) {
+	if e.Complete == nil {
+		return
+	}
+	before := string(s.buf[:s.pos])
+	word := []rune(before[strings.LastIndex(before, " ")+1:])
+	var candidates []string
+	for _, c := range e.Complete(before) {
+		if strings.HasPrefix(c, string(word)) {
+			candidates = append(candidates, c)
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		s.insert([]rune(candidates[0])[len(word):])
+		if s.pos == len(s.buf) {
+			s.insert([]rune{' '})
+		}
+		return
+	}
+	if prefix := []rune(commonPrefix(candidates)); len(prefix) > len(word) {
+		s.insert(prefix[len(word):])
+		return
+	}
+	sort.Strings(candidates)
+	fmt.Fprintf(e.out, "\r\n%s\r\n", strings.Join(candidates, "  "))
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			_, size := lastRune(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
+
+func lastRune(s string) (rune, int) {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return 0, 0
+	}
+	r := runes[len(runes)-1]
+	return r, len(string(r))
+}
+
+// refresh redraws the prompt and the line and places the cursor.
+func (e *Editor) refresh(s *lineState) {
+	line := "\r" + e.Prompt + string(s.buf) + "\x1b[K"
+	if back := len(s.buf) - s.pos; back > 0 {
+		line += fmt.Sprintf("\x1b[%dD", back)
+	}
+	fmt.Fprint(e.out, line)
+}
@@ -0,0 +1,118 @@
+package lineedit
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// editKeys runs the raw-mode editor over the given keystrokes.
+func editKeys(e *Editor, keys string) (string, error) {
+	e.in.Reset(strings.NewReader(keys))
+	return e.edit()
+}
+
+func TestEditor_Editing(t *testing.T) {
+	e := New(strings.NewReader(""), io.Discard)
+	for _, tc := range []struct {
+		name, keys, want string
+	}{
+		{"typing", "hello\r", "hello"},
+		{"backspace", "hel\x7f\x7fello\r", "hello"},
+		{"arrows", "hllo\x1b[D\x1b[D\x1b[D\x1b[D\x1b[Ce\r", "hello"},
+		{"home and end", "ello\x1b[Hh\x1b[F!\r", "hello!"},
+		{"ctrl keys", "world\x01hello \x05!\r", "hello world!"},
+		{"kill", "hello world\x02\x02\x02\x02\x02\x0b\r", "hello "},
+		{"delete", "hxello\x01\x1b[C\x1b[3~\r", "hello"},
+		{"unicode", "héllo\x7fo\r", "héllo"},
+	} {
+		got, err := editKeys(e, tc.keys)
+		if err != nil || got != tc.want {
+			t.Errorf("%s: got %q, %v, want %q", tc.name, got, err, tc.want)
+		}
+	}
+}
+
+func TestEditor_ControlKeys(t *testing.T) {
+	e := New(strings.NewReader(""), io.Discard)
+	if _, err := editKeys(e, "abc\x03"); !errors.Is(err, ErrInterrupted) {
+		t.Errorf("Ctrl-C: expected ErrInterrupted, got %v", err)
+	}
+	if _, err := editKeys(e, "\x04"); err != io.EOF {
+		t.Errorf("Ctrl-D: expected io.EOF, got %v", err)
+	}
+	if got, err := editKeys(e, "ab\x02\x04\r"); err != nil || got != "a" {
+		t.Errorf("Ctrl-D on a line deletes: got %q, %v", got, err)
+	}
+}
+
+func TestEditor_History(t *testing.T) {
+	e := New(strings.NewReader(""), io.Discard)
+	for _, line := range []string{"first", "second", "second", " "} {
+		e.AddHistory(line)
+	}
+	if got := strings.Join(e.History(), ","); got != "first,second" {
+		t.Fatalf("unexpected history %q", got)
+	}
+	for _, tc := range []struct {
+		keys, want string
+	}{
+		{"\x1b[A\r", "second"},
+		{"\x1b[A\x1b[A\r", "first"},
+		{"\x1b[A\x1b[A\x1b[A\x1b[B\r", "second"},
+		{"draft\x1b[A\x1b[B\r", "draft"},
+		{"\x10\x10!\r", "first!"},
+	} {
+		if got, err := editKeys(e, tc.keys); err != nil || got != tc.want {
+			t.Errorf("%q: got %q, %v, want %q", tc.keys, got, err, tc.want)
+		}
+	}
+}
+
+func TestEditor_Complete(t *testing.T) {
+	var out bytes.Buffer
+	e := New(strings.NewReader(""), &out)
+	e.Complete = func(line string) []string {
+		if strings.HasPrefix(line, "call ") {
+			return []string{"echo", "search_docs", "search_code"}
+		}
+		return []string{"call", "tools", "resources", "read"}
+	}
+	for _, tc := range []struct {
+		keys, want string
+	}{
+		{"c\t\r", "call "},
+		{"call e\t\r", "call echo "},
+		{"call s\t\r", "call search_"},
+		{"r\t\r", "re"},
+		{"x\t\r", "x"},
+	} {
+		if got, err := editKeys(e, tc.keys); err != nil || got != tc.want {
+			t.Errorf("%q: got %q, %v, want %q", tc.keys, got, err, tc.want)
+		}
+	}
+	out.Reset()
+	if _, err := editKeys(e, "call search_\t\r"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "search_code  search_docs") {
+		t.Errorf("expected the candidates to be listed, got %q", out.String())
+	}
+}
+
+func TestEditor_ReadLineNotTerminal(t *testing.T) {
+	e := New(strings.NewReader("tools\r\nread file:///a\nlast"), io.Discard)
+	if e.Interactive() {
+		t.Fatal("a reader is not a terminal")
+	}
+	for _, want := range []string{"tools", "read file:///a", "last"} {
+		if got, err := e.ReadLine(); err != nil || got != want {
+			t.Errorf("got %q, %v, want %q", got, err, want)
+		}
+	}
+	if _, err := e.ReadLine(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+// Package mcptest provides a small MCP server used by the tests of mcpcli.
+//
+// Tests run it as a subprocess by re-executing the test binary: the package's
+// TestMain calls RunIfRequested and test cases use Command as the stdio
+// transport command.
+package mcptest
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/aawadall/mcpcli/internal/core"
+)
+
+// EnvVar selects the server mode when the test binary is re-executed.
+const EnvVar = "MCPCLI_TEST_SERVER"
+
+// Server names and contents served to clients.
+const (
+	ServerName    = "mcptest"
+	EchoTool      = "echo"
+	GreetingURI   = "file:///greeting.txt"
+	GreetingText  = "hello"
+	ResourceError = core.ResourceNotFound
+)
+
+// RunIfRequested serves MCP over stdin/stdout and exits when the process was
+// started by Command. It must be called from TestMain before m.Run.
+func RunIfRequested() {
+	if os.Getenv(EnvVar) == "" {
+		return
+	}
+	if err := Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// Command returns a stdio transport command starting the test binary as an
+// MCP server.
+func Command(t testing.TB) string {
+	t.Helper()
+	t.Setenv(EnvVar, "1")
+	return os.Args[0] + " -test.run=^$"
+}
+
+// Serve answers newline-delimited JSON-RPC requests until r is exhausted.
+// While a client is subscribed to GreetingURI, each tools/call is preceded by
+// a notification that the greeting was updated.
+func Serve(r io.Reader, w io.Writer) error {
+	subscribed := false
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var req core.Request
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			writeMessage(w, core.NewErrorResponse(nil, core.ParseError, "Parse error", nil))
+			continue
+		}
+		if req.Method == "" || req.IsNotification() {
+			continue
+		}
+		resp := Handle(&req)
+		if resp.Error == nil {
+			switch req.Method {
+			case "resources/subscribe", "resources/unsubscribe":
+				if req.Params["uri"] == GreetingURI {
+					subscribed = req.Method == "resources/subscribe"
+				}
+			case "tools/call":
+				if subscribed {
+					writeMessage(w, core.NewNotification(core.ResourceUpdatedNotification, map[string]interface{}{"uri": GreetingURI}))
+				}
+			}
+		}
+		writeMessage(w, resp)
+	}
+	return scanner.Err()
+}
+
+func writeMessage(w io.Writer, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
+
+// Handle answers a single request.
+func Handle(req *core.Request) *core.Response {
+	result := func(v interface{}) *core.Response {
+		return &core.Response{JSONRPC: core.JSONRPCVersion, ID: req.ID, Result: v}
+	}
+	switch req.Method {
+	case "initialize":
+		version, _ := req.Params["protocolVersion"].(string)
+		if !core.IsSupportedProtocolVersion(version) {
+			version = core.LatestProtocolVersion
+		}
+		return result(core.InitializeResult{
+			ProtocolVersion: version,
+			Capabilities: core.ServerCapabilities{
+				Resources: &core.ServerResourcesCapability{Subscribe: true},
+				Tools:     &core.ServerToolsCapability{},
+			},
+			ServerInfo: core.Implementation{Name: ServerName, Version: "1.0.0"},
+		})
+	case "ping":
+		return result(map[string]interface{}{})
+	case "tools/list":
+		return result(map[string]interface{}{"tools": []interface{}{
+			map[string]interface{}{
+				"name":        EchoTool,
+				"description": "Echoes the message back",
+				"inputSchema": map[string]interface{}{
+					"type":       "object",
+					"properties": map[string]interface{}{"message": map[string]interface{}{"type": "string"}},
+					"required":   []interface{}{"message"},
+				},
+			},
+		}})
+	case "tools/call":
+		name, _ := req.Params["name"].(string)
+		if name != EchoTool {
+			return core.NewErrorResponse(req.ID, core.InvalidParams, fmt.Sprintf("Unknown tool: %s", name), nil)
+		}
+		args, _ := req.Params["arguments"].(map[string]interface{})
+		message, ok := args["message"].(string)
+		if !ok {
+			return core.NewErrorResponse(req.ID, core.InvalidParams, "message must be a string", nil)
+		}
+		return result(map[string]interface{}{
+			"content": []interface{}{map[string]interface{}{"type": "text", "text": message}},
+		})
+	case "resources/list":
+		return result(map[string]interface{}{"resources": []interface{}{
+			map[string]interface{}{"uri": GreetingURI, "name": "greeting", "mimeType": "text/plain"},
+		}})
+	case "resources/subscribe", "resources/unsubscribe":
+		if _, ok := req.Params["uri"].(string); !ok {
+			return core.NewErrorResponse(req.ID, core.InvalidParams, "uri must be a string", nil)
+		}
+		return result(map[string]interface{}{})
+	case "resources/read":
+		uri, ok := req.Params["uri"].(string)
+		if !ok {
+			return core.NewErrorResponse(req.ID, core.InvalidParams, "uri must be a string", nil)
+		}
+		if uri != GreetingURI {
+			return core.NewErrorResponse(req.ID, ResourceError, "Resource not found", map[string]interface{}{"uri": uri})
+		}
+		return result(map[string]interface{}{"contents": []interface{}{
+			map[string]interface{}{"uri": GreetingURI, "mimeType": "text/plain", "text": GreetingText},
+		}})
+	default:
+		return core.NewErrorResponse(req.ID, core.MethodNotFound, fmt.Sprintf("Method not found: %s", req.Method), nil)
+	}
+}
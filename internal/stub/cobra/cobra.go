@@ -1,6 +1,10 @@
 package cobra
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
+)
 
 // Command is a lightweight replacement for the real cobra.Command used in tests.
 type Command struct {
@@ -29,12 +33,39 @@ func (c *Command) SetArgs(a []string) {
 				i++
 			}
 			fs.values[name] = val
+			fs.changed[name] = true
 			if p, ok := fs.strVars[name]; ok {
 				*p = val
 			}
 			if b, ok := fs.boolVars[name]; ok {
 				*b = val == "true"
 			}
+			if d, ok := fs.durVars[name]; ok {
+				if parsed, err := time.ParseDuration(val); err == nil {
+					*d = parsed
+				}
+			}
+			if n, ok := fs.intVars[name]; ok {
+				if parsed, err := strconv.Atoi(val); err == nil {
+					*n = parsed
+				}
+			}
+			if l, ok := fs.sliceVars[name]; ok {
+				*l = append(*l, strings.Split(val, ",")...)
+			}
+			if l, ok := fs.arrayVars[name]; ok {
+				*l = append(*l, val)
+			}
+			if n, ok := fs.int64Vars[name]; ok {
+				if parsed, err := strconv.ParseInt(val, 10, 64); err == nil {
+					*n = parsed
+				}
+			}
+			if n, ok := fs.floatVars[name]; ok {
+				if parsed, err := strconv.ParseFloat(val, 64); err == nil {
+					*n = parsed
+				}
+			}
 		}
 	}
 }
@@ -62,7 +93,7 @@ func (c *Command) Execute() error {
 
 func (c *Command) Flags() *FlagSet {
 	if c.flags == nil {
-		c.flags = &FlagSet{values: map[string]string{}}
+		c.flags = &FlagSet{values: map[string]string{}, changed: map[string]bool{}}
 	}
 	return c.flags
 }
@@ -88,9 +119,16 @@ func (c *Command) Name() string {
 
 // FlagSet is a simple map based flag set supporting StringVarP, BoolVar and lookup.
 type FlagSet struct {
-	values   map[string]string
-	strVars  map[string]*string
-	boolVars map[string]*bool
+	values    map[string]string
+	strVars   map[string]*string
+	boolVars  map[string]*bool
+	durVars   map[string]*time.Duration
+	intVars   map[string]*int
+	int64Vars map[string]*int64
+	sliceVars map[string]*[]string
+	floatVars map[string]*float64
+	arrayVars map[string]*[]string
+	changed   map[string]bool
 }
 
 func (f *FlagSet) StringVarP(p *string, name, shorthand, value, usage string) {
@@ -133,6 +171,98 @@ func (f *FlagSet) BoolVarP(p *bool, name, shorthand string, value bool, usage st
 	f.BoolVar(p, name, value, usage)
 }
 
+// DurationVar defines a time.Duration flag.
+func (f *FlagSet) DurationVar(p *time.Duration, name string, value time.Duration, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.durVars == nil {
+		f.durVars = map[string]*time.Duration{}
+	}
+	f.values[name] = value.String()
+	if p != nil {
+		*p = value
+		f.durVars[name] = p
+	}
+}
+
+// IntVar defines an int flag.
+func (f *FlagSet) IntVar(p *int, name string, value int, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.intVars == nil {
+		f.intVars = map[string]*int{}
+	}
+	f.values[name] = strconv.Itoa(value)
+	if p != nil {
+		*p = value
+		f.intVars[name] = p
+	}
+}
+
+// Int64Var defines an int64 flag.
+func (f *FlagSet) Int64Var(p *int64, name string, value int64, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.int64Vars == nil {
+		f.int64Vars = map[string]*int64{}
+	}
+	f.values[name] = strconv.FormatInt(value, 10)
+	if p != nil {
+		*p = value
+		f.int64Vars[name] = p
+	}
+}
+
+// Float64Var defines a float64 flag.
+func (f *FlagSet) Float64Var(p *float64, name string, value float64, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.floatVars == nil {
+		f.floatVars = map[string]*float64{}
+	}
+	f.values[name] = strconv.FormatFloat(value, 'g', -1, 64)
+	if p != nil {
+		*p = value
+		f.floatVars[name] = p
+	}
+}
+
+// StringSliceVar defines a string slice flag which may be repeated or hold
+// comma-separated values.
+func (f *FlagSet) StringSliceVar(p *[]string, name string, value []string, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.sliceVars == nil {
+		f.sliceVars = map[string]*[]string{}
+	}
+	f.values[name] = strings.Join(value, ",")
+	if p != nil {
+		*p = append([]string(nil), value...)
+		f.sliceVars[name] = p
+	}
+}
+
+// StringArrayVar defines a repeatable string flag whose values are kept as
+// is, commas included.
+func (f *FlagSet) StringArrayVar(p *[]string, name string, value []string, usage string) {
+	if f.values == nil {
+		f.values = map[string]string{}
+	}
+	if f.arrayVars == nil {
+		f.arrayVars = map[string]*[]string{}
+	}
+	f.values[name] = strings.Join(value, ",")
+	if p != nil {
+		*p = append([]string(nil), value...)
+		f.arrayVars[name] = p
+	}
+}
+
 func (f *FlagSet) Lookup(name string) *Flag {
 	if f.values == nil {
 		return nil
@@ -150,6 +280,9 @@ func (f *FlagSet) GetString(name string) (string, error) {
 	return f.values[name], nil
 }
 
+// Changed reports whether the flag was set on the command line.
+func (f *FlagSet) Changed(name string) bool { return f.changed[name] }
+
 type Flag struct{ Name string }
 
 // MaximumNArgs returns a validator function for arguments.
@@ -157,6 +290,11 @@ func MaximumNArgs(n int) func(cmd *Command, args []string) error {
 	return func(cmd *Command, args []string) error { return nil }
 }
 
+// ExactArgs returns a validator function for arguments.
+func ExactArgs(n int) func(cmd *Command, args []string) error {
+	return func(cmd *Command, args []string) error { return nil }
+}
+
 func contains(slice []string, item string) bool {
 	for _, s := range slice {
 		if s == item {